@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completionItem describes a subcommand or flag offered by shell completion.
+type completionItem struct {
+	name string
+	help string
+	arg  string // for flags: "" when the flag takes no value, "file" for a path, otherwise a value hint
+}
+
+var completionCommands = []completionItem{
+	{name: "init", help: "create a skeleton template"},
+	{name: "lint", help: "check a template for common authoring mistakes"},
+	{name: "verify", help: "list generated files modified since generation"},
+	{name: "update", help: "merge the latest template version into a project"},
+	{name: "changelog", help: "list variable changes between two template versions"},
+	{name: "render", help: "render a template string with the given answers"},
+	{name: "functions", help: "list the functions available in templates"},
+	{name: "docs", help: "print a table of a template's variables"},
+	{name: "cache", help: "list or clean cached git templates"},
+	{name: "list", help: "list registered templates"},
+	{name: "add", help: "register a template under a short name"},
+	{name: "remove", help: "remove a registered template"},
+}
+
+var completionFlags = []completionItem{
+	{name: "answer", help: "answer a variable without prompting", arg: "key=value"},
+	{name: "answers", help: "pre-fill variables from a YAML or JSON file", arg: "file"},
+	{name: "atomic", help: "write nothing unless every file renders"},
+	{name: "atomic-dir", help: "generate in a staging directory moved into place on success"},
+	{name: "changed-since", help: "render only template files changed since a ref", arg: "ref"},
+	{name: "config", help: "user settings file", arg: "file"},
+	{name: "dry-run", help: "print the planned changes without writing anything"},
+	{name: "dump-context", help: "write the render context of a failed run", arg: "file"},
+	{name: "explain", help: "show why each template file was rendered or skipped"},
+	{name: "export-answers", help: "write the effective answers to a file", arg: "file"},
+	{name: "force", help: "overwrite existing files that differ from the template"},
+	{name: "incremental", help: "only write files whose content changed"},
+	{name: "init-git", help: "initialize a git repository in the output"},
+	{name: "initial-commit", help: "initialize a git repository and commit the output", arg: "message"},
+	{name: "interactive-conflicts", help: "ask before overwriting each differing file"},
+	{name: "keep-going", help: "report every render error instead of stopping at the first"},
+	{name: "manifest", help: "write checksums of generated files"},
+	{name: "max-file-size", help: "copy larger files without rendering them", arg: "size"},
+	{name: "only", help: "render only tagged files with these tags", arg: "tags"},
+	{name: "skip", help: "leave out files with these tags", arg: "tags"},
+	{name: "skip-existing", help: "keep existing files that differ from the template"},
+	{name: "prompt-style", help: "stepped or compact prompts", arg: "style"},
+	{name: "prompt-timeout", help: "abort when a prompt gets no answer in time", arg: "duration"},
+	{name: "quick", help: "accept defaults for advanced variables"},
+	{name: "record", help: "write the answers to a file after prompting", arg: "file"},
+	{name: "ref", help: "clone a git template at a branch, tag or commit", arg: "ref"},
+	{name: "refresh", help: "clone a git template again instead of using the cache"},
+	{name: "no-cache", help: "clone a git template without using the cache"},
+	{name: "no-hooks", help: "run no hooks and no git init"},
+	{name: "require-clean", help: "refuse to generate into a dirty git tree"},
+	{name: "resolve-only", help: "print where the template resolves to"},
+	{name: "template-version", help: "compare the cached template with upstream"},
+	{name: "verbose", help: "show where each answer came from"},
+	{name: "version-file", help: "record template provenance in the output", arg: "file"},
+	{name: "safe", help: "no hooks, no network, strict file names"},
+	{name: "strict", help: "reject unknown keys in kick.yaml"},
+	{name: "subdir", help: "use the template in a subdirectory of the source", arg: "dir"},
+	{name: "shell-completion", help: "print a shell completion script", arg: "bash|zsh|fish"},
+}
+
+// writeCompletion writes the completion script for shell to w.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		writeZshCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell %q, must be one of [bash, zsh, fish]", shell)
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer) {
+	var commands, flags, fileFlags []string
+	for _, c := range completionCommands {
+		commands = append(commands, c.name)
+	}
+	for _, f := range completionFlags {
+		flags = append(flags, "--"+f.name)
+		if f.arg == "file" {
+			fileFlags = append(fileFlags, "--"+f.name)
+		}
+	}
+	flags = append(flags, "-V")
+
+	_, _ = fmt.Fprintf(w, `# bash completion for kick
+_kick() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    case "$prev" in
+        %s)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return
+            ;;
+        --shell-completion)
+            COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+            return
+            ;;
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    elif [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur") $(compgen -d -- "$cur"))
+    else
+        COMPREPLY=($(compgen -d -- "$cur"))
+    fi
+}
+complete -o default -F _kick kick
+`, strings.Join(fileFlags, "|"), strings.Join(flags, " "), strings.Join(commands, " "))
+}
+
+func writeZshCompletion(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "#compdef kick\n\n_kick() {\n    local -a commands\n    commands=(")
+	for _, c := range completionCommands {
+		_, _ = fmt.Fprintf(w, "        '%s:%s'\n", c.name, c.help)
+	}
+	_, _ = fmt.Fprintln(w, "    )\n\n    _arguments -s \\")
+	for _, f := range completionFlags {
+		switch f.arg {
+		case "":
+			_, _ = fmt.Fprintf(w, "        '--%s[%s]' \\\n", f.name, f.help)
+		case "file":
+			_, _ = fmt.Fprintf(w, "        '--%s=[%s]:file:_files' \\\n", f.name, f.help)
+		case "bash|zsh|fish":
+			_, _ = fmt.Fprintf(w, "        '--%s=[%s]:shell:(bash zsh fish)' \\\n", f.name, f.help)
+		default:
+			_, _ = fmt.Fprintf(w, "        '*--%s=[%s]:%s:' \\\n", f.name, f.help, f.arg)
+		}
+	}
+	_, _ = fmt.Fprint(w, `        '-V[compare the cached template with upstream]' \
+        '1: :->first' \
+        '*:directory:_files -/'
+
+    case $state in
+        first)
+            _describe 'command' commands
+            _files -/
+            ;;
+    esac
+}
+
+_kick "$@"
+`)
+}
+
+func writeFishCompletion(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "# fish completion for kick")
+	for _, c := range completionCommands {
+		_, _ = fmt.Fprintf(w, "complete -c kick -n __fish_use_subcommand -a %s -d '%s'\n", c.name, c.help)
+	}
+	for _, f := range completionFlags {
+		switch f.arg {
+		case "":
+			_, _ = fmt.Fprintf(w, "complete -c kick -l %s -d '%s'\n", f.name, f.help)
+		case "file":
+			_, _ = fmt.Fprintf(w, "complete -c kick -l %s -r -F -d '%s'\n", f.name, f.help)
+		case "bash|zsh|fish":
+			_, _ = fmt.Fprintf(w, "complete -c kick -l %s -x -a 'bash zsh fish' -d '%s'\n", f.name, f.help)
+		default:
+			_, _ = fmt.Fprintf(w, "complete -c kick -l %s -x -d '%s'\n", f.name, f.help)
+		}
+	}
+	_, _ = fmt.Fprintln(w, "complete -c kick -s V -d 'compare the cached template with upstream'")
+}
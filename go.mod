@@ -6,6 +6,7 @@ require (
 	github.com/go-git/go-git/v5 v5.16.2
 	github.com/stretchr/testify v1.10.0
 	github.com/yarlson/tap v0.6.1
+	golang.org/x/term v0.34.0
 	golang.org/x/text v0.28.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -34,6 +35,8 @@ require (
 	golang.org/x/crypto v0.41.0 // indirect
 	golang.org/x/net v0.43.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
-	golang.org/x/term v0.34.0 // indirect
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 )
+
+// tap v0.6.1 does not build for Windows; third_party/tap is a patched copy
+replace github.com/yarlson/tap => ./third_party/tap
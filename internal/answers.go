@@ -0,0 +1,183 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AnswersFile is the name of the answers file written into the output directory alongside the
+// manifest, for use with --answers. kick itself reuses the answers recorded in StateFile.
+const AnswersFile = ".kick-answers.yaml"
+
+// ParseAnswer splits a key=value answer given on the command line
+func ParseAnswer(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid answer %q, expected key=value", s)
+	}
+	return key, value, nil
+}
+
+// LoadAnswersFile reads variable values from a YAML or JSON file, such as one written by --export-answers.
+// A float whose text would not survive parsing, such as 1.20 or 1e3, is kept as that text, so
+// a string variable gets it as written and coerceFileAnswers converts it for a number variable.
+func LoadAnswersFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read answers: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse answers %s: %w", path, err)
+	}
+	values := make(map[string]any)
+	if len(doc.Content) == 0 {
+		return values, nil
+	}
+	if err := doc.Decode(&values); err != nil {
+		return nil, fmt.Errorf("parse answers %s: %w", path, err)
+	}
+
+	content := doc.Content[0].Content
+	for i := 0; i+1 < len(content); i += 2 {
+		key, value := content[i], content[i+1]
+		if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!float" {
+			continue
+		}
+		if f, ok := values[key.Value].(float64); ok && strconv.FormatFloat(f, 'f', -1, 64) != value.Value {
+			values[key.Value] = value.Value
+		}
+	}
+	return values, nil
+}
+
+// previousAnswers reads the answers a prior run recorded in the state file in dir, the same
+// answers kick update replays. Only declared variables whose value is still valid are kept, so
+// answers the template no longer accepts are prompted again. A missing state file yields no answers.
+func previousAnswers(dir string, variables map[string]Variable) (map[string]any, error) {
+	if _, err := os.Stat(filepath.Join(dir, StateFile)); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	state, err := LoadState(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := coerceFileAnswers(variables, state.Answers)
+	values := make(map[string]any, len(saved))
+	for name, value := range saved {
+		variable, ok := variables[name]
+		if !ok || variable.Validate(value) != nil {
+			continue
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// saveAnswers writes the answers of a run into dir
+func saveAnswers(dir string, variables map[string]Variable, values map[string]any) error {
+	return writeAnswersFile(filepath.Join(dir, AnswersFile), storedAnswers(variables, values))
+}
+
+// storedAnswers returns the answers of a run worth keeping, leaving out the implicit values
+// kick recomputes on every run and secrets
+func storedAnswers(variables map[string]Variable, values map[string]any) map[string]any {
+	answers := make(map[string]any, len(values))
+	for name, value := range withoutSecrets(variables, values) {
+		if !strings.HasPrefix(name, "_") {
+			answers[name] = value
+		}
+	}
+	return answers
+}
+
+// writeAnswersFile writes the effective template context to path as YAML
+func writeAnswersFile(path string, values map[string]any) error {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("marshal answers: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write answers: %w", err)
+	}
+	return nil
+}
+
+// coerceAnswers converts raw string answers to the types of their variables.
+// Answers for undeclared variables are kept as strings.
+func coerceAnswers(variables map[string]Variable, raw map[string]string) (map[string]any, error) {
+	values := make(map[string]any, len(raw))
+	for name, str := range raw {
+		variable, ok := variables[name]
+		if !ok {
+			values[name] = str
+			continue
+		}
+
+		value, err := coerceValue(variable, str)
+		if err != nil {
+			return nil, fmt.Errorf("answer %q: %w", name, err)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// coerceFileAnswers converts scalar values read from an answers file to the type of their
+// variable when YAML typed them differently, e.g. an unquoted `go_version: 1.24` for a string
+// variable or `enabled: "yes"` for a boolean. Values that already fit, lists, maps and values
+// that cannot be converted are kept as they are for validation to report.
+func coerceFileAnswers(variables map[string]Variable, values map[string]any) map[string]any {
+	coerced := make(map[string]any, len(values))
+	for name, value := range values {
+		coerced[name] = value
+		variable, ok := variables[name]
+		if !ok || value == nil || variable.Validate(value) == nil {
+			continue
+		}
+		switch value.(type) {
+		case string, bool, int, float64:
+		default:
+			continue
+		}
+		if converted, err := coerceValue(variable, fmt.Sprint(value)); err == nil && variable.Validate(converted) == nil {
+			coerced[name] = converted
+		}
+	}
+	return coerced
+}
+
+// coerceValue converts a raw string to the type expected by variable
+func coerceValue(variable Variable, raw string) (any, error) {
+	switch variable.Type {
+	case "number":
+		n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric value %q", raw)
+		}
+		return n, nil
+
+	case "boolean":
+		switch strings.ToLower(strings.TrimSpace(raw)) {
+		case "y", "yes", "true", "1":
+			return true, nil
+		case "n", "no", "false", "0":
+			return false, nil
+		default:
+			return nil, fmt.Errorf("invalid boolean value %q", raw)
+		}
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAnswer(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantKey   string
+		wantValue string
+		wantErr   bool
+	}{
+		{input: "project_name=foo", wantKey: "project_name", wantValue: "foo"},
+		{input: "url=https://x.io/?a=b", wantKey: "url", wantValue: "https://x.io/?a=b"},
+		{input: "empty=", wantKey: "empty", wantValue: ""},
+		{input: "novalue", wantErr: true},
+		{input: "=value", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			key, value, err := ParseAnswer(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestCoerceAnswers(t *testing.T) {
+	variables := map[string]Variable{
+		"name":     {Type: "string"},
+		"port":     {Type: "number"},
+		"use_auth": {Type: "boolean"},
+		"db":       {Type: "choice", Choices: []string{"postgres"}},
+	}
+
+	tests := []struct {
+		name        string
+		raw         map[string]string
+		want        map[string]any
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "typed values",
+			raw:  map[string]string{"name": "app", "port": "8080", "use_auth": "yes", "db": "postgres"},
+			want: map[string]any{"name": "app", "port": float64(8080), "use_auth": true, "db": "postgres"},
+		},
+		{
+			name: "undeclared answers stay strings",
+			raw:  map[string]string{"extra": "42"},
+			want: map[string]any{"extra": "42"},
+		},
+		{
+			name:        "invalid number",
+			raw:         map[string]string{"port": "http"},
+			wantErr:     true,
+			errContains: `answer "port": invalid numeric value`,
+		},
+		{
+			name:        "invalid boolean",
+			raw:         map[string]string{"use_auth": "maybe"},
+			wantErr:     true,
+			errContains: "invalid boolean value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := coerceAnswers(variables, tt.raw)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestCoerceFileAnswers(t *testing.T) {
+	variables := map[string]Variable{
+		"go_version": {Type: "string"},
+		"license":    {Type: "choice", Choices: []string{"MIT", "2"}},
+		"port":       {Type: "number"},
+		"ci":         {Type: "boolean"},
+		"services":   {Type: "string"},
+		"replicas":   {Type: "number"},
+	}
+	values := map[string]any{
+		"go_version": 1.24,
+		"license":    2,
+		"port":       "8080",
+		"ci":         "yes",
+		"services":   []any{"api"},
+		"replicas":   "many",
+		"extra":      1,
+	}
+
+	got := coerceFileAnswers(variables, values)
+	assert.Equal(t, map[string]any{
+		"go_version": "1.24",
+		"license":    "2",
+		"port":       8080.0,
+		"ci":         true,
+		"services":   []any{"api"},
+		"replicas":   "many",
+		"extra":      1,
+	}, got)
+}
+
+func TestAnswersFile(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.yaml")
+		values := map[string]any{"project_name": "acme", "port": 8080, "use_docker": true, "_git_remote": ""}
+		require.NoError(t, writeAnswersFile(path, values))
+
+		got, err := LoadAnswersFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, values, got)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"project_name": "acme", "port": 8080}`), 0644))
+
+		got, err := LoadAnswersFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"project_name": "acme", "port": 8080}, got)
+	})
+
+	t.Run("floats keep their text", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("go_version: 1.20\nratio: 0.5\nport: 1.50\n"), 0644))
+
+		got, err := LoadAnswersFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"go_version": "1.20", "ratio": 0.5, "port": "1.50"}, got)
+
+		variables := map[string]Variable{"go_version": {Type: "string"}, "port": {Type: "number"}}
+		assert.Equal(t, map[string]any{"go_version": "1.20", "ratio": 0.5, "port": 1.5}, coerceFileAnswers(variables, got))
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "answers.yaml")
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+
+		got, err := LoadAnswersFile(path)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadAnswersFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CacheDir returns the directory cloned git templates are kept in ($XDG_CACHE_HOME/kick/templates or ~/.cache/kick/templates)
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, "kick", "templates"), nil
+}
+
+// templateCacheDir returns dir, such as the cache_dir setting, or CacheDir when dir is empty
+func templateCacheDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	return CacheDir()
+}
+
+// cacheKey returns the cache entry name for a normalized git URL
+func cacheKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cacheEntry returns the cache directory of a git source. Sources naming a branch, tag
+// or commit are kept apart from the default branch and from each other.
+func cacheEntry(cacheDir, src string) string {
+	key := normalizeGitURL(src)
+	if _, ref := splitRef(src); ref != "" {
+		key += "@" + ref
+	}
+	return filepath.Join(cacheDir, cacheKey(key))
+}
+
+// CachedTemplate describes one git template clone in the cache
+type CachedTemplate struct {
+	URL     string    // clone URL
+	Ref     string    // branch or tag checked out; empty for a pinned commit
+	Commit  string    // commit checked out
+	Path    string    // cache entry directory
+	Size    int64     // bytes on disk, including git objects
+	Fetched time.Time // when the clone was stored
+}
+
+// ListCache describes every git template cached in dir, or in CacheDir when dir is empty,
+// sorted by URL and ref
+func ListCache(dir string) ([]CachedTemplate, error) {
+	cacheDir, err := templateCacheDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache directory: %w", err)
+	}
+
+	var cached []CachedTemplate
+	for _, entry := range entries {
+		// Skip clones still in progress
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(cacheDir, entry.Name())
+		template, err := describeCacheEntry(path)
+		if err != nil {
+			return nil, err
+		}
+		cached = append(cached, template)
+	}
+
+	sort.Slice(cached, func(i, j int) bool {
+		if cached[i].URL != cached[j].URL {
+			return cached[i].URL < cached[j].URL
+		}
+		return cached[i].Ref < cached[j].Ref
+	})
+	return cached, nil
+}
+
+// describeCacheEntry reads the origin, checked out ref and disk usage of a cached clone.
+// An entry that is not a readable repository is still listed so it can be cleaned.
+func describeCacheEntry(path string) (CachedTemplate, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return CachedTemplate{}, err
+	}
+	template := CachedTemplate{Path: path, Fetched: info.ModTime()}
+
+	err = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		template.Size += fileInfo.Size()
+		return nil
+	})
+	if err != nil {
+		return CachedTemplate{}, fmt.Errorf("measure %s: %w", path, err)
+	}
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return template, nil
+	}
+	if remote, err := repo.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+		template.URL = remote.Config().URLs[0]
+	}
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return template, nil
+	}
+	if head.Type() == plumbing.SymbolicReference {
+		template.Ref = head.Target().Short()
+		if resolved, err := repo.Head(); err == nil {
+			template.Commit = resolved.Hash().String()
+		}
+		return template, nil
+	}
+
+	// A detached HEAD is a tag clone or a pinned commit
+	template.Commit = head.Hash().String()
+	if tags, err := repo.Tags(); err == nil {
+		_ = tags.ForEach(func(tag *plumbing.Reference) error {
+			hash := tag.Hash()
+			if annotated, err := repo.TagObject(hash); err == nil {
+				hash = annotated.Target
+			}
+			if hash == head.Hash() {
+				template.Ref = tag.Name().Short()
+			}
+			return nil
+		})
+	}
+	return template, nil
+}
+
+// CleanCache removes the cached clone of a git template from dir (CacheDir when empty), or
+// every cached template when source is empty, and returns how many entries it removed
+func CleanCache(dir, source string) (int, error) {
+	cacheDir, err := templateCacheDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	if source != "" {
+		if !isGitLike(source) {
+			return 0, fmt.Errorf("only git templates are cached")
+		}
+		dir := cacheEntry(cacheDir, source)
+		if _, err := os.Stat(dir); err != nil {
+			return 0, nil
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return 0, fmt.Errorf("remove cached template: %w", err)
+		}
+		return 1, nil
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read cache directory: %w", err)
+	}
+	removed := 0
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("remove cached template: %w", err)
+		}
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// VersionStatus compares the cached copy of a git template with its upstream repository
+type VersionStatus struct {
+	Cached   string // version of the cached template; empty when it is not cached
+	Upstream string // version of the template upstream
+	// Cloned reports that upstream had moved past the cached commit, or nothing was cached,
+	// so upstream was cloned to read its version
+	Cloned bool
+}
+
+// Outdated reports whether a cached template is older than upstream
+func (s VersionStatus) Outdated() bool {
+	return s.Cached != "" && compareVersions(s.Cached, s.Upstream) < 0
+}
+
+// CheckTemplateVersion reads the version of the cached copy of a git template and of the
+// template upstream. The remote's refs tell whether upstream still is at the cached commit;
+// only when it is not is upstream read from a shallow clone that is discarded afterwards.
+// The cache is dir, or CacheDir when dir is empty.
+func CheckTemplateVersion(source, token, dir string) (VersionStatus, error) {
+	if !isGitLike(source) {
+		return VersionStatus{}, fmt.Errorf("version check is only available for git templates")
+	}
+
+	cacheDir, err := templateCacheDir(dir)
+	if err != nil {
+		return VersionStatus{}, err
+	}
+
+	var status VersionStatus
+	var cachedCommit string
+	entry := cacheEntry(cacheDir, source)
+	_, subdir := splitSubdir(source)
+	cached := filepath.Join(entry, filepath.FromSlash(subdir))
+	if _, err := os.Stat(cached); err == nil {
+		cfg, err := loadConfig(cached)
+		if err != nil {
+			return VersionStatus{}, fmt.Errorf("cached template: %w", err)
+		}
+		status.Cached = cfg.Version
+		cachedCommit = headCommit(entry)
+	}
+
+	resolver := NewResolverWithToken(token)
+	upstreamCommit, err := resolver.upstreamCommit(source)
+	if err != nil {
+		return VersionStatus{}, fmt.Errorf("resolve template: %v", err)
+	}
+	if cachedCommit != "" && cachedCommit == upstreamCommit {
+		status.Upstream = status.Cached
+		return status, nil
+	}
+
+	status.Cloned = true
+	upstream, cleanup, err := resolver.Resolve(source)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return VersionStatus{}, fmt.Errorf("resolve template: %v", err)
+	}
+	cfg, err := loadConfig(upstream)
+	if err != nil {
+		return VersionStatus{}, err
+	}
+	status.Upstream = cfg.Version
+
+	return status, nil
+}
+
+// RefreshTemplate replaces the copy of a git template cached in dir (CacheDir when empty)
+// with a fresh clone
+func RefreshTemplate(source, token, dir string) error {
+	cacheDir, err := templateCacheDir(dir)
+	if err != nil {
+		return err
+	}
+
+	resolver := NewResolverWithCache(token, cacheDir)
+	resolver.refresh = true
+	_, _, err = resolver.Resolve(source)
+	return err
+}
+
+// compareVersions compares two semantic versions such as "1.2.0" or "v2.0.0-rc.1",
+// returning -1, 0 or 1. A pre-release sorts before the release it precedes.
+// Non-numeric parts compare as strings.
+func compareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bCore, bPre, _ := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var x, y string
+		if i < len(aParts) {
+			x = aParts[i]
+		}
+		if i < len(bParts) {
+			y = bParts[i]
+		}
+		if c := compareVersionPart(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	default:
+		return strings.Compare(aPre, bPre)
+	}
+}
+
+// compareVersionPart compares one dot-separated version component; missing parts count as 0
+func compareVersionPart(a, b string) int {
+	if a == "" {
+		a = "0"
+	}
+	if b == "" {
+		b = "0"
+	}
+
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
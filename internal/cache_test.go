@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "v1.2.0", b: "1.2.0", want: 0},
+		{a: "1.2.0", b: "1.10.0", want: -1},
+		{a: "2.0.0", b: "1.9.9", want: 1},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1.0.0-rc.1", b: "1.0.0", want: -1},
+		{a: "1.0.0", b: "1.0.0-rc.1", want: 1},
+		{a: "1.0.0-alpha", b: "1.0.0-beta", want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, compareVersions(tt.a, tt.b))
+		})
+	}
+}
+
+func TestVersionStatus_Outdated(t *testing.T) {
+	assert.True(t, VersionStatus{Cached: "1.0.0", Upstream: "1.1.0"}.Outdated())
+	assert.False(t, VersionStatus{Cached: "1.1.0", Upstream: "1.1.0"}.Outdated())
+	assert.False(t, VersionStatus{Upstream: "1.1.0"}.Outdated())
+}
+
+func TestResolver_ResolveCached(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	initRepo(t, repoDir, map[string]string{KickYAML: "name: test\nversion: 1.0.0\n"})
+	cacheDir := t.TempDir()
+
+	resolver := NewResolverWithCache("", cacheDir)
+	path, cleanup, err := resolver.Resolve(repoDir)
+	require.NoError(t, err)
+	assert.Nil(t, cleanup, "cached clones must outlive the run")
+	assert.Equal(t, filepath.Join(cacheDir, cacheKey(repoDir)), path)
+	assert.FileExists(t, filepath.Join(path, KickYAML))
+
+	// A second resolve reuses the cached clone instead of cloning again
+	require.NoError(t, os.WriteFile(filepath.Join(path, "marker"), []byte("cached"), 0644))
+	again, _, err := resolver.Resolve(repoDir)
+	require.NoError(t, err)
+	assert.Equal(t, path, again)
+	assert.FileExists(t, filepath.Join(again, "marker"))
+}
+
+func TestResolver_ResolveCachedRefresh(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	initRepo(t, repoDir, map[string]string{KickYAML: "name: test\n"})
+
+	resolver := NewResolverWithCache("", t.TempDir())
+	path, _, err := resolver.Resolve(repoDir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(path, "marker"), []byte("cached"), 0644))
+
+	resolver.refresh = true
+	again, _, err := resolver.Resolve(repoDir)
+	require.NoError(t, err)
+	assert.Equal(t, path, again)
+	assert.NoFileExists(t, filepath.Join(again, "marker"))
+	assert.FileExists(t, filepath.Join(again, KickYAML))
+}
+
+func TestListCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cacheDir, err := CacheDir()
+	require.NoError(t, err)
+
+	cached, err := ListCache("")
+	require.NoError(t, err)
+	assert.Empty(t, cached)
+
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	repo := initRepo(t, repoDir, map[string]string{KickYAML: "name: test\n"})
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.0.0", head.Hash(), nil)
+	require.NoError(t, err)
+
+	resolver := NewResolverWithCache("", cacheDir)
+	for _, src := range []string{repoDir, repoDir + "?ref=v1.0.0", repoDir + "@" + head.Hash().String()} {
+		_, _, err := resolver.Resolve(src)
+		require.NoError(t, err, src)
+	}
+
+	cached, err = ListCache("")
+	require.NoError(t, err)
+	require.Len(t, cached, 3)
+	var refs []string
+	for _, c := range cached {
+		assert.Equal(t, repoDir, c.URL)
+		assert.Equal(t, head.Hash().String(), c.Commit)
+		assert.Positive(t, c.Size)
+		refs = append(refs, c.Ref)
+	}
+	assert.ElementsMatch(t, []string{"master", "v1.0.0", "v1.0.0"}, refs, "a pinned commit shows the tag that points at it")
+
+	removed, err := CleanCache("", repoDir+"?ref=v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoDirExists(t, cacheEntry(cacheDir, repoDir+"?ref=v1.0.0"))
+
+	removed, err = CleanCache("", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+	cached, err = ListCache("")
+	require.NoError(t, err)
+	assert.Empty(t, cached)
+
+	_, err = CleanCache("", t.TempDir())
+	assert.ErrorContains(t, err, "only git templates are cached")
+}
+
+func TestCheckTemplateVersion(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	repo := initRepo(t, repoDir, map[string]string{KickYAML: "name: test\nversion: 1.0.0\n"})
+	require.NoError(t, RefreshTemplate(repoDir, "", ""))
+
+	status, err := CheckTemplateVersion(repoDir, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, VersionStatus{Cached: "1.0.0", Upstream: "1.0.0"}, status, "upstream at the cached commit is not cloned")
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, KickYAML), []byte("name: test\nversion: 1.1.0\n"), 0644))
+	_, err = worktree.Add(KickYAML)
+	require.NoError(t, err)
+	_, err = worktree.Commit("release 1.1.0", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	status, err = CheckTemplateVersion(repoDir, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, VersionStatus{Cached: "1.0.0", Upstream: "1.1.0", Cloned: true}, status)
+	assert.True(t, status.Outdated())
+}
+
+func TestGenerate_CachedTemplate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use a POSIX shell")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cacheDir, err := CacheDir()
+	require.NoError(t, err)
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	initRepo(t, repoDir, map[string]string{
+		KickYAML:  "name: test\nhooks:\n  pre_generation:\n    - test ! -f leaked.txt && touch leaked.txt\n",
+		"app.txt": "ok",
+	})
+
+	t.Run("hooks do not change the cache", func(t *testing.T) {
+		for range 2 {
+			out := t.TempDir()
+			require.NoError(t, Generate(Options{Source: repoDir, OutputDir: out}))
+			assert.FileExists(t, filepath.Join(out, "app.txt"))
+		}
+		assert.FileExists(t, filepath.Join(cacheEntry(cacheDir, repoDir), "app.txt"))
+		assert.NoFileExists(t, filepath.Join(cacheEntry(cacheDir, repoDir), "leaked.txt"))
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		_, err := CleanCache("", "")
+		require.NoError(t, err)
+		require.NoError(t, Generate(Options{Source: repoDir, OutputDir: t.TempDir(), NoCache: true}))
+		assert.NoDirExists(t, cacheEntry(cacheDir, repoDir))
+	})
+
+	t.Run("cache directory from settings", func(t *testing.T) {
+		custom := t.TempDir()
+		require.NoError(t, Generate(Options{Source: repoDir, OutputDir: t.TempDir(), CacheDir: custom}))
+		assert.DirExists(t, cacheEntry(custom, repoDir))
+		assert.NoDirExists(t, cacheEntry(cacheDir, repoDir))
+
+		cached, err := ListCache(custom)
+		require.NoError(t, err)
+		assert.Len(t, cached, 1)
+		removed, err := CleanCache(custom, "")
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+	})
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// VariableChange describes how one variable differs between two versions of a template
+type VariableChange struct {
+	Name    string
+	Kind    string   // "added", "removed" or "changed"
+	Details []string // human-readable differences of a changed variable
+}
+
+// Changelog resolves two versions of a template and reports how their variables differ
+func Changelog(oldSource, newSource, token string) ([]VariableChange, error) {
+	oldCfg, err := resolveConfig(oldSource, token)
+	if err != nil {
+		return nil, err
+	}
+	newCfg, err := resolveConfig(newSource, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffVariables(oldCfg.Variables, newCfg.Variables), nil
+}
+
+// resolveConfig resolves a template source and parses its configuration
+func resolveConfig(source, token string) (Config, error) {
+	templatePath, cleanup, err := NewResolverWithToken(token).Resolve(source)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("resolve template %s: %v", source, err)
+	}
+	return loadConfig(templatePath)
+}
+
+// diffVariables compares two variable sets, returning changes sorted by variable name
+func diffVariables(oldVars, newVars map[string]Variable) []VariableChange {
+	names := make([]string, 0, len(oldVars)+len(newVars))
+	for name := range oldVars {
+		names = append(names, name)
+	}
+	for name := range newVars {
+		if _, ok := oldVars[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []VariableChange
+	for _, name := range names {
+		oldVar, inOld := oldVars[name]
+		newVar, inNew := newVars[name]
+		switch {
+		case !inOld:
+			details := []string{"type " + newVar.Type}
+			if newVar.Default == nil {
+				details = append(details, "required")
+			}
+			changes = append(changes, VariableChange{Name: name, Kind: "added", Details: details})
+		case !inNew:
+			changes = append(changes, VariableChange{Name: name, Kind: "removed"})
+		default:
+			if details := diffVariable(oldVar, newVar); len(details) > 0 {
+				changes = append(changes, VariableChange{Name: name, Kind: "changed", Details: details})
+			}
+		}
+	}
+	return changes
+}
+
+// diffVariable lists the differences between two definitions of the same variable
+func diffVariable(oldVar, newVar Variable) []string {
+	var details []string
+	if oldVar.Type != newVar.Type {
+		details = append(details, fmt.Sprintf("type %s → %s", oldVar.Type, newVar.Type))
+	}
+
+	if added := missingFrom(oldVar.Choices, newVar.Choices); len(added) > 0 {
+		details = append(details, "choices added: "+strings.Join(added, ", "))
+	}
+	if removed := missingFrom(newVar.Choices, oldVar.Choices); len(removed) > 0 {
+		details = append(details, "choices removed: "+strings.Join(removed, ", "))
+	}
+
+	switch {
+	case oldVar.Default != nil && newVar.Default == nil:
+		details = append(details, "now required (default removed)")
+	case fmt.Sprint(oldVar.Default) != fmt.Sprint(newVar.Default):
+		details = append(details, fmt.Sprintf("default %v → %v", formatDefault(oldVar.Default), formatDefault(newVar.Default)))
+	}
+
+	if oldVar.Pattern != newVar.Pattern {
+		details = append(details, fmt.Sprintf("pattern %q → %q", oldVar.Pattern, newVar.Pattern))
+	}
+	if oldVar.Min != newVar.Min || oldVar.Max != newVar.Max {
+		details = append(details, fmt.Sprintf("range [%d, %d] → [%d, %d]", oldVar.Min, oldVar.Max, newVar.Min, newVar.Max))
+	}
+	return details
+}
+
+// missingFrom returns the items of list that are not in base, in list order
+func missingFrom(base, list []string) []string {
+	var missing []string
+	for _, item := range list {
+		if !slices.Contains(base, item) {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+func formatDefault(v any) string {
+	if v == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%q", fmt.Sprint(v))
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldVars map[string]Variable
+		newVars map[string]Variable
+		want    []VariableChange
+	}{
+		{
+			name:    "unchanged",
+			oldVars: map[string]Variable{"name": {Type: "string", Default: "app"}},
+			newVars: map[string]Variable{"name": {Type: "string", Default: "app"}},
+		},
+		{
+			name:    "added and removed",
+			oldVars: map[string]Variable{"legacy": {Type: "boolean"}},
+			newVars: map[string]Variable{"region": {Type: "string"}, "port": {Type: "number", Default: 8080}},
+			want: []VariableChange{
+				{Name: "legacy", Kind: "removed"},
+				{Name: "port", Kind: "added", Details: []string{"type number"}},
+				{Name: "region", Kind: "added", Details: []string{"type string", "required"}},
+			},
+		},
+		{
+			name:    "type and choices",
+			oldVars: map[string]Variable{"db": {Type: "choice", Choices: []string{"postgres", "mysql"}, Default: "postgres"}},
+			newVars: map[string]Variable{"db": {Type: "choice", Choices: []string{"postgres", "sqlite"}, Default: "postgres"}},
+			want: []VariableChange{
+				{Name: "db", Kind: "changed", Details: []string{"choices added: sqlite", "choices removed: mysql"}},
+			},
+		},
+		{
+			name:    "default removed makes it required",
+			oldVars: map[string]Variable{"owner": {Type: "string", Default: "me"}},
+			newVars: map[string]Variable{"owner": {Type: "choice", Choices: []string{"a"}}},
+			want: []VariableChange{
+				{Name: "owner", Kind: "changed", Details: []string{"type string → choice", "choices added: a", "now required (default removed)"}},
+			},
+		},
+		{
+			name:    "default, pattern and range",
+			oldVars: map[string]Variable{"port": {Type: "number", Default: 8080, Min: 1}, "slug": {Type: "string", Pattern: "^[a-z]+$"}},
+			newVars: map[string]Variable{"port": {Type: "number", Default: 9090, Min: 1024}, "slug": {Type: "string", Pattern: "^[a-z-]+$"}},
+			want: []VariableChange{
+				{Name: "port", Kind: "changed", Details: []string{`default "8080" → "9090"`, "range [1, 0] → [1024, 0]"}},
+				{Name: "slug", Kind: "changed", Details: []string{`pattern "^[a-z]+$" → "^[a-z-]+$"`}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diffVariables(tt.oldVars, tt.newVars))
+		})
+	}
+}
@@ -1,55 +1,324 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
+	"maps"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/yarlson/tap"
+	"golang.org/x/term"
 )
 
-// CollectValues prompts for and collects user input for template variables
-func CollectValues(variables map[string]Variable, order []string) (map[string]any, error) {
+// ErrCancelled is returned when the user cancels a prompt with Ctrl+C
+var ErrCancelled = errors.New("cancelled")
+
+// ErrNoInput is returned when a prompt receives no answer within the prompt timeout
+var ErrNoInput = errors.New("no input received")
+
+// errRequired is returned when a required variable is left empty
+var errRequired = errors.New("a value is required")
+
+// Source records where a variable's value came from
+type Source string
+
+const (
+	SourceImplicit Source = "implicit"     // provided by kick, e.g. _git_remote
+	SourceSettings Source = "settings"     // answers in the user settings file
+	SourceEnv      Source = "environment"  // secrets read from the environment variable named by env
+	SourcePrevious Source = "previous run" // answers in .kick-state.yaml in the output directory
+	SourceFile     Source = "answers file" // pre-seeded values, e.g. from --answers
+	SourceFlag     Source = "command line" // key=value arguments and --answer
+	SourceDefault  Source = "default"      // the default, accepted at the prompt
+	SourcePrompt   Source = "prompted"     // entered at the prompt
+)
+
+// defaultAnswer wraps a prompt result that is the variable's default, accepted as offered
+type defaultAnswer struct {
+	value any
+}
+
+// CollectValues prompts for and collects user input for template variables.
+// Variables already present in seed are validated and used as-is; only the missing ones are prompted.
+func CollectValues(variables map[string]Variable, order []string, seed map[string]any) (map[string]any, error) {
+	values, _, err := collectValues(variables, order, seed, collectOptions{})
+	return values, err
+}
+
+// collectOptions adjusts how collectValues asks for values
+type collectOptions struct {
+	// quick gives advanced variables with a default that default without prompting
+	quick bool
+	// overlays are applied as soon as their selector has a value
+	overlays Overlays
+	// prompt asks for the values that are missing
+	prompt prompter
+}
+
+// collectValues is CollectValues that also reports whether each prompted variable was
+// entered or left at its default.
+func collectValues(variables map[string]Variable, order []string, seed map[string]any, opts collectOptions) (map[string]any, map[string]Source, error) {
+	if answer, ok := seed[opts.overlays.Selector]; ok && opts.overlays.Selector != "" {
+		variables = opts.overlays.apply(variables, answer)
+	}
+
 	values := make(map[string]any, len(variables))
+	sources := make(map[string]Source)
+	// unset holds answered variables that stay out of values, such as optional ones left empty
+	unset := make(map[string]bool)
+	for name, value := range seed {
+		if variable, ok := variables[name]; ok {
+			if variable.Type == "note" {
+				continue
+			}
+			if err := variable.Validate(value); err != nil {
+				return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+			}
+			if variable.omitted(value) {
+				unset[name] = true
+				continue
+			}
+			normalized, err := variable.normalize(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+			}
+			value = normalized
+		}
+		values[name] = value
+	}
 
 	// Project scaffolding intro
 	tap.Intro("🏗️  Project Scaffolding")
 
 	// Process each variable in order
 	for _, name := range order {
+		if _, ok := values[name]; ok || unset[name] {
+			continue
+		}
+
 		variable := variables[name]
-		defStr := fmt.Sprint(variable.Default)
+		conditions := conditionData(variables, values)
+		applies, err := variable.applies(conditions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+		}
+		if !applies {
+			// A skipped variable still gets its default so templates can use it
+			if variable.Type == "note" {
+				continue
+			}
+			value, err := skippedDefault(variable, conditions, values)
+			if err != nil {
+				return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+			}
+			if value != nil {
+				values[name] = value
+				sources[name] = SourceDefault
+			}
+			continue
+		}
+
+		if variable.Type == "note" {
+			if err := opts.prompt.showNote(variable, values); err != nil {
+				return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+			}
+			continue
+		}
+
+		required, err := variable.required(conditions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+		}
+		if variable.conditional() && !required {
+			continue
+		}
+
+		variable, err = variable.conditionalDefault(conditions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+		}
+		defStr, err := resolveDefault(variable, values)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+		}
+
+		if opts.quick && variable.Advanced && variable.Default != nil {
+			value, err := variable.defaultValue(defStr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("variable %q: %w", name, err)
+			}
+			values[name] = value
+			sources[name] = SourceDefault
+			continue
+		}
 
 		var result any
-		var err error
 
 		// Handle different variable types
 		if len(variable.Choices) > 0 {
-			result, err = promptChoice(variable, defStr)
+			result, err = opts.prompt.promptChoice(variable, defStr)
 		} else {
 			switch variable.Type {
 			case "boolean":
-				result, err = promptBoolean(variable)
+				result, err = opts.prompt.promptBoolean(variable)
 			case "number":
-				result, err = promptNumber(variable, defStr)
+				result, err = opts.prompt.promptNumber(variable, defStr)
+			case "path":
+				result, err = opts.prompt.promptPath(variable, defStr, required)
+			case "secret":
+				result, err = opts.prompt.promptSecret(variable, required)
 			default:
-				result, err = promptText(variable, defStr)
+				result, err = opts.prompt.promptText(variable, defStr, required)
 			}
 		}
 
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		sources[name] = SourcePrompt
+		if answer, ok := result.(defaultAnswer); ok {
+			result = answer.value
+			sources[name] = SourceDefault
+		}
+
+		// Skipped tri-state answers and empty optional answers stay out of values
+		if result == nil || variable.omitted(result) {
+			delete(sources, name)
+			continue
 		}
 
 		values[name] = result
+		if name == opts.overlays.Selector {
+			variables = opts.overlays.apply(variables, result)
+		}
+	}
+
+	if err := checkRequired(variables, order, values); err != nil {
+		return nil, nil, err
+	}
+	return values, sources, nil
+}
+
+// skippedDefault returns the default of a variable whose when condition is false, or nil
+// when it has none
+func skippedDefault(variable Variable, conditions, values map[string]any) (any, error) {
+	variable, err := variable.conditionalDefault(conditions)
+	if err != nil || variable.Default == nil {
+		return nil, err
+	}
+	defStr, err := resolveDefault(variable, values)
+	if err != nil {
+		return nil, err
+	}
+	return variable.defaultValue(defStr)
+}
+
+// conditionData returns the values completed with every declared variable that has no value,
+// because it was skipped, left unset or not asked yet, so that when, required and default_when
+// conditions can refer to any variable. Such a variable takes its default when that is a plain
+// value and the zero value of its type otherwise.
+func conditionData(variables map[string]Variable, values map[string]any) map[string]any {
+	data := make(map[string]any, len(variables)+len(values))
+	for name, variable := range variables {
+		if variable.Type == "note" {
+			continue
+		}
+		switch def := variable.Default; {
+		case def != nil && variable.Type == "boolean":
+			data[name] = asBool(def)
+		case def != nil && !strings.Contains(fmt.Sprint(def), "{{"):
+			data[name] = def
+		case variable.Type == "boolean":
+			data[name] = false
+		case variable.Type == "number":
+			data[name] = 0.0
+		default:
+			data[name] = ""
+		}
+	}
+	maps.Copy(data, values)
+	return data
+}
+
+// checkRequired rejects required variables that ended up empty, whether they were
+// answered up front or at a prompt that could not ask, e.g. without a terminal.
+// Variables whose when condition is false are not required.
+func checkRequired(variables map[string]Variable, order []string, values map[string]any) error {
+	for _, name := range order {
+		variable := variables[name]
+		if variable.Required == "" {
+			continue
+		}
+		conditions := conditionData(variables, values)
+		applies, err := variable.applies(conditions)
+		if err != nil {
+			return fmt.Errorf("variable %q: %w", name, err)
+		}
+		if !applies {
+			continue
+		}
+		required, err := variable.required(conditions)
+		if err != nil {
+			return fmt.Errorf("variable %q: %w", name, err)
+		}
+		if value, ok := values[name]; required && (!ok || value == "") {
+			return fmt.Errorf("variable %q: %w", name, errRequired)
+		}
+	}
+	return nil
+}
+
+// resolveDefault returns the variable's default as a string. String defaults may
+// reference implicit context and earlier answers, e.g. "{{ ._git_remote }}".
+func resolveDefault(variable Variable, values map[string]any) (string, error) {
+	if variable.Default == nil {
+		return "", nil
+	}
+
+	str, ok := variable.Default.(string)
+	if !ok || !strings.Contains(str, "{{") {
+		return fmt.Sprint(variable.Default), nil
+	}
+
+	rendered, err := NewRenderer().renderString(str, values)
+	if err != nil {
+		return "", fmt.Errorf("render default: %w", err)
 	}
+	return rendered, nil
+}
 
-	return values, nil
+// showNote displays the rendered text of a note between prompts
+func (p prompter) showNote(variable Variable, values map[string]any) error {
+	text, err := NewRenderer().renderString(variable.Prompt, values)
+	if err != nil {
+		return fmt.Errorf("render note: %w", err)
+	}
+	if p.compact {
+		_, _ = fmt.Fprintln(p.out, text)
+		return nil
+	}
+	tap.Box(text, "", tap.BoxOptions{WidthAuto: true, Rounded: true, IncludePrefix: true})
+	return nil
+}
+
+// defaultValue converts a resolved default to the value a prompt accepting it would return
+func (v Variable) defaultValue(defStr string) (any, error) {
+	if v.Type == "number" || v.Type == "boolean" {
+		return v.Default, nil
+	}
+	if err := v.Validate(defStr); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	return v.normalize(defStr)
 }
 
 // promptChoice handles selection from predefined choices
-func promptChoice(variable Variable, defStr string) (any, error) {
+func (p prompter) promptChoice(variable Variable, defStr string) (any, error) {
 	options := make([]tap.SelectOption[string], len(variable.Choices))
 	for i, choice := range variable.Choices {
 		options[i] = tap.SelectOption[string]{
@@ -60,91 +329,250 @@ func promptChoice(variable Variable, defStr string) (any, error) {
 
 	// Set initial value if default matches a choice
 	var initialValue *string
-	for _, choice := range variable.Choices {
-		if choice == defStr {
-			initialValue = &choice
-			break
-		}
+	if choice, ok := variable.matchChoice(defStr); ok {
+		initialValue = &choice
 	}
 
-	return tap.Select(tap.SelectOptions[string]{
-		Message:      variable.Prompt,
-		Options:      options,
-		InitialValue: initialValue,
-	}), nil
+	selected, err := p.selectOption(variable.Prompt, options, initialValue)
+	if err != nil {
+		return nil, err
+	}
+	if promptCancelled(selected == "" && !slices.Contains(variable.Choices, "")) {
+		return nil, ErrCancelled
+	}
+
+	var result any = selected
+	// Number choices are returned as numbers
+	if variable.Type == "number" {
+		n, err := strconv.ParseFloat(selected, 64)
+		if err != nil {
+			return defaultAnswer{variable.Default}, nil
+		}
+		result = n
+	}
+	if initialValue != nil && selected == *initialValue {
+		return defaultAnswer{result}, nil
+	}
+	return result, nil
 }
 
 // promptBoolean handles yes/no prompts
-func promptBoolean(variable Variable) (any, error) {
+func (p prompter) promptBoolean(variable Variable) (any, error) {
+	if variable.TriState {
+		return p.promptTriState(variable)
+	}
+
 	initialValue := asBool(variable.Default)
 
-	return tap.Confirm(tap.ConfirmOptions{
-		Message:      variable.Prompt,
-		Active:       "Yes",
-		Inactive:     "No",
-		InitialValue: initialValue,
-	}), nil
+	confirmed, err := p.confirm(variable.Prompt, initialValue)
+	if err != nil {
+		return nil, err
+	}
+	if variable.Default != nil && confirmed == initialValue {
+		return defaultAnswer{confirmed}, nil
+	}
+	return confirmed, nil
+}
+
+// promptTriState handles yes/no/skip prompts, returning nil when skipped
+func (p prompter) promptTriState(variable Variable) (any, error) {
+	const (
+		yes  = "yes"
+		no   = "no"
+		skip = "skip"
+	)
+
+	initialValue := skip
+	if variable.Default != nil {
+		initialValue = no
+		if asBool(variable.Default) {
+			initialValue = yes
+		}
+	}
+
+	selected, err := p.selectOption(variable.Prompt, []tap.SelectOption[string]{
+		{Value: yes, Label: "Yes"},
+		{Value: no, Label: "No"},
+		{Value: skip, Label: "Skip", Hint: "leave unset"},
+	}, &initialValue)
+	if err != nil {
+		return nil, err
+	}
+	if promptCancelled(selected == "") {
+		return nil, ErrCancelled
+	}
+
+	var result any
+	switch selected {
+	case yes:
+		result = true
+	case no:
+		result = false
+	}
+	if selected == initialValue {
+		return defaultAnswer{result}, nil
+	}
+	return result, nil
 }
 
 // promptNumber handles numeric input with validation
-func promptNumber(variable Variable, defStr string) (any, error) {
-	input := tap.Text(tap.TextOptions{
-		Message:      variable.Prompt,
-		Placeholder:  defStr,
-		DefaultValue: defStr,
-		Validate: func(input string) error {
-			if input == "" {
-				return nil // Allow empty input to use default
-			}
-			if _, err := strconv.ParseFloat(input, 64); err != nil {
-				return fmt.Errorf("invalid numeric value")
-			}
-			return nil
-		},
+func (p prompter) promptNumber(variable Variable, defStr string) (any, error) {
+	input, isDefault, err := p.promptInput(variable.Prompt, defStr, func(input string) error {
+		if input == "" {
+			return nil // Allow empty input to use default
+		}
+		n, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return fmt.Errorf("invalid numeric value")
+		}
+		return variable.Validate(n)
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	// Convert to number
-	if input == "" {
-		return variable.Default, nil
+	if isDefault {
+		return defaultAnswer{variable.Default}, nil
 	}
 	if n, err := strconv.ParseFloat(input, 64); err == nil {
 		return n, nil
 	}
-	return variable.Default, nil
+	return defaultAnswer{variable.Default}, nil
 }
 
 // promptText handles text input with optional pattern validation
-func promptText(variable Variable, defStr string) (any, error) {
-	var validate func(string) error
-	if variable.Pattern != "" {
-		validate = func(input string) error {
-			if input == "" {
-				return nil // Allow empty input to use default
-			}
-			matched, err := regexp.MatchString(variable.Pattern, input)
-			if err != nil {
-				return fmt.Errorf("pattern validation error: %v", err)
-			}
-			if !matched {
-				if variable.Help != "" {
-					return fmt.Errorf("%s", variable.Help)
-				}
-				return fmt.Errorf("value does not match pattern: %s", variable.Pattern)
-			}
+func (p prompter) promptText(variable Variable, defStr string, required bool) (any, error) {
+	input, isDefault, err := p.promptInput(variable.Prompt, defStr, func(input string) error {
+		if input == "" && required {
+			return errRequired
+		}
+		if input == "" {
+			return nil // Allow empty input to use default
+		}
+		return variable.Validate(input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if isDefault {
+		return defaultAnswer{defStr}, nil
+	}
+	return input, nil
+}
+
+// promptInput shows a text prompt that falls back to defStr on empty input and reports
+// whether the default was accepted without typing. validate sees the default rather than
+// the empty input. The default is only shown as the placeholder, so tap returns what was
+// typed and an empty submit stays empty.
+func (p prompter) promptInput(message, defStr string, validate func(string) error) (string, bool, error) {
+	if p.compact {
+		return p.compactInput(message, defStr, validate)
+	}
+
+	submit := submission{validate: func(input string) error {
+		if input == "" {
+			input = defStr
+		}
+		if validate == nil {
 			return nil
 		}
+		return validate(input)
+	}}
+
+	input, err := awaitPrompt(p.timeout, func() string {
+		return tap.Text(tap.TextOptions{
+			Message:     message,
+			Placeholder: defStr,
+			Validate:    submit.check,
+		})
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if promptCancelled(!submit.submitted) {
+		return "", false, ErrCancelled
+	}
+	if input == "" {
+		return defStr, true, nil
+	}
+	return input, false, nil
+}
+
+// awaitPrompt runs a prompt, giving up with ErrNoInput once timeout passes without an
+// answer; zero waits indefinitely. Neither tap prompts nor a read from the standard input can
+// be interrupted, so the abandoned prompt leaks: its goroutine stays blocked on the input until
+// the process exits. The terminal state is restored here and callers are expected to abort.
+func awaitPrompt[T any](timeout time.Duration, prompt func() T) (T, error) {
+	if timeout <= 0 {
+		return prompt(), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, _ := term.GetState(fd)
+
+	answer := make(chan T, 1)
+	go func() { answer <- prompt() }()
+
+	select {
+	case value := <-answer:
+		return value, nil
+	case <-time.After(timeout):
+		if state != nil {
+			_ = term.Restore(fd, state)
+		}
+		var zero T
+		return zero, fmt.Errorf("%w within %s", ErrNoInput, timeout)
 	}
+}
+
+// submission tells a submitted tap text prompt apart from a cancelled one. tap returns an
+// empty string both on Ctrl+C and for input that was typed and erased, but it runs Validate
+// only when Enter is pressed, and submits only once Validate accepts the input.
+type submission struct {
+	validate  func(string) error
+	submitted bool
+}
+
+// check is the Validate function of the prompt
+func (s *submission) check(input string) error {
+	err := s.validate(input)
+	s.submitted = err == nil
+	return err
+}
+
+// promptCancelled reports whether a prompt that returned without an answer, such as an empty
+// selection or a text prompt that was never submitted, was cancelled with Ctrl+C. tap returns
+// zero values both on cancel and when no terminal is available, so it only counts as
+// cancelled on a terminal. A cancelled select offers no other signal, and a cancelled
+// confirm cannot be told apart from "No".
+func promptCancelled(unanswered bool) bool {
+	return unanswered && term.IsTerminal(int(os.Stdin.Fd()))
+}
 
-	input := tap.Text(tap.TextOptions{
-		Message:      variable.Prompt,
-		Placeholder:  defStr,
-		DefaultValue: defStr,
-		Validate:     validate,
+// promptPath handles path input, validating existence constraints and returning an absolute path
+func (p prompter) promptPath(variable Variable, defStr string, required bool) (any, error) {
+	input, isDefault, err := p.promptInput(variable.Prompt, defStr, func(input string) error {
+		if input == "" && required {
+			return errRequired
+		}
+		return variable.Validate(input)
 	})
+	if err != nil {
+		return nil, err
+	}
 	if input == "" {
-		return defStr, nil
+		return defaultAnswer{""}, nil
 	}
-	return input, nil
+
+	path, err := normalizePath(input)
+	if err != nil {
+		return nil, err
+	}
+	if isDefault {
+		return defaultAnswer{path}, nil
+	}
+	return path, nil
 }
 
 // asBool converts various types to boolean
@@ -159,6 +587,8 @@ func asBool(v any) bool {
 		default:
 			return false
 		}
+	case int:
+		return t != 0
 	case float64:
 		return t != 0
 	default:
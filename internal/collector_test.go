@@ -0,0 +1,375 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/term"
+)
+
+func TestCollectValues_Seeded(t *testing.T) {
+	variables := map[string]Variable{
+		"project_name": {Type: "string", Pattern: "^[a-z-]+$"},
+		"port":         {Type: "number", Min: 1024},
+		"use_docker":   {Type: "boolean"},
+	}
+	order := []string{"project_name", "port", "use_docker"}
+
+	tests := []struct {
+		name        string
+		seed        map[string]any
+		want        map[string]any
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "all variables provided",
+			seed: map[string]any{"project_name": "my-app", "port": 8080, "use_docker": true},
+			want: map[string]any{"project_name": "my-app", "port": 8080, "use_docker": true},
+		},
+		{
+			name: "extra values are kept",
+			seed: map[string]any{"project_name": "my-app", "port": 8080, "use_docker": false, "extra": "x"},
+			want: map[string]any{"project_name": "my-app", "port": 8080, "use_docker": false, "extra": "x"},
+		},
+		{
+			name:        "provided value fails validation",
+			seed:        map[string]any{"project_name": "My App", "port": 8080, "use_docker": true},
+			wantErr:     true,
+			errContains: `variable "project_name"`,
+		},
+		{
+			name:        "provided value has wrong type",
+			seed:        map[string]any{"project_name": "my-app", "port": "8080", "use_docker": true},
+			wantErr:     true,
+			errContains: "expected number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := CollectValues(variables, order, tt.seed)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func TestCollectValues_Normalizes(t *testing.T) {
+	variables := map[string]Variable{
+		"database": {Type: "choice", Choices: []string{"postgres", "mysql"}, CaseInsensitive: true},
+		"key_file": {Type: "path"},
+	}
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	values, err := CollectValues(variables, []string{"database", "key_file"}, map[string]any{"database": "Postgres", "key_file": "keys/id.pem"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", values["database"])
+	assert.Equal(t, filepath.Join(wd, "keys", "id.pem"), values["key_file"])
+}
+
+func TestCollectValues_Optional(t *testing.T) {
+	variables := map[string]Variable{
+		"homepage": {Type: "string", Optional: true, Pattern: "^https://"},
+		"logo":     {Type: "path", Optional: true},
+		"name":     {Type: "string"},
+	}
+
+	values, err := CollectValues(variables, []string{"homepage", "logo", "name"}, map[string]any{"homepage": "", "logo": "", "name": ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": ""}, values)
+
+	values, err = CollectValues(variables, []string{"homepage", "logo", "name"}, map[string]any{"homepage": "https://example.com", "logo": "/logo.png", "name": "app"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", values["homepage"])
+
+	_, err = CollectValues(variables, []string{"homepage", "logo", "name"}, map[string]any{"homepage": "example.com", "logo": "", "name": ""})
+	assert.Error(t, err, "a non-empty optional value is still validated")
+}
+
+func TestCollectValues_Required(t *testing.T) {
+	variables := map[string]Variable{
+		"use_database": {Type: "boolean"},
+		"db_host":      {Type: "string", Required: "{{ .use_database }}"},
+		"owner":        {Type: "string", Required: "true"},
+	}
+	order := []string{"use_database", "db_host", "owner"}
+
+	values, err := CollectValues(variables, order, map[string]any{"use_database": true, "db_host": "localhost", "owner": "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", values["db_host"])
+
+	_, err = CollectValues(variables, order, map[string]any{"use_database": true, "db_host": "", "owner": "acme"})
+	assert.ErrorContains(t, err, `variable "db_host": a value is required`)
+
+	values, err = CollectValues(variables, order, map[string]any{"use_database": false, "owner": "acme"})
+	require.NoError(t, err, "db_host is skipped when use_database is false")
+	assert.NotContains(t, values, "db_host")
+
+	_, err = CollectValues(variables, order, map[string]any{"use_database": false, "owner": ""})
+	assert.ErrorContains(t, err, `variable "owner": a value is required`)
+}
+
+func TestCollectValues_Sources(t *testing.T) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		t.Skip("prompts are interactive on a terminal")
+	}
+
+	variables := map[string]Variable{
+		"project_name": {Type: "string", Default: "my-app"},
+		"port":         {Type: "number", Default: 8080},
+	}
+
+	// Without a terminal every prompt falls back to its default
+	values, sources, err := collectValues(variables, []string{"project_name", "port"}, map[string]any{"project_name": "given"}, collectOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"project_name": "given", "port": 8080}, values)
+	assert.Equal(t, map[string]Source{"port": SourceDefault}, sources)
+}
+
+func TestResolveDefault(t *testing.T) {
+	values := map[string]any{"_git_remote": "https://github.com/acme/widgets", "project_name": "widgets"}
+
+	tests := []struct {
+		name     string
+		variable Variable
+		want     string
+		wantErr  bool
+	}{
+		{name: "no default", variable: Variable{Type: "string"}, want: ""},
+		{name: "static default", variable: Variable{Type: "number", Default: 8080}, want: "8080"},
+		{name: "implicit context", variable: Variable{Type: "string", Default: "{{ ._git_remote }}"}, want: "https://github.com/acme/widgets"},
+		{name: "earlier answer", variable: Variable{Type: "string", Default: "{{ .project_name | upper }}"}, want: "WIDGETS"},
+		{name: "unknown reference", variable: Variable{Type: "string", Default: "{{ .missing }}"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveDefault(tt.variable, values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAwaitPrompt(t *testing.T) {
+	const timeout = 20 * time.Millisecond
+
+	t.Run("answered in time", func(t *testing.T) {
+		got, err := awaitPrompt(timeout, func() string { return "api" })
+		require.NoError(t, err)
+		assert.Equal(t, "api", got)
+	})
+
+	t.Run("no input", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+
+		_, err := awaitPrompt(timeout, func() string {
+			<-block
+			return "late"
+		})
+		require.ErrorIs(t, err, ErrNoInput)
+		assert.Contains(t, err.Error(), "no input received within 20ms")
+	})
+}
+
+func TestSubmission(t *testing.T) {
+	submit := submission{validate: func(input string) error {
+		if input == "" {
+			return errRequired
+		}
+		return nil
+	}}
+	assert.False(t, submit.submitted, "a prompt that never ran Validate was cancelled")
+
+	require.ErrorIs(t, submit.check(""), errRequired)
+	assert.False(t, submit.submitted, "rejected input is not submitted")
+
+	require.NoError(t, submit.check("api"))
+	assert.True(t, submit.submitted)
+}
+
+func TestCollectValues_Quick(t *testing.T) {
+	variables := map[string]Variable{
+		"project_name": {Type: "string"},
+		"port":         {Type: "number", Default: 8080, Advanced: true},
+		"log_format":   {Type: "choice", Choices: []string{"text", "json"}, Default: "JSON", CaseInsensitive: true, Advanced: true},
+		"module":       {Type: "string", Default: "example.com/{{ .project_name }}", Advanced: true},
+	}
+	order := []string{"project_name", "port", "log_format", "module"}
+
+	values, sources, err := collectValues(variables, order, map[string]any{"project_name": "demo"}, collectOptions{quick: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"project_name": "demo", "port": 8080, "log_format": "json", "module": "example.com/demo"}, values)
+	assert.Equal(t, map[string]Source{"port": SourceDefault, "log_format": SourceDefault, "module": SourceDefault}, sources)
+
+	variables["module"] = Variable{Type: "string", Default: "Example", Pattern: "^[a-z]+$", Advanced: true}
+	_, _, err = collectValues(variables, order, map[string]any{"project_name": "demo"}, collectOptions{quick: true})
+	assert.ErrorContains(t, err, `variable "module": default`)
+}
+
+func TestCollectValues_Overlays(t *testing.T) {
+	variables := map[string]Variable{
+		"environment": {Type: "choice", Choices: []string{"dev", "prod"}, Default: "dev"},
+		"replicas":    {Type: "number", Default: 1, Advanced: true},
+		"log_level":   {Type: "choice", Choices: []string{"debug", "info"}, Default: "debug", Advanced: true},
+	}
+	order := []string{"environment", "replicas", "log_level"}
+	overlays := Overlays{
+		Selector: "environment",
+		Values: map[string]map[string]VariableOverlay{
+			"prod": {
+				"replicas":  {Default: 3},
+				"log_level": {Default: "warn", Choices: []string{"warn", "error"}},
+			},
+		},
+	}
+	opts := collectOptions{quick: true, overlays: overlays}
+
+	values, _, err := collectValues(variables, order, map[string]any{"environment": "prod"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"environment": "prod", "replicas": 3, "log_level": "warn"}, values)
+
+	values, _, err = collectValues(variables, order, map[string]any{"environment": "dev"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"environment": "dev", "replicas": 1, "log_level": "debug"}, values)
+
+	_, _, err = collectValues(variables, order, map[string]any{"environment": "prod", "log_level": "debug"}, opts)
+	assert.ErrorContains(t, err, "not a valid choice", "seeded answers are checked against the overlay")
+}
+
+func TestCollectValues_DefaultWhen(t *testing.T) {
+	variables := map[string]Variable{
+		"shell": {Type: "choice", Choices: []string{"bash", "zsh", "pwsh"}, Default: "bash", Advanced: true, DefaultWhen: []ConditionalDefault{
+			{When: `{{ eq ._os "darwin" }}`, Value: "zsh"},
+			{When: `{{ eq ._os "windows" }}`, Value: "pwsh"},
+		}},
+		"install_dir": {Type: "string", Advanced: true, DefaultWhen: []ConditionalDefault{
+			{When: `{{ eq .shell "pwsh" }}`, Value: `C:\{{ .name }}`},
+			{When: "{{ true }}", Value: "/opt/{{ .name }}"},
+		}},
+	}
+	order := []string{"shell", "install_dir"}
+
+	tests := []struct {
+		os   string
+		want map[string]any
+	}{
+		{os: "darwin", want: map[string]any{"shell": "zsh", "install_dir": "/opt/app"}},
+		{os: "windows", want: map[string]any{"shell": "pwsh", "install_dir": `C:\app`}},
+		{os: "linux", want: map[string]any{"shell": "bash", "install_dir": "/opt/app"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.os, func(t *testing.T) {
+			values, sources, err := collectValues(variables, order, map[string]any{"_os": tt.os, "name": "app"}, collectOptions{quick: true})
+			require.NoError(t, err)
+			for name, want := range tt.want {
+				assert.Equal(t, want, values[name], name)
+				assert.Equal(t, SourceDefault, sources[name], name)
+			}
+		})
+	}
+}
+
+func TestCollectValues_When(t *testing.T) {
+	variables := map[string]Variable{
+		"use_database": {Type: "boolean"},
+		"db_note":      {Type: "note", Prompt: "Database settings", When: "{{ .use_database }}"},
+		"db_host":      {Type: "string", Prompt: "Host", Required: "true", When: "{{ .use_database }}"},
+		"db_port":      {Type: "number", Prompt: "Port", Default: 5432, When: "{{ .use_database }}"},
+	}
+	order := []string{"use_database", "db_note", "db_host", "db_port"}
+
+	t.Run("condition false skips the prompts", func(t *testing.T) {
+		prompt, out := compactPrompter("")
+		values, sources, err := collectValues(variables, order, map[string]any{"use_database": false}, collectOptions{prompt: prompt})
+		require.NoError(t, err, "a skipped variable is not required")
+		assert.Equal(t, map[string]any{"use_database": false, "db_port": 5432}, values)
+		assert.Equal(t, SourceDefault, sources["db_port"])
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("condition true prompts", func(t *testing.T) {
+		prompt, out := compactPrompter("db.local\n6543\n")
+		values, _, err := collectValues(variables, order, map[string]any{"use_database": true}, collectOptions{prompt: prompt})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"use_database": true, "db_host": "db.local", "db_port": 6543.0}, values)
+		assert.Contains(t, out.String(), "Database settings")
+	})
+}
+
+func TestCollectValues_ConditionsOnUnsetVariables(t *testing.T) {
+	variables := map[string]Variable{
+		"nickname":  {Type: "string", Optional: true},
+		"greeting":  {Type: "string", Prompt: "Greeting", Default: "hi", When: `{{ ne .nickname "" }}`},
+		"tls":       {Type: "boolean", Prompt: "TLS", Default: true, When: `{{ eq .region "eu" }}`},
+		"region":    {Type: "string", Prompt: "Region", Default: "eu"},
+		"ca_file":   {Type: "string", Prompt: "CA file", Required: "{{ .custom_ca }}"},
+		"custom_ca": {Type: "boolean", Prompt: "Custom CA"},
+	}
+	order := []string{"nickname", "greeting", "tls", "region", "ca_file", "custom_ca"}
+
+	prompt, out := compactPrompter("\n\n\n")
+	values, _, err := collectValues(variables, order, map[string]any{"nickname": ""}, collectOptions{prompt: prompt})
+	require.NoError(t, err, "conditions on skipped, unset and later variables evaluate with their defaults")
+	assert.Equal(t, map[string]any{"greeting": "hi", "tls": true, "region": "eu", "custom_ca": false}, values)
+	assert.Equal(t, "TLS [Y/n]: Region [eu]: Custom CA [y/N]: ", out.String())
+}
+
+func TestCollectValues_Notes(t *testing.T) {
+	variables := map[string]Variable{
+		"name":    {Type: "string", Prompt: "Name"},
+		"db_note": {Type: "note", Prompt: "The next questions configure the {{ .name }} database"},
+		"db_name": {Type: "string", Prompt: "Database", Default: "{{ .name }}_db"},
+	}
+	order := []string{"name", "db_note", "db_name"}
+
+	prompt, out := compactPrompter("svc\n\n")
+	values, _, err := collectValues(variables, order, map[string]any{"db_note": "ignored"}, collectOptions{prompt: prompt})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "svc", "db_name": "svc_db"}, values)
+	assert.Equal(t, "Name: The next questions configure the svc database\nDatabase [svc_db]: ", out.String())
+	assert.NotContains(t, templateData(variables, values), "db_note")
+}
+
+func TestAsBool(t *testing.T) {
+	tests := []struct {
+		value any
+		want  bool
+	}{
+		{true, true},
+		{false, false},
+		{"yes", true},
+		{" Y ", true},
+		{"true", true},
+		{"no", false},
+		{"", false},
+		{1, true},
+		{0, false},
+		{1.0, true},
+		{0.0, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, asBool(tt.value), "%#v", tt.value)
+		assert.Equal(t, map[bool]string{true: "Y/n", false: "y/N"}[tt.want], confirmHint(asBool(tt.value)))
+	}
+}
@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yarlson/tap"
+)
+
+// Prompt styles for Options.PromptStyle
+const (
+	PromptStyleStepped = "stepped" // tap's framed prompts, one step per variable
+	PromptStyleCompact = "compact" // a single plain line per variable
+)
+
+// stdinReader reads the answers to compact prompts from the standard input
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// prompter asks for values. The zero value uses tap's stepped prompts and waits indefinitely.
+type prompter struct {
+	// timeout bounds how long each prompt waits for an answer; zero waits indefinitely
+	timeout time.Duration
+	// compact asks with single-line prompts instead of tap's stepped ones, reading answers
+	// from in and writing questions to out
+	compact bool
+	in      *bufio.Reader
+	out     io.Writer
+}
+
+// newPrompter returns a prompter asking in style, giving up on a prompt after timeout.
+// Compact prompts use the standard input and output.
+func newPrompter(style string, timeout time.Duration) prompter {
+	return prompter{timeout: timeout, compact: style == PromptStyleCompact, in: stdinReader, out: os.Stdout}
+}
+
+// validPromptStyle reports an error for an unknown prompt style; empty selects the stepped style
+func validPromptStyle(style string) error {
+	switch style {
+	case "", PromptStyleStepped, PromptStyleCompact:
+		return nil
+	}
+	return fmt.Errorf("unknown prompt style %q, must be one of [%s, %s]", style, PromptStyleStepped, PromptStyleCompact)
+}
+
+// selectOption asks for one of options, returning the selected value
+func (p prompter) selectOption(message string, options []tap.SelectOption[string], initial *string) (string, error) {
+	if p.compact {
+		return p.compactSelect(message, options, initial)
+	}
+	return awaitPrompt(p.timeout, func() string {
+		return tap.Select(tap.SelectOptions[string]{
+			Message:      message,
+			Options:      options,
+			InitialValue: initial,
+		})
+	})
+}
+
+// confirm asks a yes/no question, answered with a single y or n keypress. Enter accepts
+// initial, which the question shows in capitals, e.g. "(Y/n)".
+func (p prompter) confirm(message string, initial bool) (bool, error) {
+	if p.compact {
+		return p.compactConfirm(message, initial)
+	}
+	return awaitPrompt(p.timeout, func() bool {
+		return tap.Confirm(tap.ConfirmOptions{
+			Message:      fmt.Sprintf("%s (%s)", message, confirmHint(initial)),
+			Active:       "Yes",
+			Inactive:     "No",
+			InitialValue: initial,
+		})
+	})
+}
+
+// compactInput asks for a line of text until validate accepts it. Empty input selects defStr,
+// and so does the end of input, as tap does without a terminal. validate sees the default
+// rather than the empty input.
+func (p prompter) compactInput(message, defStr string, validate func(string) error) (string, bool, error) {
+	for {
+		if defStr != "" {
+			_, _ = fmt.Fprintf(p.out, "%s [%s]: ", message, defStr)
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s: ", message)
+		}
+
+		line, eof, err := p.readAnswer()
+		if err != nil || eof {
+			return defStr, err == nil, err
+		}
+
+		value := line
+		if line == "" {
+			value = defStr
+		}
+		if validate != nil {
+			if err := validate(value); err != nil {
+				_, _ = fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return value, line == "", nil
+	}
+}
+
+// compactSelect asks for an option by value, label or 1-based position
+func (p prompter) compactSelect(message string, options []tap.SelectOption[string], initial *string) (string, error) {
+	labels := make([]string, len(options))
+	for i, option := range options {
+		labels[i] = option.Label
+	}
+
+	defStr := ""
+	if initial != nil {
+		for _, option := range options {
+			if option.Value == *initial {
+				defStr = option.Label
+			}
+		}
+	}
+
+	for {
+		if defStr != "" {
+			_, _ = fmt.Fprintf(p.out, "%s (%s) [%s]: ", message, strings.Join(labels, "/"), defStr)
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s (%s): ", message, strings.Join(labels, "/"))
+		}
+
+		line, eof, err := p.readAnswer()
+		if err != nil {
+			return "", err
+		}
+		if (eof || line == "") && initial != nil {
+			return *initial, nil
+		}
+		if eof {
+			return "", nil
+		}
+
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1].Value, nil
+		}
+		for _, option := range options {
+			if strings.EqualFold(line, option.Value) || strings.EqualFold(line, option.Label) {
+				return option.Value, nil
+			}
+		}
+		_, _ = fmt.Fprintf(p.out, "  choose one of %s\n", strings.Join(labels, ", "))
+	}
+}
+
+// compactConfirm asks a yes/no question; empty input and the end of input select initial
+func (p prompter) compactConfirm(message string, initial bool) (bool, error) {
+	for {
+		_, _ = fmt.Fprintf(p.out, "%s [%s]: ", message, confirmHint(initial))
+		line, eof, err := p.readAnswer()
+		if err != nil {
+			return false, err
+		}
+		if eof || line == "" {
+			return initial, nil
+		}
+
+		switch strings.ToLower(line) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		_, _ = fmt.Fprintln(p.out, "  answer y or n")
+	}
+}
+
+// confirmHint spells the answers to a yes/no question with the default in capitals
+func confirmHint(initial bool) string {
+	if initial {
+		return "Y/n"
+	}
+	return "y/N"
+}
+
+// readAnswer reads one line from p.in within the prompt timeout, reporting the end of input
+func (p prompter) readAnswer() (string, bool, error) {
+	type answer struct {
+		line string
+		err  error
+	}
+
+	got, err := awaitPrompt(p.timeout, func() answer {
+		line, err := p.in.ReadString('\n')
+		return answer{line, err}
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if got.err != nil && !errors.Is(got.err, io.EOF) {
+		return "", false, fmt.Errorf("read answer: %w", got.err)
+	}
+	if got.err != nil && got.line == "" {
+		_, _ = fmt.Fprintln(p.out)
+		return "", true, nil
+	}
+	return strings.TrimSpace(got.line), false, nil
+}
@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yarlson/tap"
+)
+
+// compactPrompter returns a prompter asking compact prompts answered from input, and the
+// buffer they write to
+func compactPrompter(input string) (prompter, *bytes.Buffer) {
+	var out bytes.Buffer
+	return prompter{compact: true, in: bufio.NewReader(strings.NewReader(input)), out: &out}, &out
+}
+
+func TestCompactInput(t *testing.T) {
+	p, out := compactPrompter("Bad Name\nmy-app\n")
+	value, isDefault, err := p.promptInput("Project name", "demo", func(s string) error {
+		return Variable{Type: "string", Pattern: "^[a-z-]+$"}.Validate(s)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", value)
+	assert.False(t, isDefault)
+	assert.Equal(t, "Project name [demo]:   value \"Bad Name\" does not match pattern \"^[a-z-]+$\"\nProject name [demo]: ", out.String())
+
+	p, _ = compactPrompter("\n")
+	value, isDefault, err = p.promptInput("Project name", "demo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "demo", value)
+	assert.True(t, isDefault)
+
+	p, _ = compactPrompter("")
+	value, isDefault, err = p.promptInput("Project name", "demo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "demo", value, "end of input takes the default")
+	assert.True(t, isDefault)
+}
+
+func TestCompactSelect(t *testing.T) {
+	options := []tap.SelectOption[string]{
+		{Value: "postgres", Label: "postgres"},
+		{Value: "mysql", Label: "mysql"},
+	}
+	initial := "postgres"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "by value", input: "mysql\n", want: "mysql"},
+		{name: "ignoring case", input: "MySQL\n", want: "mysql"},
+		{name: "by position", input: "2\n", want: "mysql"},
+		{name: "default", input: "\n", want: "postgres"},
+		{name: "asks again after an unknown option", input: "oracle\n3\nmysql\n", want: "mysql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, out := compactPrompter(tt.input)
+			got, err := p.selectOption("Database", options, &initial)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, strings.HasPrefix(out.String(), "Database (postgres/mysql) [postgres]: "))
+		})
+	}
+}
+
+func TestCompactConfirm(t *testing.T) {
+	tests := []struct {
+		input   string
+		initial bool
+		want    bool
+	}{
+		{input: "y\n", want: true},
+		{input: "No\n", initial: true, want: false},
+		{input: "\n", initial: true, want: true},
+		{input: "maybe\nyes\n", want: true},
+		{input: "", initial: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, _ := compactPrompter(tt.input)
+			got, err := p.confirm("Enable auth", tt.initial)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGenerate_UnknownPromptStyle(t *testing.T) {
+	err := Generate(Options{Source: t.TempDir(), OutputDir: t.TempDir(), PromptStyle: "fancy"})
+	assert.ErrorContains(t, err, `unknown prompt style "fancy"`)
+}
+
+func TestCompactSecret(t *testing.T) {
+	p, out := compactPrompter("\nshort\nlong-enough\n")
+	value, err := p.promptSecret(Variable{Type: "secret", Prompt: "Token", Pattern: "^.{8,}$"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "long-enough", value)
+	assert.Equal(t, "Token:   a value is required\nToken:   value does not match pattern \"^.{8,}$\"\nToken: ", out.String())
+	assert.NotContains(t, out.String(), "short", "errors do not reveal the secret")
+}
+
+func TestCompactSecret_EndOfInput(t *testing.T) {
+	p, _ := compactPrompter("")
+	_, err := p.promptSecret(Variable{Type: "secret", Prompt: "Token"}, true)
+	assert.ErrorIs(t, err, errRequired)
+
+	p, _ = compactPrompter("")
+	value, err := p.promptSecret(Variable{Type: "secret", Prompt: "Token", Optional: true}, false)
+	require.NoError(t, err)
+	assert.Empty(t, value)
+}
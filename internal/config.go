@@ -1,10 +1,20 @@
 package internal
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -23,6 +33,9 @@ type Config struct {
 	// Template variables
 	Variables map[string]Variable `yaml:"variables"`
 
+	// Overlays adjust variable defaults and choices depending on an earlier answer
+	Overlays Overlays `yaml:"overlays,omitempty"`
+
 	// Optional hooks
 	Hooks Hooks `yaml:"hooks,omitempty"`
 
@@ -43,18 +56,188 @@ type Variable struct {
 	Help    string   `yaml:"help,omitempty"`
 	Min     int      `yaml:"min,omitempty"`
 	Max     int      `yaml:"max,omitempty"`
+
+	// Error replaces the message shown when a value fails the pattern, range or choices.
+	// It is a template with .value, .pattern, .min, .max and .choices available.
+	Error string `yaml:"error,omitempty"`
+
+	// Step requires numbers to be a multiple of step, counted from min
+	Step float64 `yaml:"step,omitempty"`
+
+	// CaseInsensitive matches choice answers regardless of case, storing the choice as declared
+	CaseInsensitive bool `yaml:"case_insensitive,omitempty"`
+
+	// TriState lets a boolean be skipped, leaving it unset instead of false
+	TriState bool `yaml:"tri_state,omitempty"`
+
+	// Optional leaves a string or path unset when it is answered with an empty value
+	Optional bool `yaml:"optional,omitempty"`
+
+	// Advanced marks a variable most users can leave at its default. With --quick it is not
+	// prompted and takes its default; advanced variables without a default are still asked.
+	Advanced bool `yaml:"advanced,omitempty"`
+
+	// Required rejects an empty string or path. It is either a boolean or a template
+	// evaluated against earlier answers, e.g. "{{ .use_database }}"; when the template
+	// evaluates false the variable is skipped.
+	Required string `yaml:"required,omitempty"`
+
+	// Extract reads the default from a file already in the output directory, e.g. the module
+	// path of an existing go.mod. The declared default applies when nothing is found.
+	Extract *Extract `yaml:"extract,omitempty"`
+
+	// DefaultFromEnv names an environment variable whose value, when set, replaces the default
+	// offered at the prompt, e.g. GITHUB_USER
+	DefaultFromEnv string `yaml:"default_from_env,omitempty"`
+	// DefaultFromGit names a git config key whose value replaces the default when the
+	// environment has none, e.g. user.email
+	DefaultFromGit string `yaml:"default_from_git,omitempty"`
+
+	// Env names an environment variable that answers a secret without prompting when it is set,
+	// e.g. OPENAI_API_KEY
+	Env string `yaml:"env,omitempty"`
+
+	// When asks for the variable only when the template evaluates true against earlier answers,
+	// e.g. "{{ .use_database }}". Otherwise it takes its default, or stays unset without one.
+	When string `yaml:"when,omitempty"`
+
+	// DefaultWhen picks the default from the first entry whose condition holds for the implicit
+	// context and earlier answers, e.g. zsh when ._os is darwin. Default applies when none does.
+	DefaultWhen []ConditionalDefault `yaml:"default_when,omitempty"`
+
+	// Path constraints: the path must exist, and optionally be a directory or a regular file
+	MustExist bool `yaml:"must_exist,omitempty"`
+	IsDir     bool `yaml:"is_dir,omitempty"`
+	IsFile    bool `yaml:"is_file,omitempty"`
+}
+
+// ConditionalDefault is a default chosen only when its condition holds
+type ConditionalDefault struct {
+	// When is a template evaluated against the context so far, e.g. `{{ eq ._os "darwin" }}`
+	When  string `yaml:"when"`
+	Value any    `yaml:"value"`
 }
 
 // Hooks defines pre and post generation commands
 type Hooks struct {
-	PreGeneration  []string `yaml:"pre_generation,omitempty"`
-	PostGeneration []string `yaml:"post_generation,omitempty"`
+	PreGeneration  []Hook `yaml:"pre_generation,omitempty"`
+	PostGeneration []Hook `yaml:"post_generation,omitempty"`
+
+	// CheckCommands verifies every hook's program is on PATH before generation starts
+	CheckCommands bool `yaml:"check_commands,omitempty"`
+
+	// Env sets environment variables for every hook. Values are templates evaluated against
+	// the answers, e.g. GOFLAGS: "-mod=mod".
+	Env map[string]string `yaml:"env,omitempty"`
+}
+
+// Hook is a command run before or after generation. In kick.yaml it is either a command
+// string or a mapping with the command and its options.
+type Hook struct {
+	Command string `yaml:"command"`
+
+	// Interactive runs the hook attached to the terminal instead of streaming its output,
+	// for tools that prompt, such as `gh repo create` or `npm init`
+	Interactive bool `yaml:"interactive,omitempty"`
+
+	// Shell runs the command: sh, bash, zsh, cmd, powershell or pwsh. Empty uses PowerShell on
+	// Windows and sh elsewhere.
+	Shell string `yaml:"shell,omitempty"`
+
+	// Timeout stops the hook after this long, e.g. "15m"; zero uses DefaultHookTimeout
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// AllowFailure turns a failing hook into a warning instead of stopping generation
+	AllowFailure bool `yaml:"allow_failure,omitempty"`
+
+	// When is a template evaluated against the answers; the hook runs only when it renders
+	// true, e.g. "{{ .use_docker }}"
+	When string `yaml:"when,omitempty"`
+
+	// Script is the template's hook script this hook runs, e.g. "hooks/post_gen.py"; Command
+	// is set to run its rendered copy just before the hooks run
+	Script string `yaml:"-"`
+}
+
+// all returns the pre- and post-generation hooks in run order
+func (h Hooks) all() []Hook {
+	return append(append([]Hook{}, h.PreGeneration...), h.PostGeneration...)
+}
+
+// hookKeys are the keys of a hook mapping. Hook decodes itself, which the decoder's check for
+// unknown fields does not reach, so unknownKeys checks them against this list.
+var hookKeys = []string{"command", "interactive", "shell", "timeout", "allow_failure", "when"}
+
+// UnmarshalYAML accepts a plain command string as well as a hook mapping
+func (h *Hook) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*h = Hook{}
+		return node.Decode(&h.Command)
+	}
+
+	type plain Hook
+	return node.Decode((*plain)(h))
 }
 
 // TemplateSettings defines template engine configuration
 type TemplateSettings struct {
-	IgnorePatterns  []string `yaml:"ignore_patterns,omitempty"`
-	KeepPermissions bool     `yaml:"keep_permissions,omitempty"`
+	// Root renders only this subdirectory of the template, e.g. "template", so examples and
+	// docs kept next to it in the template repository are not generated
+	Root string `yaml:"root,omitempty"`
+
+	IgnorePatterns  []string       `yaml:"ignore_patterns,omitempty"`
+	KeepPermissions bool           `yaml:"keep_permissions,omitempty"`
+	Encodings       []FileEncoding `yaml:"encodings,omitempty"`
+
+	// Executable lists glob patterns of output files that are made executable, e.g. "*.sh" or
+	// "bin/*", whatever the mode of the template file
+	Executable []string `yaml:"executable,omitempty"`
+
+	// EnsureFinalNewline makes rendered text files end with exactly one newline
+	EnsureFinalNewline bool `yaml:"ensure_final_newline,omitempty"`
+
+	// Engine selects the template syntax: "go" (default) or "jinja" for Cookiecutter compatibility
+	Engine string `yaml:"engine,omitempty"`
+
+	// Delimiters replaces the {{ and }} action delimiters in file contents and names, e.g.
+	// ["<%", "%>"] for files that use {{ }} themselves
+	Delimiters []string `yaml:"delimiters,omitempty"`
+
+	// Symlinks selects how symbolic links in the template are generated: "recreate" (default)
+	// makes the same link in the output, "follow" generates what the link points to
+	Symlinks string `yaml:"symlinks,omitempty"`
+
+	// Header prepends a generated-file banner, as a comment, to rendered text files
+	Header HeaderSettings `yaml:"header,omitempty"`
+
+	// Tags groups optional files and directories by glob pattern, e.g. docs: ["docs", "*.md"],
+	// so --only and --skip can select them
+	Tags map[string][]string `yaml:"tags,omitempty"`
+
+	// RawPatterns lists glob patterns of files, or of directories whose files, are copied
+	// verbatim without template parsing, e.g. "charts" or ".github/workflows/*.yml"
+	RawPatterns []string `yaml:"raw_patterns,omitempty"`
+
+	// Files generates the files and directories matching a pattern only when a condition holds,
+	// e.g. Dockerfile only when use_docker is true
+	Files []FileCondition `yaml:"files,omitempty"`
+
+	// hooksDir is the directory of hook scripts left out of the output, when it is inside the
+	// rendered directory
+	hooksDir string
+}
+
+// FileCondition includes the entries matching Pattern only when When evaluates true
+type FileCondition struct {
+	Pattern string `yaml:"pattern"`
+	// When is a template evaluated against the answers, e.g. "{{ .use_docker }}"
+	When string `yaml:"when"`
+}
+
+// FileEncoding selects the output encoding for rendered files matching a pattern
+type FileEncoding struct {
+	Pattern  string `yaml:"pattern"`
+	Encoding string `yaml:"encoding"`
 }
 
 // ParseKickYAML parses a kick.yaml configuration file
@@ -91,9 +274,96 @@ func ParseKickYAML(data []byte) (Config, error) {
 		}
 	}
 
+	if err := validateEnvNames(config); err != nil {
+		return Config{}, err
+	}
+
+	if err := validateOverlays(config); err != nil {
+		return Config{}, fmt.Errorf("overlays: %w", err)
+	}
+
+	// Validate hooks
+	if err := validateHooks(config.Hooks); err != nil {
+		return Config{}, fmt.Errorf("hooks: %w", err)
+	}
+
+	// Validate template settings
+	if err := validateTemplateSettings(config.Template); err != nil {
+		return Config{}, fmt.Errorf("template: %w", err)
+	}
+
 	return config, nil
 }
 
+// unknownFieldPattern matches the error yaml.v3 reports for a key no struct field declares
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (.+) not found in type `)
+
+// unknownKeys lists the keys of a kick.yaml that no configuration field declares, such as a
+// misspelled "varaibles:", as "line 3: unknown key ..." messages. Plain parsing ignores them.
+// Data that is not valid YAML yields none, leaving the error to ParseKickYAML.
+func unknownKeys(data []byte) []string {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var config Config
+	var typeErr *yaml.TypeError
+	if err := dec.Decode(&config); err != nil && !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	var keys []string
+	if typeErr != nil {
+		for _, msg := range typeErr.Errors {
+			if m := unknownFieldPattern.FindStringSubmatch(msg); m != nil {
+				keys = append(keys, fmt.Sprintf("line %s: unknown key %q", m[1], m[2]))
+			}
+		}
+	}
+	return append(keys, unknownHookKeys(data)...)
+}
+
+// unknownHookKeys lists the keys of hook mappings that are not in hookKeys, such as a
+// misspelled "alow_failure:", as "line 5: unknown key ..." messages
+func unknownHookKeys(data []byte) []string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+
+	var keys []string
+	hooks := mappingValue(doc.Content[0], "hooks")
+	for _, stage := range []string{"pre_generation", "post_generation"} {
+		list := mappingValue(hooks, stage)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, hook := range list.Content {
+			if hook.Kind != yaml.MappingNode {
+				continue
+			}
+			for i := 0; i+1 < len(hook.Content); i += 2 {
+				if key := hook.Content[i]; !slices.Contains(hookKeys, key.Value) {
+					keys = append(keys, fmt.Sprintf("line %d: unknown key %q", key.Line, key.Value))
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// mappingValue returns the value of key in a mapping node, or nil when node is not a mapping
+// or has no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // GetVariableOrder returns variable names in their YAML definition order
 func (c Config) GetVariableOrder() []string {
 	if len(c.variableOrder) > 0 {
@@ -111,8 +381,12 @@ func (c Config) GetVariableOrder() []string {
 
 // Validate validates a value against the variable constraints
 func (v Variable) Validate(value any) error {
+	if v.omitted(value) {
+		return nil
+	}
+
 	switch v.Type {
-	case "string":
+	case "string", "secret":
 		str, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("expected string, got %T", value)
@@ -123,8 +397,12 @@ func (v Variable) Validate(value any) error {
 			if err != nil {
 				return fmt.Errorf("invalid pattern: %w", err)
 			}
+			if !matched && v.Type == "secret" {
+				// The message must not reveal the secret
+				return v.invalid(value, "value does not match pattern %q", v.Pattern)
+			}
 			if !matched {
-				return fmt.Errorf("value %q does not match pattern %q", str, v.Pattern)
+				return v.invalid(value, "value %q does not match pattern %q", str, v.Pattern)
 			}
 		}
 
@@ -134,11 +412,14 @@ func (v Variable) Validate(value any) error {
 			return fmt.Errorf("expected string for choice, got %T", value)
 		}
 
-		if slices.Contains(v.Choices, str) {
+		if _, ok := v.matchChoice(str); ok {
 			return nil
 		}
 
-		return fmt.Errorf("value %q is not a valid choice, must be one of %v", str, v.Choices)
+		if suggestion, ok := closestChoice(str, v.Choices); ok {
+			return v.invalid(value, "value %q is not a valid choice, must be one of %v; did you mean %q?", str, v.Choices, suggestion)
+		}
+		return v.invalid(value, "value %q is not a valid choice, must be one of %v", str, v.Choices)
 
 	case "number":
 		var num float64
@@ -152,22 +433,218 @@ func (v Variable) Validate(value any) error {
 		}
 
 		if v.Min != 0 && num < float64(v.Min) {
-			return fmt.Errorf("value %g is below minimum %d", num, v.Min)
+			return v.invalid(value, "value %g is below minimum %d", num, v.Min)
 		}
 
 		if v.Max != 0 && num > float64(v.Max) {
-			return fmt.Errorf("value %g is above maximum %d", num, v.Max)
+			return v.invalid(value, "value %g is above maximum %d", num, v.Max)
+		}
+
+		if len(v.Choices) > 0 && !slices.Contains(numericChoices(v.Choices), num) {
+			return v.invalid(value, "value %g is not a valid choice, must be one of %v", num, v.Choices)
+		}
+
+		if v.Step != 0 {
+			steps := (num - float64(v.Min)) / v.Step
+			if math.Abs(steps-math.Round(steps)) > 1e-9 {
+				return v.invalid(value, "value %g is not a multiple of step %g", num, v.Step)
+			}
 		}
 
 	case "boolean":
 		if _, ok := value.(bool); !ok {
 			return fmt.Errorf("expected boolean, got %T", value)
 		}
+
+	case "path":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected path, got %T", value)
+		}
+		return v.validatePath(str)
 	}
 
 	return nil
 }
 
+// omitted reports whether value leaves an optional variable unset
+func (v Variable) omitted(value any) bool {
+	return v.Optional && value == ""
+}
+
+// conditional reports whether the variable is only required depending on earlier answers
+func (v Variable) conditional() bool {
+	return strings.Contains(v.Required, "{{")
+}
+
+// required reports whether the variable needs a non-empty value given the answers so far
+func (v Variable) required(values map[string]any) (bool, error) {
+	if !v.conditional() {
+		return asBool(v.Required), nil
+	}
+
+	rendered, err := NewRenderer().renderString(v.Required, values)
+	if err != nil {
+		return false, fmt.Errorf("evaluate required: %w", err)
+	}
+	return asBool(rendered), nil
+}
+
+// applies reports whether the variable's when condition holds for the values collected so far
+func (v Variable) applies(values map[string]any) (bool, error) {
+	if v.When == "" {
+		return true, nil
+	}
+	rendered, err := NewRenderer().renderString(v.When, values)
+	if err != nil {
+		return false, fmt.Errorf("evaluate when: %w", err)
+	}
+	return asBool(rendered), nil
+}
+
+// conditionalDefault returns the variable with its default replaced by the value of the first
+// default_when entry whose condition holds for the values collected so far
+func (v Variable) conditionalDefault(values map[string]any) (Variable, error) {
+	for i, entry := range v.DefaultWhen {
+		rendered, err := NewRenderer().renderString(entry.When, values)
+		if err != nil {
+			return v, fmt.Errorf("evaluate default_when %d: %w", i+1, err)
+		}
+		if asBool(rendered) {
+			v.Default = entry.Value
+			break
+		}
+	}
+	return v, nil
+}
+
+// invalid returns the error for a value that fails a constraint. A custom error message
+// declared by the variable takes the place of the default one.
+func (v Variable) invalid(value any, format string, args ...any) error {
+	if v.Error == "" {
+		return fmt.Errorf(format, args...)
+	}
+
+	// The message must not reveal a secret
+	if v.Type == "secret" {
+		value = secretMask
+	}
+	msg, err := NewRenderer().renderString(v.Error, map[string]any{
+		"value":   value,
+		"pattern": v.Pattern,
+		"min":     v.Min,
+		"max":     v.Max,
+		"choices": v.Choices,
+	})
+	if err != nil {
+		return errors.New(v.Error)
+	}
+	return errors.New(msg)
+}
+
+// matchChoice returns the declared choice matching str, ignoring case when CaseInsensitive is set
+func (v Variable) matchChoice(str string) (string, bool) {
+	for _, choice := range v.Choices {
+		if choice == str || (v.CaseInsensitive && strings.EqualFold(choice, str)) {
+			return choice, true
+		}
+	}
+	return "", false
+}
+
+// closestChoice returns the choice nearest to str by edit distance, ignoring case.
+// Only likely typos are suggested: at most a third of the choice may differ.
+func closestChoice(str string, choices []string) (string, bool) {
+	best, bestDistance := "", -1
+	for _, choice := range choices {
+		distance := levenshtein(strings.ToLower(str), strings.ToLower(choice))
+		if distance > max(1, len([]rune(choice))/3) {
+			continue
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = choice, distance
+		}
+	}
+	return best, bestDistance >= 0
+}
+
+// levenshtein returns the number of single-rune insertions, deletions and substitutions turning a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// normalize converts a valid value to the form stored for the variable:
+// an absolute path for path variables and the declared spelling of a case-insensitive choice.
+func (v Variable) normalize(value any) (any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	switch v.Type {
+	case "path":
+		return normalizePath(str)
+	case "choice":
+		if choice, ok := v.matchChoice(str); ok {
+			return choice, nil
+		}
+	}
+	return value, nil
+}
+
+// validatePath checks a path against the must_exist, is_dir and is_file constraints
+func (v Variable) validatePath(path string) error {
+	if !v.MustExist && !v.IsDir && !v.IsFile {
+		return nil
+	}
+
+	normalized, err := normalizePath(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(normalized)
+	if err != nil {
+		return v.invalid(path, "path %q does not exist", path)
+	}
+	if v.IsDir && !info.IsDir() {
+		return v.invalid(path, "path %q is not a directory", path)
+	}
+	if v.IsFile && !info.Mode().IsRegular() {
+		return v.invalid(path, "path %q is not a file", path)
+	}
+	return nil
+}
+
+// normalizePath expands a leading ~ to the home directory and makes path absolute
+func normalizePath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand ~: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return filepath.Abs(path)
+}
+
 func validateVariable(_ string, variable Variable) error {
 	// Validate variable type
 	validTypes := map[string]bool{
@@ -175,10 +652,115 @@ func validateVariable(_ string, variable Variable) error {
 		"choice":  true,
 		"number":  true,
 		"boolean": true,
+		"path":    true,
+		"secret":  true,
+		"note":    true,
 	}
 
 	if !validTypes[variable.Type] {
-		return fmt.Errorf("invalid variable type %q, must be one of [string, choice, number, boolean]", variable.Type)
+		return fmt.Errorf("invalid variable type %q, must be one of [string, choice, number, boolean, path, secret, note]", variable.Type)
+	}
+
+	if variable.Type == "secret" && variable.Default != nil {
+		return fmt.Errorf("secret variables cannot have a default")
+	}
+	if variable.Env != "" && variable.Type != "secret" {
+		return fmt.Errorf("env is only supported for secret variables")
+	}
+	if (variable.DefaultFromEnv != "" || variable.DefaultFromGit != "") && (variable.Type == "secret" || variable.Type == "note") {
+		return fmt.Errorf("default_from_env and default_from_git are not supported for %s variables", variable.Type)
+	}
+	if variable.DefaultFromGit != "" && !strings.Contains(variable.DefaultFromGit, ".") {
+		return fmt.Errorf("default_from_git must be a git config key such as user.email, got %q", variable.DefaultFromGit)
+	}
+
+	if variable.When != "" {
+		if err := validateTemplateExpr(variable.When); err != nil {
+			return fmt.Errorf("invalid when expression: %w", err)
+		}
+	}
+
+	if variable.Type == "note" {
+		if strings.TrimSpace(variable.Prompt) == "" {
+			return fmt.Errorf("note requires a prompt with the text to show")
+		}
+		if err := validateTemplateExpr(variable.Prompt); err != nil {
+			return fmt.Errorf("invalid note: %w", err)
+		}
+		if variable.Default != nil || len(variable.Choices) > 0 || variable.Pattern != "" || variable.Required != "" || variable.Extract != nil || len(variable.DefaultWhen) > 0 {
+			return fmt.Errorf("note collects no value, so it takes only a prompt")
+		}
+		return nil
+	}
+
+	if variable.TriState && variable.Type != "boolean" {
+		return fmt.Errorf("tri_state is only supported for boolean type")
+	}
+
+	if variable.Optional && variable.Type != "string" && variable.Type != "path" && variable.Type != "secret" {
+		return fmt.Errorf("optional is only supported for string, path and secret types")
+	}
+
+	if variable.Required != "" {
+		if variable.Type != "string" && variable.Type != "path" && variable.Type != "secret" {
+			return fmt.Errorf("required is only supported for string, path and secret types")
+		}
+		if variable.Optional {
+			return fmt.Errorf("optional and required cannot both be set")
+		}
+		if variable.conditional() {
+			if err := validateTemplateExpr(variable.Required); err != nil {
+				return fmt.Errorf("invalid required expression: %w", err)
+			}
+		} else if _, err := strconv.ParseBool(variable.Required); err != nil {
+			return fmt.Errorf("required must be a boolean or a template expression, got %q", variable.Required)
+		}
+	}
+
+	if variable.Extract != nil {
+		if err := variable.Extract.validate(); err != nil {
+			return fmt.Errorf("extract: %w", err)
+		}
+	}
+
+	if len(variable.DefaultWhen) > 0 {
+		if variable.Type != "string" && variable.Type != "choice" {
+			return fmt.Errorf("default_when is only supported for string and choice types")
+		}
+		for i, entry := range variable.DefaultWhen {
+			if strings.TrimSpace(entry.When) == "" {
+				return fmt.Errorf("default_when %d: when is required", i+1)
+			}
+			if err := validateTemplateExpr(entry.When); err != nil {
+				return fmt.Errorf("default_when %d: invalid when expression: %w", i+1, err)
+			}
+			if entry.Value == nil {
+				return fmt.Errorf("default_when %d: value is required", i+1)
+			}
+			if variable.Type == "choice" && !slices.Contains(variable.Choices, fmt.Sprint(entry.Value)) {
+				return fmt.Errorf("default_when %d: value %q is not one of the choices", i+1, fmt.Sprint(entry.Value))
+			}
+		}
+	}
+
+	if variable.Step != 0 && variable.Type != "number" {
+		return fmt.Errorf("step is only supported for number type")
+	}
+
+	if (variable.MustExist || variable.IsDir || variable.IsFile) && variable.Type != "path" {
+		return fmt.Errorf("must_exist, is_dir and is_file are only supported for path type")
+	}
+	if variable.Error != "" {
+		if err := validateTemplateExpr(variable.Error); err != nil {
+			return fmt.Errorf("invalid error message: %w", err)
+		}
+	}
+
+	if variable.CaseInsensitive && variable.Type != "choice" {
+		return fmt.Errorf("case_insensitive is only supported for choice type")
+	}
+	if variable.IsDir && variable.IsFile {
+		return fmt.Errorf("is_dir and is_file cannot both be set")
 	}
 
 	// Type-specific validation
@@ -192,8 +774,16 @@ func validateVariable(_ string, variable Variable) error {
 		if variable.Min != 0 && variable.Max != 0 && variable.Min > variable.Max {
 			return fmt.Errorf("min cannot be greater than max")
 		}
+		if variable.Step < 0 {
+			return fmt.Errorf("step must be positive")
+		}
+		for _, choice := range variable.Choices {
+			if _, err := strconv.ParseFloat(choice, 64); err != nil {
+				return fmt.Errorf("choice %q is not a number", choice)
+			}
+		}
 
-	case "string":
+	case "string", "secret":
 		if variable.Pattern != "" {
 			_, err := regexp.Compile(variable.Pattern)
 			if err != nil {
@@ -205,6 +795,166 @@ func validateVariable(_ string, variable Variable) error {
 	return nil
 }
 
+// numericChoices parses the choices of a number variable, skipping any that are not numbers
+func numericChoices(choices []string) []float64 {
+	nums := make([]float64, 0, len(choices))
+	for _, choice := range choices {
+		if n, err := strconv.ParseFloat(choice, 64); err == nil {
+			nums = append(nums, n)
+		}
+	}
+	return nums
+}
+
+// envNamePattern matches a portable environment variable name
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateEnvNames rejects variables that would reach hooks in the same environment variable,
+// such as deploy-token and deploy_token, and hooks.env entries that take a name kick sets
+func validateEnvNames(config Config) error {
+	seen := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(config.Variables)) {
+		env := variableEnvName(name)
+		if config.Variables[name].Type == "secret" {
+			env = secretEnvName(name)
+		}
+		if other, ok := seen[env]; ok {
+			return fmt.Errorf("variables %q and %q are both passed to hooks as %s", other, name, env)
+		}
+		seen[env] = name
+	}
+	for name := range config.Hooks.Env {
+		if name == HookOutputEnv || strings.HasPrefix(name, secretEnvPrefix) {
+			return fmt.Errorf("hooks env: %s is reserved for kick", name)
+		}
+	}
+	return nil
+}
+
+// validateTemplateExpr reports a syntax error in text, a template kick renders with the
+// template functions, such as a when condition or an error message
+func validateTemplateExpr(text string) error {
+	_, err := template.New("expr").Funcs(newTemplateFuncs()).Parse(text)
+	return err
+}
+
+func validateHooks(hooks Hooks) error {
+	for name, value := range hooks.Env {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("hooks env: invalid name %q: use letters, digits and '_'", name)
+		}
+		if err := validateTemplateExpr(value); err != nil {
+			return fmt.Errorf("hooks env %s: invalid value: %w", name, err)
+		}
+	}
+	for stage, list := range map[string][]Hook{"pre_generation": hooks.PreGeneration, "post_generation": hooks.PostGeneration} {
+		for i, hook := range list {
+			if strings.TrimSpace(hook.Command) == "" {
+				return fmt.Errorf("%s hook %d: command is required", stage, i+1)
+			}
+			if hook.Shell != "" && !slices.Contains(hookShells, hook.Shell) {
+				return fmt.Errorf("%s hook %d: invalid shell %q, must be one of [%s]", stage, i+1, hook.Shell, strings.Join(hookShells, ", "))
+			}
+			if hook.Timeout < 0 {
+				return fmt.Errorf("%s hook %d: timeout must not be negative", stage, i+1)
+			}
+			if hook.When != "" {
+				if err := validateTemplateExpr(hook.When); err != nil {
+					return fmt.Errorf("%s hook %d: invalid when expression: %w", stage, i+1, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateTemplateSettings(settings TemplateSettings) error {
+	if settings.Root != "" && !filepath.IsLocal(settings.Root) {
+		return fmt.Errorf("root %q must be a relative path inside the template", settings.Root)
+	}
+
+	switch settings.Engine {
+	case "", EngineGo, EngineJinja:
+	default:
+		return fmt.Errorf("invalid engine %q, must be one of [%s, %s]", settings.Engine, EngineGo, EngineJinja)
+	}
+
+	switch settings.Symlinks {
+	case "", SymlinksRecreate, SymlinksFollow:
+	default:
+		return fmt.Errorf("invalid symlinks %q, must be one of [%s, %s]", settings.Symlinks, SymlinksRecreate, SymlinksFollow)
+	}
+
+	if settings.Delimiters != nil {
+		if len(settings.Delimiters) != 2 || settings.Delimiters[0] == "" || settings.Delimiters[1] == "" {
+			return fmt.Errorf("delimiters must be a left and a right delimiter, e.g. [\"<%%\", \"%%>\"]")
+		}
+		if settings.Engine == EngineJinja {
+			return fmt.Errorf("delimiters cannot be changed with the %s engine", EngineJinja)
+		}
+	}
+
+	if settings.Header.Text != "" {
+		if err := validateTemplateExpr(settings.Header.Text); err != nil {
+			return fmt.Errorf("invalid header: %w", err)
+		}
+	}
+	for _, pattern := range settings.Header.Skip {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid header skip pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, pattern := range settings.Executable {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid executable pattern %q: %w", pattern, err)
+		}
+	}
+
+	for _, pattern := range settings.RawPatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid raw pattern %q: %w", pattern, err)
+		}
+	}
+
+	for tag, patterns := range settings.Tags {
+		for _, pattern := range patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q for tag %q: %w", pattern, tag, err)
+			}
+		}
+	}
+
+	for i, file := range settings.Files {
+		if file.Pattern == "" {
+			return fmt.Errorf("files %d: pattern is required", i+1)
+		}
+		if _, err := filepath.Match(file.Pattern, ""); err != nil {
+			return fmt.Errorf("files %d: invalid pattern %q: %w", i+1, file.Pattern, err)
+		}
+		if strings.TrimSpace(file.When) == "" {
+			return fmt.Errorf("files %d: when is required", i+1)
+		}
+		if err := validateTemplateExpr(file.When); err != nil {
+			return fmt.Errorf("files %d: invalid when expression: %w", i+1, err)
+		}
+	}
+
+	for _, enc := range settings.Encodings {
+		if enc.Pattern == "" {
+			return fmt.Errorf("encoding pattern is required")
+		}
+		if _, err := filepath.Match(enc.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid encoding pattern %q: %w", enc.Pattern, err)
+		}
+		if _, err := lookupEncoding(enc.Encoding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // extractVariableOrder extracts the order of variables from the YAML node structure
 func extractVariableOrder(node *yaml.Node) ([]string, error) {
 	var order []string
@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -243,8 +246,133 @@ hooks:
 					},
 				},
 				Hooks: Hooks{
-					PreGeneration:  []string{"echo 'Starting generation'", "scripts/validate.sh"},
-					PostGeneration: []string{"go mod init {{.project_name}}", "echo 'Done!'"},
+					PreGeneration:  []Hook{{Command: "echo 'Starting generation'"}, {Command: "scripts/validate.sh"}},
+					PostGeneration: []Hook{{Command: "go mod init {{.project_name}}"}, {Command: "echo 'Done!'"}},
+				},
+			},
+		},
+
+		{
+			name: "interactive hook",
+			input: `name: "test"
+hooks:
+  post_generation:
+    - "git init"
+    - command: "gh repo create {{.project_name}}"
+      interactive: true`,
+			wantConfig: Config{
+				Name: "test",
+				Hooks: Hooks{
+					PostGeneration: []Hook{{Command: "git init"}, {Command: "gh repo create {{.project_name}}", Interactive: true}},
+				},
+			},
+		},
+		{
+			name: "hook with timeout, failure policy and condition",
+			input: `name: "test"
+hooks:
+  post_generation:
+    - command: "go mod tidy"
+      timeout: 15m
+      allow_failure: true
+      when: "{{ .use_go }}"`,
+			wantConfig: Config{
+				Name: "test",
+				Hooks: Hooks{
+					PostGeneration: []Hook{{Command: "go mod tidy", Timeout: 15 * time.Minute, AllowFailure: true, When: "{{ .use_go }}"}},
+				},
+			},
+		},
+		{
+			name: "hook with invalid when",
+			input: `name: "test"
+hooks:
+  pre_generation:
+    - command: "make"
+      when: "{{ .use_make"`,
+			wantErr:       true,
+			errorContains: "pre_generation hook 1: invalid when expression",
+		},
+		{
+			name: "hook with negative timeout",
+			input: `name: "test"
+hooks:
+  pre_generation:
+    - command: "make"
+      timeout: -1s`,
+			wantErr:       true,
+			errorContains: "pre_generation hook 1: timeout must not be negative",
+		},
+		{
+			name: "hooks env with invalid name",
+			input: `name: "test"
+hooks:
+  env:
+    MY-VAR: "x"`,
+			wantErr:       true,
+			errorContains: `hooks env: invalid name "MY-VAR"`,
+		},
+		{
+			name: "hooks env with a name kick sets",
+			input: `name: "test"
+hooks:
+  env:
+    KICK_SECRET_TOKEN: "x"`,
+			wantErr:       true,
+			errorContains: "hooks env: KICK_SECRET_TOKEN is reserved for kick",
+		},
+		{
+			name: "variables passed to hooks in the same environment variable",
+			input: `name: "test"
+variables:
+  deploy-token: {type: string}
+  deploy_token: {type: string}`,
+			wantErr:       true,
+			errorContains: `variables "deploy-token" and "deploy_token" are both passed to hooks as KICK_VAR_DEPLOY_TOKEN`,
+		},
+		{
+			name: "hook without command",
+			input: `name: "test"
+hooks:
+  post_generation:
+    - interactive: true`,
+			wantErr:       true,
+			errorContains: "post_generation hook 1: command is required",
+		},
+		{
+			name: "tri-state boolean variable",
+			input: `name: "test"
+variables:
+  use_cache:
+    type: boolean
+    prompt: "Enable caching?"
+    tri_state: true`,
+			wantConfig: Config{
+				Name: "test",
+				Variables: map[string]Variable{
+					"use_cache": {
+						Type:     "boolean",
+						Prompt:   "Enable caching?",
+						TriState: true,
+					},
+				},
+			},
+		},
+		{
+			name: "number variable with step and choices",
+			input: `name: "test"
+variables:
+  port:
+    type: number
+    step: 1000
+  replicas:
+    type: number
+    choices: [1, 3, 5]`,
+			wantConfig: Config{
+				Name: "test",
+				Variables: map[string]Variable{
+					"port":     {Type: "number", Step: 1000},
+					"replicas": {Type: "number", Choices: []string{"1", "3", "5"}},
 				},
 			},
 		},
@@ -313,6 +441,417 @@ variables:
 			wantErr:       true,
 			errorContains: "choices required for choice type",
 		},
+		{
+			name: "tri-state on non-boolean variable",
+			input: `name: "test"
+variables:
+  test:
+    type: string
+    tri_state: true`,
+			wantErr:       true,
+			errorContains: "tri_state is only supported for boolean type",
+		},
+		{
+			name: "number variable with non-numeric choice",
+			input: `name: "test"
+variables:
+  replicas:
+    type: number
+    choices: [1, many]`,
+			wantErr:       true,
+			errorContains: `choice "many" is not a number`,
+		},
+		{
+			name: "step on string variable",
+			input: `name: "test"
+variables:
+  test:
+    type: string
+    step: 2`,
+			wantErr:       true,
+			errorContains: "step is only supported for number type",
+		},
+		{
+			name: "path constraint on string variable",
+			input: `name: "test"
+variables:
+  test:
+    type: string
+    must_exist: true`,
+			wantErr:       true,
+			errorContains: "only supported for path type",
+		},
+		{
+			name: "case_insensitive on string variable",
+			input: `name: "test"
+variables:
+  test:
+    type: string
+    case_insensitive: true`,
+			wantErr:       true,
+			errorContains: "case_insensitive is only supported for choice type",
+		},
+		{
+			name: "error message with invalid template",
+			input: `name: "test"
+variables:
+  test:
+    type: string
+    pattern: "^[a-z]+$"
+    error: "{{ .value "`,
+			wantErr:       true,
+			errorContains: "invalid error message",
+		},
+		{
+			name: "path is both dir and file",
+			input: `name: "test"
+variables:
+  test:
+    type: path
+    is_dir: true
+    is_file: true`,
+			wantErr:       true,
+			errorContains: "is_dir and is_file cannot both be set",
+		},
+		{
+			name: "unsupported output encoding",
+			input: `name: "test"
+template:
+  encodings:
+    - pattern: "*.bat"
+      encoding: "ebcdic"`,
+			wantErr:       true,
+			errorContains: "unsupported encoding",
+		},
+		{
+			name: "conditionally required variable",
+			input: `name: "test"
+variables:
+  use_database:
+    type: boolean
+  db_host:
+    type: string
+    required: "{{ .use_database }}"
+  owner:
+    type: string
+    required: true`,
+			wantConfig: Config{
+				Name: "test",
+				Variables: map[string]Variable{
+					"use_database": {Type: "boolean"},
+					"db_host":      {Type: "string", Required: "{{ .use_database }}"},
+					"owner":        {Type: "string", Required: "true"},
+				},
+			},
+		},
+		{
+			name: "unparsable required expression",
+			input: `name: "test"
+variables:
+  db_host:
+    type: string
+    required: "{{ .use_database "`,
+			wantErr:       true,
+			errorContains: "invalid required expression",
+		},
+		{
+			name: "required is neither boolean nor expression",
+			input: `name: "test"
+variables:
+  db_host:
+    type: string
+    required: sometimes`,
+			wantErr:       true,
+			errorContains: "required must be a boolean or a template expression",
+		},
+		{
+			name: "secret with a default",
+			input: `name: "test"
+variables:
+  token:
+    type: secret
+    default: abc`,
+			wantErr:       true,
+			errorContains: "secret variables cannot have a default",
+		},
+		{
+			name: "default_when on a choice",
+			input: `name: "test"
+variables:
+  shell:
+    type: choice
+    choices: [bash, zsh]
+    default: bash
+    default_when:
+      - when: '{{ eq ._os "darwin" }}'
+        value: zsh`,
+			wantConfig: Config{
+				Name: "test",
+				Variables: map[string]Variable{
+					"shell": {
+						Type:        "choice",
+						Choices:     []string{"bash", "zsh"},
+						Default:     "bash",
+						DefaultWhen: []ConditionalDefault{{When: `{{ eq ._os "darwin" }}`, Value: "zsh"}},
+					},
+				},
+			},
+		},
+		{
+			name: "default_when on a number",
+			input: `name: "test"
+variables:
+  port:
+    type: number
+    default_when:
+      - when: "{{ true }}"
+        value: 80`,
+			wantErr:       true,
+			errorContains: "default_when is only supported for string and choice types",
+		},
+		{
+			name: "default_when with an invalid expression",
+			input: `name: "test"
+variables:
+  shell:
+    type: string
+    default_when:
+      - when: "{{ eq ._os "
+        value: zsh`,
+			wantErr:       true,
+			errorContains: "default_when 1: invalid when expression",
+		},
+		{
+			name: "default_when value outside the choices",
+			input: `name: "test"
+variables:
+  shell:
+    type: choice
+    choices: [bash, zsh]
+    default_when:
+      - when: "{{ true }}"
+        value: fish`,
+			wantErr:       true,
+			errorContains: `default_when 1: value "fish" is not one of the choices`,
+		},
+		{
+			name: "default_when without a value",
+			input: `name: "test"
+variables:
+  shell:
+    type: string
+    default_when:
+      - when: "{{ true }}"`,
+			wantErr:       true,
+			errorContains: "default_when 1: value is required",
+		},
+		{
+			name: "note without text",
+			input: `name: "test"
+variables:
+  intro:
+    type: note`,
+			wantErr:       true,
+			errorContains: "note requires a prompt with the text to show",
+		},
+		{
+			name: "note with a default",
+			input: `name: "test"
+variables:
+  intro:
+    type: note
+    prompt: "Database settings"
+    default: x`,
+			wantErr:       true,
+			errorContains: "note collects no value, so it takes only a prompt",
+		},
+		{
+			name: "extract with both pattern and key",
+			input: `name: "test"
+variables:
+  module:
+    type: string
+    extract:
+      file: go.mod
+      pattern: "module (\\S+)"
+      key: module`,
+			wantErr:       true,
+			errorContains: "extract: exactly one of pattern and key is required",
+		},
+		{
+			name: "extract outside the output directory",
+			input: `name: "test"
+variables:
+  module:
+    type: string
+    extract:
+      file: ../go.mod
+      pattern: "module (\\S+)"`,
+			wantErr:       true,
+			errorContains: `extract: file "../go.mod" must be relative to the output directory`,
+		},
+		{
+			name: "overlay for a variable asked before the selector",
+			input: `name: "test"
+variables:
+  replicas:
+    type: number
+  environment:
+    type: choice
+    choices: [dev, prod]
+overlays:
+  selector: environment
+  values:
+    prod:
+      replicas:
+        default: 3`,
+			wantErr:       true,
+			errorContains: `"replicas" must be declared after "environment"`,
+		},
+		{
+			name: "overlay for an unknown choice",
+			input: `name: "test"
+variables:
+  environment:
+    type: choice
+    choices: [dev, prod]
+  replicas:
+    type: number
+overlays:
+  selector: environment
+  values:
+    staging:
+      replicas:
+        default: 2`,
+			wantErr:       true,
+			errorContains: `overlay "staging" is not a choice of "environment"`,
+		},
+		{
+			name: "overlay selector not declared",
+			input: `name: "test"
+variables:
+  replicas:
+    type: number
+overlays:
+  selector: environment
+  values:
+    prod:
+      replicas:
+        default: 3`,
+			wantErr:       true,
+			errorContains: `selector "environment" is not a declared variable`,
+		},
+		{
+			name: "optional number variable",
+			input: `name: "test"
+variables:
+  test:
+    type: number
+    optional: true`,
+			wantErr:       true,
+			errorContains: "optional is only supported for string, path and secret types",
+		},
+		{
+			name: "template root outside the template",
+			input: `name: "test"
+template:
+  root: "../shared"`,
+			wantErr:       true,
+			errorContains: "must be a relative path inside the template",
+		},
+		{
+			name: "default_from_env on a secret",
+			input: `name: "test"
+variables:
+  token:
+    type: secret
+    default_from_env: TOKEN`,
+			wantErr:       true,
+			errorContains: "default_from_env and default_from_git are not supported for secret variables",
+		},
+		{
+			name: "env on a non-secret variable",
+			input: `name: "test"
+variables:
+  user:
+    type: string
+    env: USER`,
+			wantErr:       true,
+			errorContains: "env is only supported for secret variables",
+		},
+		{
+			name: "variable with invalid when",
+			input: `name: "test"
+variables:
+  db_port:
+    type: number
+    default: 5432
+    when: "{{ .use_database"`,
+			wantErr:       true,
+			errorContains: "invalid when expression",
+		},
+		{
+			name: "files condition without when",
+			input: `name: "test"
+template:
+  files:
+    - pattern: Dockerfile`,
+			wantErr:       true,
+			errorContains: "files 1: when is required",
+		},
+		{
+			name: "files condition with invalid when",
+			input: `name: "test"
+template:
+  files:
+    - pattern: Dockerfile
+      when: "{{ .use_docker"`,
+			wantErr:       true,
+			errorContains: "files 1: invalid when expression",
+		},
+		{
+			name: "invalid raw pattern",
+			input: `name: "test"
+template:
+  raw_patterns: ["charts/["]`,
+			wantErr:       true,
+			errorContains: `invalid raw pattern "charts/["`,
+		},
+		{
+			name: "delimiters without a right delimiter",
+			input: `name: "test"
+template:
+  delimiters: ["<%"]`,
+			wantErr:       true,
+			errorContains: "delimiters must be a left and a right delimiter",
+		},
+		{
+			name: "hook with unknown shell",
+			input: `name: "test"
+hooks:
+  post_generation:
+    - command: "echo hi"
+      shell: fish`,
+			wantErr:       true,
+			errorContains: `post_generation hook 1: invalid shell "fish"`,
+		},
+		{
+			name: "invalid symlinks",
+			input: `name: "test"
+template:
+  symlinks: copy`,
+			wantErr:       true,
+			errorContains: `invalid symlinks "copy"`,
+		},
+		{
+			name: "delimiters with the jinja engine",
+			input: `name: "test"
+template:
+  engine: jinja
+  delimiters: ["<%", "%>"]`,
+			wantErr:       true,
+			errorContains: "delimiters cannot be changed with the jinja engine",
+		},
 		{
 			name: "number variable with invalid min/max",
 			input: `name: "test"
@@ -412,6 +951,18 @@ func TestVariable_Validate(t *testing.T) {
 			wantErr: true,
 		},
 
+		{
+			name:     "case-insensitive choice",
+			variable: Variable{Type: "choice", Choices: []string{"postgres", "mysql"}, CaseInsensitive: true},
+			value:    "Postgres",
+		},
+		{
+			name:     "choice is case-sensitive by default",
+			variable: Variable{Type: "choice", Choices: []string{"postgres", "mysql"}},
+			value:    "Postgres",
+			wantErr:  true,
+		},
+
 		// Number validation
 		{
 			name: "valid number in range",
@@ -443,6 +994,39 @@ func TestVariable_Validate(t *testing.T) {
 			wantErr: true,
 		},
 
+		{
+			name:     "number multiple of step",
+			variable: Variable{Type: "number", Step: 1000},
+			value:    8000,
+		},
+		{
+			name:     "number not a multiple of step",
+			variable: Variable{Type: "number", Step: 1000},
+			value:    8080,
+			wantErr:  true,
+		},
+		{
+			name:     "step counted from min",
+			variable: Variable{Type: "number", Min: 1, Step: 2},
+			value:    5,
+		},
+		{
+			name:     "fractional step",
+			variable: Variable{Type: "number", Step: 0.1},
+			value:    0.3,
+		},
+		{
+			name:     "number in discrete set",
+			variable: Variable{Type: "number", Choices: []string{"1", "3", "5"}},
+			value:    3.0,
+		},
+		{
+			name:     "number outside discrete set",
+			variable: Variable{Type: "number", Choices: []string{"1", "3", "5"}},
+			value:    2,
+			wantErr:  true,
+		},
+
 		// Boolean validation
 		{
 			name: "valid boolean true",
@@ -542,3 +1126,154 @@ variables:
 		})
 	}
 }
+
+func TestVariable_ValidateErrorMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable Variable
+		value    any
+		wantErr  string
+	}{
+		{
+			name:     "pattern",
+			variable: Variable{Type: "string", Pattern: "^[a-z0-9-]+$", Error: "{{ .value }} is not a valid DNS label"},
+			value:    "My_Service",
+			wantErr:  "My_Service is not a valid DNS label",
+		},
+		{
+			name:     "range",
+			variable: Variable{Type: "number", Min: 1024, Max: 65535, Error: "port must be between {{ .min }} and {{ .max }}"},
+			value:    80,
+			wantErr:  "port must be between 1024 and 65535",
+		},
+		{
+			name:     "choice",
+			variable: Variable{Type: "choice", Choices: []string{"postgres", "mysql"}, Error: "pick one of {{ .choices }}"},
+			value:    "oracle",
+			wantErr:  "pick one of [postgres mysql]",
+		},
+		{
+			name:     "secret is masked",
+			variable: Variable{Type: "secret", Pattern: "^.{8,}$", Error: "{{ .value }} is too short"},
+			value:    "s3cr3t",
+			wantErr:  secretMask + " is too short",
+		},
+		{
+			name:     "type mismatch keeps default message",
+			variable: Variable{Type: "number", Error: "custom"},
+			value:    "abc",
+			wantErr:  "expected number",
+		},
+		{
+			name:     "choice suggests the closest match",
+			variable: Variable{Type: "choice", Choices: []string{"postgres", "mysql"}},
+			value:    "postgress",
+			wantErr:  `must be one of [postgres mysql]; did you mean "postgres"?`,
+		},
+		{
+			name:     "no custom message",
+			variable: Variable{Type: "string", Pattern: "^[a-z]+$"},
+			value:    "ABC",
+			wantErr:  `value "ABC" does not match pattern "^[a-z]+$"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.variable.Validate(tt.value)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestClosestChoice(t *testing.T) {
+	choices := []string{"postgres", "mysql", "sqlite"}
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{input: "postgress", want: "postgres", ok: true},
+		{input: "Postgre", want: "postgres", ok: true},
+		{input: "mysq", want: "mysql", ok: true},
+		{input: "sqlit3", want: "sqlite", ok: true},
+		{input: "oracle"},
+		{input: "my"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := closestChoice(tt.input, choices)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"postgres", "postgress", 1},
+		{"héllo", "hello", 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, levenshtein(tt.a, tt.b), "%q → %q", tt.a, tt.b)
+	}
+}
+
+func TestVariable_ValidatePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(file, []byte("key"), 0600))
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name     string
+		variable Variable
+		value    any
+		wantErr  string
+	}{
+		{name: "unconstrained missing path", variable: Variable{Type: "path"}, value: missing},
+		{name: "existing file", variable: Variable{Type: "path", MustExist: true}, value: file},
+		{name: "missing path", variable: Variable{Type: "path", MustExist: true}, value: missing, wantErr: "does not exist"},
+		{name: "directory", variable: Variable{Type: "path", IsDir: true}, value: dir},
+		{name: "file is not a directory", variable: Variable{Type: "path", IsDir: true}, value: file, wantErr: "is not a directory"},
+		{name: "regular file", variable: Variable{Type: "path", IsFile: true}, value: file},
+		{name: "directory is not a file", variable: Variable{Type: "path", IsFile: true}, value: dir, wantErr: "is not a file"},
+		{name: "not a string", variable: Variable{Type: "path"}, value: 42, wantErr: "expected path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.variable.Validate(tt.value)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	got, err := normalizePath("~/keys/id.pem")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "keys", "id.pem"), got)
+
+	got, err = normalizePath("configs")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(wd, "configs"), got)
+}
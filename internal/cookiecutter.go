@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CookiecutterJSON is the configuration of a Cookiecutter template. kick reads it when a
+// template has no kick.yaml.
+const CookiecutterJSON = "cookiecutter.json"
+
+// ParseCookiecutterJSON converts a cookiecutter.json into a configuration that renders with the
+// Jinja engine. Keys become variables in file order: strings are string variables, lists are
+// choices defaulting to their first item, booleans and numbers keep their type. String defaults
+// are Jinja, e.g. "{{ cookiecutter.project_name.lower() }}". Prompts come from __prompts__ and
+// otherwise are the variable names, as in Cookiecutter. Other keys starting with _ hold
+// Cookiecutter settings and are ignored.
+func ParseCookiecutterJSON(data []byte) (Config, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Config{}, fmt.Errorf("json: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return Config{}, fmt.Errorf("json: expected an object")
+	}
+	root := doc.Content[0]
+
+	prompts := cookiecutterPrompts(root)
+	cfg := Config{
+		Name:      "cookiecutter",
+		Variables: map[string]Variable{},
+		Template:  TemplateSettings{Engine: EngineJinja},
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name, value := root.Content[i].Value, root.Content[i+1]
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+
+		variable, err := cookiecutterVariable(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("variable %q: %w", name, err)
+		}
+		variable.Prompt = name
+		if prompt := prompts[name]; prompt != "" {
+			variable.Prompt = prompt
+		}
+		if err := validateVariable(name, variable); err != nil {
+			return Config{}, fmt.Errorf("variable %q: %w", name, err)
+		}
+		cfg.Variables[name] = variable
+		cfg.variableOrder = append(cfg.variableOrder, name)
+	}
+	return cfg, nil
+}
+
+// cookiecutterVariable converts the value of one cookiecutter.json key into a variable
+func cookiecutterVariable(node *yaml.Node) (Variable, error) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var items []any
+		if err := node.Decode(&items); err != nil {
+			return Variable{}, err
+		}
+		if len(items) == 0 {
+			return Variable{}, fmt.Errorf("choice list is empty")
+		}
+		choices := make([]string, len(items))
+		for i, item := range items {
+			choices[i] = fmt.Sprint(item)
+		}
+		return Variable{Type: "choice", Choices: choices, Default: choices[0]}, nil
+
+	case yaml.ScalarNode:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return Variable{}, err
+		}
+		switch v := value.(type) {
+		case bool:
+			return Variable{Type: "boolean", Default: v}, nil
+		case int, float64:
+			return Variable{Type: "number", Default: v}, nil
+		case nil:
+			return Variable{Type: "string"}, nil
+		}
+
+		def := node.Value
+		if strings.Contains(def, "{{") || strings.Contains(def, "{%") {
+			// Defaults are rendered with the answers so far, which are not under a cookiecutter map
+			translated, err := (&jinjaTranslator{flatten: true}).translate(def)
+			if err != nil {
+				return Variable{}, fmt.Errorf("default: %w", err)
+			}
+			def = translated
+		}
+		return Variable{Type: "string", Default: def}, nil
+
+	default:
+		return Variable{}, fmt.Errorf("dictionary variables are not supported")
+	}
+}
+
+// cookiecutterPrompts reads the __prompts__ object, whose entries are either the prompt text
+// or, for choices, an object with the text under __prompt__
+func cookiecutterPrompts(root *yaml.Node) map[string]string {
+	prompts := map[string]string{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "__prompts__" {
+			continue
+		}
+		var raw map[string]any
+		if err := root.Content[i+1].Decode(&raw); err != nil {
+			return prompts
+		}
+		for name, prompt := range raw {
+			switch p := prompt.(type) {
+			case string:
+				prompts[name] = p
+			case map[string]any:
+				if text, ok := p["__prompt__"].(string); ok {
+					prompts[name] = text
+				}
+			}
+		}
+	}
+	return prompts
+}
+
+// loadCookiecutter reads the cookiecutter.json of a template without a kick.yaml. It reports
+// false when there is none. Cookiecutter renders only the directory whose name refers to a
+// variable, such as {{cookiecutter.project_slug}}, so that directory becomes the template root
+// and its contents are generated straight into the output directory.
+func loadCookiecutter(templatePath string) (Config, bool, error) {
+	path := filepath.Join(templatePath, CookiecutterJSON)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{}, false, nil
+	}
+	if err != nil {
+		return Config{}, true, fmt.Errorf("read %s: %v", path, err)
+	}
+
+	cfg, err := ParseCookiecutterJSON(data)
+	if err != nil {
+		return Config{}, true, fmt.Errorf("parse %s: %v", CookiecutterJSON, err)
+	}
+	cfg.Name = filepath.Base(templatePath)
+
+	entries, err := os.ReadDir(templatePath)
+	if err != nil {
+		return Config{}, true, fmt.Errorf("read template: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.Contains(entry.Name(), "{{") && strings.Contains(entry.Name(), "cookiecutter.") {
+			cfg.Template.Root = entry.Name()
+			break
+		}
+	}
+	if cfg.Template.Root == "" {
+		return Config{}, true, fmt.Errorf("cookiecutter template has no {{cookiecutter.*}} project directory")
+	}
+	return cfg, true, nil
+}
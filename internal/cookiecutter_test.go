@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCookiecutterJSON(t *testing.T) {
+	cfg, err := ParseCookiecutterJSON([]byte(`{
+  "project_name": "My Project",
+  "project_slug": "{{ cookiecutter.project_name.lower().replace(' ', '-') }}",
+  "license": ["MIT", "BSD-3", "GPL-3.0"],
+  "use_docker": true,
+  "port": 8000,
+  "_copy_without_render": ["*.html"],
+  "__prompts__": {"project_name": "Project name", "license": {"__prompt__": "Which license?", "MIT": "MIT License"}}
+}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"project_name", "project_slug", "license", "use_docker", "port"}, cfg.GetVariableOrder())
+	assert.Equal(t, EngineJinja, cfg.Template.Engine)
+	assert.Equal(t, map[string]Variable{
+		"project_name": {Type: "string", Prompt: "Project name", Default: "My Project"},
+		"project_slug": {Type: "string", Prompt: "project_slug", Default: `{{ (replace (lower .project_name) " " "-") }}`},
+		"license":      {Type: "choice", Prompt: "Which license?", Choices: []string{"MIT", "BSD-3", "GPL-3.0"}, Default: "MIT"},
+		"use_docker":   {Type: "boolean", Prompt: "use_docker", Default: true},
+		"port":         {Type: "number", Prompt: "port", Default: 8000},
+	}, cfg.Variables)
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			input   string
+			wantErr string
+		}{
+			{name: "not an object", input: `["a"]`, wantErr: "expected an object"},
+			{name: "dictionary variable", input: `{"db": {"host": "localhost"}}`, wantErr: `variable "db": dictionary variables are not supported`},
+			{name: "empty choices", input: `{"license": []}`, wantErr: `variable "license": choice list is empty`},
+			{name: "unsupported default", input: `{"a": "{{ cookiecutter.b.split() }}"}`, wantErr: `variable "a": default`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := ParseCookiecutterJSON([]byte(tt.input))
+				assert.ErrorContains(t, err, tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestGenerate_Cookiecutter(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, CookiecutterJSON), []byte(`{
+  "project_name": "My Project",
+  "project_slug": "{{ cookiecutter.project_name.lower().replace(' ', '_') }}",
+  "use_docker": ["y", "n"]
+}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("about the template"), 0644))
+	project := filepath.Join(src, "{{cookiecutter.project_slug}}")
+	require.NoError(t, os.MkdirAll(filepath.Join(project, "{{ cookiecutter.project_slug }}"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "README.md"),
+		[]byte("# {{ cookiecutter.project_name }}\n{% if cookiecutter.use_docker == 'y' %}docker{% endif %}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "{{ cookiecutter.project_slug }}", "__init__.py"), []byte(""), 0644))
+
+	out := t.TempDir()
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"project_name": "Demo App", "use_docker": "y"}, StrictConfig: true}))
+
+	content, err := os.ReadFile(filepath.Join(out, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Demo App\ndocker\n", string(content))
+	assert.FileExists(t, filepath.Join(out, "demo_app", "__init__.py"))
+}
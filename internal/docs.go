@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Output formats of VariableDocs
+const (
+	DocsFormatMarkdown = "md"
+	DocsFormatTable    = "table"
+)
+
+// VariableDocs resolves a template and describes its variables as a table in prompt order:
+// a Markdown table for the template's README, or an aligned plain-text table for a terminal
+func VariableDocs(source, token, format string) (string, error) {
+	if format != DocsFormatMarkdown && format != DocsFormatTable {
+		return "", fmt.Errorf("unsupported format %q, must be one of [%s, %s]", format, DocsFormatMarkdown, DocsFormatTable)
+	}
+	cfg, err := resolveConfig(source, token)
+	if err != nil {
+		return "", err
+	}
+	return formatVariableDocs(cfg, format), nil
+}
+
+// variableDocHeader names the columns of the variables table
+var variableDocHeader = []string{"Name", "Type", "Default", "Description", "Choices"}
+
+// variableDocRows returns one row per variable in prompt order. Notes collect no value and
+// are left out; the description is the help text, or the prompt when there is none.
+func variableDocRows(cfg Config) [][]string {
+	var rows [][]string
+	for _, name := range cfg.GetVariableOrder() {
+		variable := cfg.Variables[name]
+		if variable.Type == "note" {
+			continue
+		}
+
+		description := variable.Help
+		if description == "" {
+			description = variable.Prompt
+		}
+		var def string
+		if variable.Default != nil {
+			def = fmt.Sprint(variable.Default)
+		}
+		rows = append(rows, []string{name, variable.Type, def, description, strings.Join(variable.Choices, ", ")})
+	}
+	return rows
+}
+
+// formatVariableDocs renders the variables table in format
+func formatVariableDocs(cfg Config, format string) string {
+	rows := variableDocRows(cfg)
+	if format == DocsFormatTable {
+		return alignedTable(variableDocHeader, rows)
+	}
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(variableDocHeader, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat("---|", len(variableDocHeader)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = markdownCell(cell)
+		}
+		// Names and defaults are literal values, so they read best as code
+		cells[0] = "`" + row[0] + "`"
+		if row[2] != "" {
+			cells[2] = "`" + markdownCell(row[2]) + "`"
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return b.String()
+}
+
+// markdownCell escapes text for a Markdown table cell, which cannot hold a pipe or a line break
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// alignedTable lays rows out in columns separated by two spaces
+func alignedTable(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range append([][]string{header}, rows...) {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString("  ")
+			}
+			line.WriteString(cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariableDocs(t *testing.T) {
+	src := writeTemplate(t, `name: service
+variables:
+  project_name:
+    type: string
+    prompt: Project name
+  intro:
+    type: note
+    prompt: Database settings follow
+  database:
+    type: choice
+    prompt: Database
+    help: Storage used by the service | pick none to skip it
+    choices: [postgres, none]
+    default: postgres
+  port:
+    type: number
+    default: 8080
+`, nil)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: DocsFormatMarkdown,
+			want: "| Name | Type | Default | Description | Choices |\n" +
+				"|---|---|---|---|---|\n" +
+				"| `project_name` | string |  | Project name |  |\n" +
+				"| `database` | choice | `postgres` | Storage used by the service \\| pick none to skip it | postgres, none |\n" +
+				"| `port` | number | `8080` |  |  |\n",
+		},
+		{
+			format: DocsFormatTable,
+			want: "Name          Type    Default   Description                                         Choices\n" +
+				"project_name  string            Project name\n" +
+				"database      choice  postgres  Storage used by the service | pick none to skip it  postgres, none\n" +
+				"port          number  8080\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := VariableDocs(src, "", tt.format)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := VariableDocs(src, "", "html")
+		assert.ErrorContains(t, err, `unsupported format "html", must be one of [md, table]`)
+	})
+}
@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dumpContext writes the render context of a failed run as YAML to path, or to stderr when
+// path is "-", so the failure can be reproduced with the exact data the template received.
+// Secrets are redacted.
+func dumpContext(path string, variables map[string]Variable, data map[string]any) error {
+	out, err := yaml.Marshal(redactSecrets(variables, data))
+	if err != nil {
+		return fmt.Errorf("marshal context: %w", err)
+	}
+
+	if path == "-" {
+		_, _ = fmt.Fprintf(os.Stderr, "render context:\n%s", out)
+		return nil
+	}
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		return fmt.Errorf("write context: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// defaultEncoding is used for rendered files that match no encoding rule.
+const defaultEncoding = "utf-8"
+
+// outputEncodings lists the supported output encodings by name.
+var outputEncodings = map[string]encoding.Encoding{
+	"utf-8":        unicode.UTF8,
+	"utf-8-bom":    unicode.UTF8BOM,
+	"utf-16le":     unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	"utf-16be":     unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	"utf-16le-bom": unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16be-bom": unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	"windows-1252": charmap.Windows1252,
+	"iso-8859-1":   charmap.ISO8859_1,
+}
+
+// utf8BOM is the byte order mark editors on Windows often put at the start of UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// stripBOM removes a leading UTF-8 byte order mark, so it is not rendered into the output
+// as literal text. The utf-8-bom encoding adds it back to files that should keep one.
+func stripBOM(content []byte) []byte {
+	return bytes.TrimPrefix(content, utf8BOM)
+}
+
+// lookupEncoding returns the encoding registered under name.
+// An empty name selects the default UTF-8 encoding.
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		key = defaultEncoding
+	}
+
+	enc, ok := outputEncodings[key]
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding %q", name)
+	}
+	return enc, nil
+}
+
+// encodingFor returns the encoding name of the first rule matching relPath.
+func encodingFor(relPath string, settings TemplateSettings) string {
+	for _, rule := range settings.Encodings {
+		if matchesPattern(rule.Pattern, relPath) {
+			return rule.Encoding
+		}
+	}
+	return defaultEncoding
+}
+
+// encodeOutput converts UTF-8 content to the named encoding.
+func encodeOutput(content []byte, name string) ([]byte, error) {
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	if enc == unicode.UTF8 {
+		return content, nil
+	}
+
+	out, err := enc.NewEncoder().Bytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("encode as %s: %w", name, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+)
+
+// environmentDefaults returns variables with the defaults of those declaring default_from_env
+// or default_from_git replaced by the value the machine already knows, e.g. GITHUB_USER or the
+// user.email of the global git config. The environment wins over git config. Values the
+// variable does not accept leave the default alone and are described in the returned warnings.
+func environmentDefaults(variables map[string]Variable) (map[string]Variable, []string) {
+	names := make([]string, 0, len(variables))
+	for name, variable := range variables {
+		if variable.DefaultFromEnv != "" || variable.DefaultFromGit != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return variables, nil
+	}
+	sort.Strings(names)
+
+	gitConfig, _ := config.LoadConfig(config.GlobalScope)
+	updated := maps.Clone(variables)
+	var warnings []string
+	for _, name := range names {
+		variable := updated[name]
+		raw, from := os.Getenv(variable.DefaultFromEnv), "$"+variable.DefaultFromEnv
+		if variable.DefaultFromEnv == "" || raw == "" {
+			raw, from = gitConfigValue(gitConfig, variable.DefaultFromGit), "git config "+variable.DefaultFromGit
+		}
+		if raw == "" {
+			continue
+		}
+
+		value, err := coerceValue(variable, strings.TrimSpace(raw))
+		if err == nil {
+			err = variable.Validate(value)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("variable %q: default from %s: %v", name, from, err))
+			continue
+		}
+		// String defaults are templates, so text that looks like one is quoted
+		if str, ok := value.(string); ok && strings.Contains(str, "{{") {
+			value = "{{ " + strconv.Quote(str) + " }}"
+		}
+		variable.Default = value
+		// The machine's value takes precedence over conditional defaults as well
+		variable.DefaultWhen = nil
+		updated[name] = variable
+	}
+	return updated, warnings
+}
+
+// gitConfigValue looks up a key such as user.email or remote.origin.url in git config,
+// returning "" when it is not set
+func gitConfigValue(cfg *config.Config, key string) string {
+	if cfg == nil || key == "" {
+		return ""
+	}
+	section, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return ""
+	}
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		return cfg.Raw.Section(section).Subsection(rest[:i]).Option(rest[i+1:])
+	}
+	return cfg.Raw.Section(section).Option(rest)
+}
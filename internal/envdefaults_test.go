@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"),
+		[]byte("[user]\n\tname = Ada Lovelace\n\temail = ada@example.com\n[remote \"origin\"]\n\turl = https://example.com/repo.git\n"), 0o644))
+	t.Setenv("TEST_KICK_USER", "ada-gh")
+	t.Setenv("TEST_KICK_PORT", "not a number")
+
+	variables := map[string]Variable{
+		"github_user":  {Type: "string", Default: "me", DefaultFromEnv: "TEST_KICK_USER"},
+		"author_name":  {Type: "string", DefaultFromEnv: "TEST_KICK_UNSET", DefaultFromGit: "user.name"},
+		"author_email": {Type: "string", DefaultFromGit: "user.email"},
+		"remote":       {Type: "string", DefaultFromGit: "remote.origin.url"},
+		"missing":      {Type: "string", Default: "kept", DefaultFromGit: "user.signingkey"},
+		"port":         {Type: "number", Default: 8080, DefaultFromEnv: "TEST_KICK_PORT"},
+	}
+
+	got, warnings := environmentDefaults(variables)
+	assert.Equal(t, "ada-gh", got["github_user"].Default)
+	assert.Equal(t, "Ada Lovelace", got["author_name"].Default, "git config fills in when the environment has nothing")
+	assert.Equal(t, "ada@example.com", got["author_email"].Default)
+	assert.Equal(t, "https://example.com/repo.git", got["remote"].Default)
+	assert.Equal(t, "kept", got["missing"].Default)
+	assert.Equal(t, 8080, got["port"].Default)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], `variable "port": default from $TEST_KICK_PORT`)
+	assert.Equal(t, "me", variables["github_user"].Default, "the declared variables are not modified")
+}
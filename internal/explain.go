@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Explanation records what the renderer did with one template entry and which rule decided it
+type Explanation struct {
+	Path    string // slash-separated path relative to the template root
+	Outcome string // "rendered", "copied", "linked", "created", "unchanged", "kept", "conflict", "skipped" or "failed"
+	Reason  string // the responsible rule, e.g. `ignore pattern "*.tmp"`; empty for plain renders
+}
+
+func (e Explanation) String() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%-9s %s", e.Outcome, e.Path)
+	}
+	return fmt.Sprintf("%-9s %s (%s)", e.Outcome, e.Path, e.Reason)
+}
+
+// explain records the outcome of a template entry
+func (r *Renderer) explain(rel, outcome, reason string) {
+	r.explanations = append(r.explanations, Explanation{Path: filepath.ToSlash(rel), Outcome: outcome, Reason: reason})
+}
+
+// explainWrite records whether a written file was rendered or copied verbatim
+func (r *Renderer) explainWrite(f fileTarget) {
+	if f.verbatim != "" {
+		r.explain(f.rel, "copied", f.verbatim)
+		return
+	}
+	r.explain(f.rel, "rendered", "")
+}
+
+// showExplanations prints the outcome of every template entry
+func showExplanations(explanations []Explanation) {
+	for _, e := range explanations {
+		_, _ = fmt.Fprintln(os.Stdout, e)
+	}
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Extract reads a variable's default from a file already in the output directory, so
+// regenerating over an existing project suggests the values it already uses
+type Extract struct {
+	// File is the slash-separated path of the file relative to the output directory
+	File string `yaml:"file"`
+	// Pattern is a regular expression matched against the file; the first capture group,
+	// or the whole match when it has none, becomes the default
+	Pattern string `yaml:"pattern,omitempty"`
+	// Key is a dot-separated path into a JSON or YAML file, e.g. "name" or "engines.node";
+	// list elements are addressed by index
+	Key string `yaml:"key,omitempty"`
+}
+
+// validate checks that an extract rule names a file inside the output directory and exactly
+// one way of reading it
+func (e Extract) validate() error {
+	if e.File == "" {
+		return fmt.Errorf("file is required")
+	}
+	if path.IsAbs(e.File) || filepath.IsAbs(e.File) || strings.HasPrefix(path.Clean(e.File), "..") {
+		return fmt.Errorf("file %q must be relative to the output directory", e.File)
+	}
+	if (e.Pattern == "") == (e.Key == "") {
+		return fmt.Errorf("exactly one of pattern and key is required")
+	}
+	if e.Pattern != "" {
+		if _, err := regexp.Compile(e.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// value reads the extracted text from the output directory. It reports false when the file
+// does not exist or holds no match.
+func (e Extract) value(outputDir string) (string, bool, error) {
+	content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(e.File)))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	if e.Pattern != "" {
+		match := regexp.MustCompile(e.Pattern).FindSubmatch(content)
+		switch {
+		case match == nil:
+			return "", false, nil
+		case len(match) > 1:
+			return string(match[1]), true, nil
+		default:
+			return string(match[0]), true, nil
+		}
+	}
+
+	// YAML is a superset of JSON, so one parser reads both
+	var doc any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return "", false, fmt.Errorf("parse %s: %w", e.File, err)
+	}
+	for _, key := range strings.Split(e.Key, ".") {
+		switch node := doc.(type) {
+		case map[string]any:
+			doc = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return "", false, nil
+			}
+			doc = node[i]
+		default:
+			return "", false, nil
+		}
+	}
+	switch doc.(type) {
+	case nil:
+		return "", false, nil
+	case map[string]any, []any:
+		return "", false, fmt.Errorf("%s in %s is not a single value", e.Key, e.File)
+	}
+	return fmt.Sprint(doc), true, nil
+}
+
+// extractDefaults returns variables with the defaults of those declaring an extract rule
+// replaced by the value read from the output directory. Rules that cannot be applied leave
+// the default alone and are described in the returned warnings.
+func extractDefaults(variables map[string]Variable, outputDir string) (map[string]Variable, []string) {
+	names := make([]string, 0, len(variables))
+	for name, variable := range variables {
+		if variable.Extract != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return variables, nil
+	}
+	sort.Strings(names)
+
+	extracted := maps.Clone(variables)
+	var warnings []string
+	for _, name := range names {
+		variable := extracted[name]
+		raw, ok, err := variable.Extract.value(outputDir)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("variable %q: extract default: %v", name, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		value, err := coerceValue(variable, strings.TrimSpace(raw))
+		if err == nil {
+			err = variable.Validate(value)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("variable %q: extract default: %v", name, err))
+			continue
+		}
+		// String defaults are templates, so text that looks like one is quoted
+		if str, ok := value.(string); ok && strings.Contains(str, "{{") {
+			value = "{{ " + strconv.Quote(str) + " }}"
+		}
+		variable.Default = value
+		extracted[name] = variable
+	}
+	return extracted, warnings
+}
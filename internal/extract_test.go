@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDefaults(t *testing.T) {
+	out := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module github.com/acme/widget\n\ngo 1.24\n",
+		"package.json": `{"name": "widget", "engines": {"node": "20"}, "workspaces": ["a", "b"]}`,
+		"chart.yaml":   "replicas: 3\nimage:\n  tag: \"{{ tag }}\"\n",
+		"broken.json":  "{",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(out, name), []byte(content), 0644))
+	}
+
+	tests := []struct {
+		name     string
+		variable Variable
+		want     any
+		warning  string
+	}{
+		{
+			name:     "regexp capture group",
+			variable: Variable{Type: "string", Default: "example.com/app", Extract: &Extract{File: "go.mod", Pattern: `(?m)^module\s+(\S+)`}},
+			want:     "github.com/acme/widget",
+		},
+		{
+			name:     "regexp without group",
+			variable: Variable{Type: "string", Extract: &Extract{File: "go.mod", Pattern: `go \d+\.\d+`}},
+			want:     "go 1.24",
+		},
+		{
+			name:     "json key",
+			variable: Variable{Type: "string", Extract: &Extract{File: "package.json", Key: "name"}},
+			want:     "widget",
+		},
+		{
+			name:     "nested json key",
+			variable: Variable{Type: "string", Extract: &Extract{File: "package.json", Key: "engines.node"}},
+			want:     "20",
+		},
+		{
+			name:     "list index",
+			variable: Variable{Type: "string", Extract: &Extract{File: "package.json", Key: "workspaces.1"}},
+			want:     "b",
+		},
+		{
+			name:     "yaml number",
+			variable: Variable{Type: "number", Default: 1, Extract: &Extract{File: "chart.yaml", Key: "replicas"}},
+			want:     3.0,
+		},
+		{
+			name:     "template text is quoted",
+			variable: Variable{Type: "string", Extract: &Extract{File: "chart.yaml", Key: "image.tag"}},
+			want:     `{{ "{{ tag }}" }}`,
+		},
+		{
+			name:     "missing file keeps the default",
+			variable: Variable{Type: "string", Default: "app", Extract: &Extract{File: "Cargo.toml", Pattern: `name = "(.*)"`}},
+			want:     "app",
+		},
+		{
+			name:     "missing key keeps the default",
+			variable: Variable{Type: "string", Default: "app", Extract: &Extract{File: "package.json", Key: "engines.deno"}},
+			want:     "app",
+		},
+		{
+			name:     "not a single value",
+			variable: Variable{Type: "string", Default: "app", Extract: &Extract{File: "package.json", Key: "engines"}},
+			want:     "app",
+			warning:  "engines in package.json is not a single value",
+		},
+		{
+			name:     "unparsable file",
+			variable: Variable{Type: "string", Default: "app", Extract: &Extract{File: "broken.json", Key: "name"}},
+			want:     "app",
+			warning:  "parse broken.json",
+		},
+		{
+			name:     "value failing validation",
+			variable: Variable{Type: "string", Default: "app", Pattern: "^[a-z]+$", Extract: &Extract{File: "go.mod", Pattern: `module (\S+)`}},
+			want:     "app",
+			warning:  "does not match pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variables := map[string]Variable{"value": tt.variable}
+			extracted, warnings := extractDefaults(variables, out)
+			assert.Equal(t, tt.want, extracted["value"].Default)
+			if tt.warning == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Contains(t, warnings[0], tt.warning)
+			}
+			assert.Equal(t, tt.variable.Default, variables["value"].Default, "the declared variables are left alone")
+		})
+	}
+}
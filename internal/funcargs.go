@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// stringArg is the parameter type of template functions that work on text. Unlike a string
+// parameter it accepts any value, so instead of text/template's "wrong type for value" the
+// function converts it with asString. `kick functions` shows it as string.
+type stringArg any
+
+// asString converts a template argument to the text a string function works on. Numbers
+// and booleans are converted the way they print, so {{ snake .port }} works for a number
+// answer, and an unset variable counts as empty. Lists, maps and other values have no
+// sensible text form and are rejected.
+func asString(v stringArg) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v), nil
+	case reflect.Slice, reflect.Array:
+		return "", fmt.Errorf("expected a string, got a list (%T)", v)
+	case reflect.Map:
+		return "", fmt.Errorf("expected a string, got a map (%T)", v)
+	default:
+		return "", fmt.Errorf("expected a string, got %T", v)
+	}
+}
+
+// stringFunc adapts a one-string function to take any argument asString accepts
+func stringFunc(fn func(string) string) func(stringArg) (string, error) {
+	return func(a stringArg) (string, error) {
+		s, err := asString(a)
+		if err != nil {
+			return "", err
+		}
+		return fn(s), nil
+	}
+}
+
+// stringFunc2 is stringFunc for functions of two strings
+func stringFunc2(fn func(string, string) string) func(stringArg, stringArg) (string, error) {
+	return func(a, b stringArg) (string, error) {
+		args, err := asStrings(a, b)
+		if err != nil {
+			return "", err
+		}
+		return fn(args[0], args[1]), nil
+	}
+}
+
+// stringFunc3 is stringFunc for functions of three strings
+func stringFunc3(fn func(string, string, string) string) func(stringArg, stringArg, stringArg) (string, error) {
+	return func(a, b, c stringArg) (string, error) {
+		args, err := asStrings(a, b, c)
+		if err != nil {
+			return "", err
+		}
+		return fn(args[0], args[1], args[2]), nil
+	}
+}
+
+// stringFuncN is stringFunc for variadic functions of strings
+func stringFuncN(fn func(...string) string) func(...stringArg) (string, error) {
+	return func(a ...stringArg) (string, error) {
+		args, err := asStrings(a...)
+		if err != nil {
+			return "", err
+		}
+		return fn(args...), nil
+	}
+}
+
+// asStrings converts every argument with asString
+func asStrings(args ...stringArg) ([]string, error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		s, err := asString(arg)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = s
+	}
+	return strs, nil
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FunctionDoc describes a template function for `kick functions`
+type FunctionDoc struct {
+	Name        string
+	Signature   string // parameter and result types, e.g. "(string, string) string"
+	Description string
+	Example     string
+}
+
+// functionDocs documents every function in newTemplateFuncs. A test fails when the two drift apart.
+var functionDocs = map[string]struct{ description, example string }{
+	"upper":    {"convert to upper case", `{{ upper "api" }} → API`},
+	"lower":    {"convert to lower case", `{{ lower "API" }} → api`},
+	"title":    {"capitalize every word", `{{ title "my app" }} → My App`},
+	"trim":     {"remove leading and trailing white space", `{{ trim "  app " }} → app`},
+	"snake":    {"convert to snake_case", `{{ snake "MyApp" }} → my_app`},
+	"kebab":    {"convert to kebab-case", `{{ kebab "MyApp" }} → my-app`},
+	"camel":    {"convert to camelCase", `{{ camel "my app" }} → myApp`},
+	"pascal":   {"convert to PascalCase", `{{ pascal "my app" }} → MyApp`},
+	"replace":  {"replace every occurrence of old with new", `{{ replace "a-b" "-" "_" }} → a_b`},
+	"goSlice":  {"format a list as a Go string slice literal", `{{ goSlice .services }} → []string{"api", "worker"}`},
+	"yamlList": {"format a list as a YAML block sequence", `{{ yamlList .services }} → - api`},
+	"has":      {"whether a list holds an item, a map a key or a string a substring", `{{ if has "api" .services }}`},
+	"wrap":     {"word-wrap text to a width", `{{ wrap 80 .description }}`},
+	"wrapWith": {"word-wrap text, starting every line with a prefix", `{{ wrapWith 80 "// " .description }}`},
+
+	"toYaml":       {"marshal a value as YAML", `{{ toYaml .service }}`},
+	"toJson":       {"marshal a value as compact JSON", `{{ toJson .service }} → {"name":"api"}`},
+	"toPrettyJson": {"marshal a value as indented JSON", `{{ toPrettyJson .service }}`},
+	"indent":       {"indent every non-empty line by n spaces", `{{ indent 2 .block }}`},
+	"nindent":      {"indent, preceded by a newline", `spec:{{ .spec | toYaml | nindent 2 }}`},
+
+	"pathJoin": {"join segments into a slash-separated path", `{{ pathJoin .module "internal" }} → github.com/acme/app/internal`},
+	"basePath": {"last element of a slash-separated path", `{{ basePath "github.com/acme/app" }} → app`},
+
+	"trimAll":    {"remove leading and trailing characters found in a cutset", `{{ trimAll "/\"" "\"/srv/app/\"" }} → srv/app`},
+	"trimPrefix": {"remove a prefix once, if present", `{{ trimPrefix "v" "v1.2.0" }} → 1.2.0`},
+	"trimSuffix": {"remove a suffix once, if present", `{{ trimSuffix ".git" "app.git" }} → app`},
+
+	"pluralizeIf": {"singular form when the count is one, plural otherwise", `{{ pluralizeIf .n "file" "files" }} → files`},
+}
+
+// Functions lists the functions available in templates, sorted by name
+func Functions() []FunctionDoc {
+	funcs := newTemplateFuncs()
+	docs := make([]FunctionDoc, 0, len(funcs))
+	for name, fn := range funcs {
+		doc := functionDocs[name]
+		docs = append(docs, FunctionDoc{
+			Name:        name,
+			Signature:   funcSignature(reflect.TypeOf(fn)),
+			Description: doc.description,
+			Example:     doc.example,
+		})
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
+
+// funcSignature spells a function type the way templates see it; an error result
+// aborts rendering and is left out
+func funcSignature(t reflect.Type) string {
+	params := make([]string, t.NumIn())
+	for i := range params {
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			params[i] = "..." + typeName(t.In(i).Elem())
+			continue
+		}
+		params[i] = typeName(t.In(i))
+	}
+
+	var results []string
+	for i := 0; i < t.NumOut(); i++ {
+		if out := t.Out(i); out != reflect.TypeFor[error]() {
+			results = append(results, typeName(out))
+		}
+	}
+	return "(" + strings.Join(params, ", ") + ") " + strings.Join(results, ", ")
+}
+
+func typeName(t reflect.Type) string {
+	if t == reflect.TypeFor[stringArg]() {
+		return "string"
+	}
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		return "any"
+	}
+	return t.String()
+}
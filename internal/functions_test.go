@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctions_Documented(t *testing.T) {
+	for name := range newTemplateFuncs() {
+		doc, ok := functionDocs[name]
+		if assert.True(t, ok, "template function %q has no entry in functionDocs", name) {
+			assert.NotEmpty(t, doc.description, name)
+			assert.NotEmpty(t, doc.example, name)
+		}
+	}
+	for name := range functionDocs {
+		assert.Contains(t, newTemplateFuncs(), name, "functionDocs documents unknown function %q", name)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	docs := Functions()
+	assert.True(t, slices.IsSortedFunc(docs, func(a, b FunctionDoc) int { return strings.Compare(a.Name, b.Name) }))
+
+	signatures := map[string]string{}
+	for _, doc := range docs {
+		signatures[doc.Name] = doc.Signature
+	}
+	assert.Equal(t, "(string) string", signatures["upper"])
+	assert.Equal(t, "(string, string, string) string", signatures["replace"])
+	assert.Equal(t, "(any) string", signatures["toJson"])
+	assert.Equal(t, "(...string) string", signatures["pathJoin"])
+	assert.Equal(t, "(int, string, string) string", signatures["wrapWith"])
+}
@@ -2,9 +2,13 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,14 +17,143 @@ import (
 
 // Options contains the configuration for template generation
 type Options struct {
-	Source    string // Template source (path or URL)
-	OutputDir string // Output directory
+	Source      string // Template source (path or URL)
+	OutputDir   string // Output directory
+	Incremental bool   // Only write files whose rendered content changed since the last run
+	Manifest    bool   // Write a manifest of generated file checksums into the output directory
+	KeepGoing   bool   // Render remaining files after a render error and report all failures
+	Atomic      bool   // Render every file before writing any, so a render error leaves the output untouched
+	AtomicDir   bool   // Generate and run post-generation hooks in a staging directory moved into place only on success
+	DryRun      bool   // Render every file but write nothing and run no hooks, printing the planned changes instead
+	MaxFileSize int64  // Copy files larger than this many bytes verbatim instead of rendering them; 0 uses DefaultMaxFileSize
+
+	// Conflicts decides what happens to existing files in the output directory that differ from
+	// the generated content; empty fails like ConflictFail, before any file is written
+	Conflicts ConflictPolicy
+
+	// Values pre-seeds variable values, e.g. from an answers file; only variables missing from it are prompted
+	Values map[string]any
+	// AnswersFile pre-seeds Values from this YAML or JSON file, such as one written by
+	// ExportAnswers; entries already in Values take precedence
+	AnswersFile string
+	// Answers holds raw command-line answers, coerced to each variable's type.
+	// They take precedence over Values.
+	Answers map[string]string
+	// SettingsAnswers holds raw answers from the user settings file. Every other source overrides them.
+	SettingsAnswers map[string]string
+	// Quick skips the prompts of advanced variables that have a default, using the default instead
+	Quick bool
+	// PromptTimeout aborts with ErrNoInput when a prompt gets no answer within this duration; zero waits indefinitely
+	PromptTimeout time.Duration
+	// PromptStyle is PromptStyleStepped (the default when empty) or PromptStyleCompact
+	PromptStyle string
+	// Verbose prints where each answer came from after collection
+	Verbose bool
+	// Explain prints the outcome of every template entry after rendering, with the rule that
+	// decided it: the matching ignore pattern, tag selection, an empty rendered name and so on
+	Explain bool
+	// Only renders just the tagged files with one of these tags (plus untagged files)
+	Only []string
+	// Skip leaves out files with any of these tags
+	Skip []string
+	// GitToken authenticates HTTPS clones of private template repositories
+	GitToken string
+	// SkipHooks never runs pre- or post-generation hooks
+	SkipHooks bool
+	// HookPolicy refuses hooks whose commands it does not permit, before any hook runs
+	HookPolicy HookPolicy
+	// LocalOnly refuses git sources, so nothing is fetched over the network
+	LocalOnly bool
+	// StrictConfig rejects a kick.yaml with keys kick does not recognize, such as a misspelled
+	// "varaibles:", instead of ignoring them
+	StrictConfig bool
+	// StrictNames rejects file and directory names that render to text containing a path separator
+	// or that some operating system cannot create
+	StrictNames bool
+	// InitGit initializes a git repository in the output directory after generation,
+	// unless it already lies inside one or SkipHooks is set
+	InitGit bool
+	// InitialCommit commits the generated files with this message when InitGit creates a repository
+	InitialCommit string
+	// ChangedSince renders only the template files added or modified between this template
+	// ref (a branch, tag or commit) and the resolved template's HEAD. Git templates are then
+	// cloned with their full history instead of being read from the cache.
+	ChangedSince string
+	// Refresh clones a git template again instead of reusing its cached copy
+	Refresh bool
+	// NoCache clones a git template into a temporary directory, leaving the cache alone
+	NoCache bool
+	// CacheDir keeps cloned git templates in this directory instead of CacheDir()
+	CacheDir string
+	// RequireClean refuses to generate into a git working tree with uncommitted changes
+	RequireClean bool
+	// WorkingDir is the directory relative paths in Source, OutputDir, AnswersFile, ExportAnswers,
+	// Record and DumpContext resolve against; empty uses the process working directory
+	WorkingDir string
+	// PostRender runs after every file is written and before post-generation hooks, with the
+	// output directory, e.g. to format generated code from Go. An error aborts generation
+	// before the hooks run. Nil skips it.
+	PostRender func(outputDir string) error
+	// VersionFile writes provenance metadata (template name, version, source, commit and
+	// generation time) to this file in the output directory; empty writes none
+	VersionFile string
+	// ExportAnswers writes the effective context (answers and implicit values, but no secrets) to this file
+	// after generation, so a later run can import it with --answers
+	ExportAnswers string
+	// Record writes the collected answers (no secrets or implicit values) to this file as soon
+	// as prompting is done, even when generation fails later, so the run can be replayed with
+	// --answers without prompts
+	Record string
+	// DumpContext writes the render context (prompted, computed and implicit values, with
+	// secrets redacted) as YAML to this file when rendering or a hook fails; "-" writes it
+	// to stderr and empty writes nothing
+	DumpContext string
 }
 
 // Generate performs the complete template generation workflow
 func Generate(opts Options) error {
-	// Resolve template source
-	resolver := NewResolver()
+	opts = opts.resolvePaths()
+
+	if opts.AnswersFile != "" {
+		values, err := LoadAnswersFile(opts.AnswersFile)
+		if err != nil {
+			return err
+		}
+		maps.Copy(values, opts.Values)
+		opts.Values = values
+	}
+
+	// Protect uncommitted work before anything is prompted or written
+	if opts.RequireClean {
+		if err := checkClean(opts.OutputDir); err != nil {
+			return err
+		}
+	}
+
+	if err := validPromptStyle(opts.PromptStyle); err != nil {
+		return err
+	}
+
+	// Refuse a populated output directory before asking anything
+	if opts.AtomicDir && dirHasEntries(opts.OutputDir) {
+		return fmt.Errorf("%s is not empty; --atomic-dir needs an output directory that does not exist or is empty", opts.OutputDir)
+	}
+
+	if opts.LocalOnly && isGitLike(opts.Source) {
+		return fmt.Errorf("remote template %s is not allowed, only local paths", opts.Source)
+	}
+
+	// Resolve template source, reusing cached clones of git templates when a cache directory is
+	// available. Hooks and rendering work on a copy, so the cache holds only what was cloned.
+	resolver := NewResolverWithToken(opts.GitToken)
+	if opts.ChangedSince != "" {
+		// Cached clones are shallow, so diffing needs a fresh clone with every commit
+		resolver.fullHistory = true
+	} else if cacheDir, err := templateCacheDir(opts.CacheDir); err == nil && !opts.NoCache {
+		resolver = NewResolverWithCache(opts.GitToken, cacheDir)
+		resolver.refresh = opts.Refresh
+		resolver.workCopy = true
+	}
 	templatePath, cleanup, err := resolver.Resolve(opts.Source)
 	if err != nil {
 		return fmt.Errorf("resolve template: %v", err)
@@ -34,26 +167,339 @@ func Generate(opts Options) error {
 	if err != nil {
 		return err
 	}
+	if opts.StrictConfig {
+		keys, err := configUnknownKeys(templatePath)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			return fmt.Errorf("parse config: %s", strings.Join(keys, "; "))
+		}
+	}
+
+	// Reject tags the template does not declare
+	for _, tag := range append(append([]string{}, opts.Only...), opts.Skip...) {
+		if _, ok := cfg.Template.Tags[tag]; !ok {
+			return fmt.Errorf("unknown tag %q", tag)
+		}
+	}
+
+	renderPath, err := renderRoot(templatePath, cfg.Template)
+	if err != nil {
+		return err
+	}
+
+	// Render only the template files changed since ChangedSince; none changed renders nothing
+	var changed []string
+	if opts.ChangedSince != "" {
+		if changed, err = changedFiles(renderPath, opts.ChangedSince); err != nil {
+			return fmt.Errorf("changed template files: %w", err)
+		}
+		if changed == nil {
+			changed = []string{}
+		}
+	}
+
+	// Warn about ignore patterns that would produce an empty project
+	findings, err := checkIgnorePatterns(renderPath, cfg.Template)
+	if err != nil {
+		return err
+	}
+	for _, finding := range findings {
+		warn("%s", finding)
+	}
+
+	// Merge implicit context, settings, answers from a previous run, pre-seeded values and
+	// command-line answers, weakest first
+	settingsAnswers, err := coerceAnswers(cfg.Variables, opts.SettingsAnswers)
+	if err != nil {
+		return err
+	}
+	answers, err := coerceAnswers(cfg.Variables, opts.Answers)
+	if err != nil {
+		return err
+	}
+	previous, err := previousAnswers(opts.OutputDir, cfg.Variables)
+	if err != nil {
+		return err
+	}
+	seed := make(map[string]any)
+	sources := make(map[string]Source)
+	for _, layer := range []struct {
+		values map[string]any
+		source Source
+	}{
+		{implicitValues(opts.OutputDir), SourceImplicit},
+		{settingsAnswers, SourceSettings},
+		{envSecrets(cfg.Variables), SourceEnv},
+		{previous, SourcePrevious},
+		{coerceFileAnswers(cfg.Variables, opts.Values), SourceFile},
+		{answers, SourceFlag},
+	} {
+		for name, value := range layer.values {
+			seed[name] = value
+			sources[name] = layer.source
+		}
+	}
+
+	// Suggest what the machine already knows, then the values an existing project in the
+	// output directory already uses
+	variables, warnings := environmentDefaults(cfg.Variables)
+	for _, msg := range warnings {
+		warn("%s", msg)
+	}
+	variables, warnings = extractDefaults(variables, opts.OutputDir)
+	for _, msg := range warnings {
+		warn("%s", msg)
+	}
 
 	// Collect user input
-	values, err := CollectValues(cfg.Variables, cfg.GetVariableOrder())
+	prompt := newPrompter(opts.PromptStyle, opts.PromptTimeout)
+	values, prompted, err := collectValues(variables, cfg.GetVariableOrder(), seed, collectOptions{quick: opts.Quick, overlays: cfg.Overlays, prompt: prompt})
+	if err != nil {
+		return fmt.Errorf("collect values: %w", err)
+	}
+	for name, source := range prompted {
+		sources[name] = source
+	}
+	if opts.Verbose {
+		showSources(cfg.Variables, cfg.GetVariableOrder(), values, sources)
+	}
+
+	data := renderData(cfg, values)
+	// Hooks see secrets only through environment variables, never in their command strings
+	hookData, secretEnv := hookSecrets(cfg.Variables, data)
+	env, err := hookEnv(cfg, data)
 	if err != nil {
-		return fmt.Errorf("collect values: %v", err)
+		return err
 	}
+	hooks := hookSettings{policy: opts.HookPolicy, env: append(secretEnv, env...)}
 
-	// Execute pre-generation hooks
-	if err := executeHooks(cfg.Hooks.PreGeneration, "pre-generation", templatePath, values); err != nil {
+	// Record what the template received when rendering or a hook fails
+	failed := func(err error) error {
+		if opts.DumpContext != "" {
+			if dumpErr := dumpContext(opts.DumpContext, cfg.Variables, data); dumpErr != nil {
+				warn("%v", dumpErr)
+			}
+		}
 		return err
 	}
 
+	// A dry run changes nothing, so hooks cannot run either
+	if opts.SkipHooks || opts.DryRun {
+		if n := len(cfg.Hooks.all()); n > 0 {
+			warn("skipping %d hooks", n)
+		}
+		cfg.Hooks = Hooks{}
+	}
+
+	// Hook scripts are rendered like template files before any hook runs
+	cleanupScripts, err := prepareScriptHooks(&cfg.Hooks, templatePath, hookData, cfg.Template)
+	defer cleanupScripts()
+	if err != nil {
+		return failed(err)
+	}
+
+	// Fail before generating anything when a hook's program is missing
+	if cfg.Hooks.CheckCommands {
+		if err := New().CheckCommands(cfg.Hooks, hookData); err != nil {
+			return failed(err)
+		}
+	}
+
+	// Refuse every hook up front rather than after some have run
+	if err := opts.HookPolicy.checkHooks(cfg.Hooks, hookData); err != nil {
+		return failed(err)
+	}
+
+	// Execute pre-generation hooks, which can set values for rendering through KICK_OUTPUT
+	setByHooks := false
+	pre := hooks
+	pre.setValues = func(set map[string]any) (map[string]any, []string, error) {
+		if err := mergeHookValues(cfg.Variables, values, set); err != nil {
+			return nil, nil, err
+		}
+		data = renderData(cfg, values)
+		hookData, secretEnv = hookSecrets(cfg.Variables, data)
+		env, err := hookEnv(cfg, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		hooks.env = append(secretEnv, env...)
+		setByHooks = true
+		return hookData, hooks.env, nil
+	}
+	preErr := executeHooks(cfg.Hooks.PreGeneration, "pre-generation", templatePath, hookData, pre)
+	// Record the answers with the values hooks set, even when generation fails from here on
+	if opts.Record != "" {
+		if err := writeAnswersFile(opts.Record, storedAnswers(cfg.Variables, values)); err != nil {
+			return err
+		}
+	}
+	if preErr != nil {
+		return failed(preErr)
+	}
+	if setByHooks {
+		// Post-generation scripts are rendered again with the values the hooks set, and as
+		// those values can change what the hooks run, they are checked again
+		post := Hooks{PostGeneration: cfg.Hooks.PostGeneration}
+		cleanupPostScripts, err := prepareScriptHooks(&post, templatePath, hookData, cfg.Template)
+		defer cleanupPostScripts()
+		if err != nil {
+			return failed(err)
+		}
+		if cfg.Hooks.CheckCommands {
+			if err := New().CheckCommands(post, hookData); err != nil {
+				return failed(err)
+			}
+		}
+		if err := opts.HookPolicy.checkHooks(post, hookData); err != nil {
+			return failed(err)
+		}
+	}
+
+	header, err := renderHeader(cfg, opts.Source, data)
+	if err != nil {
+		return failed(err)
+	}
+
+	// Generate into a staging directory that replaces the output only when everything succeeded
+	target := opts.OutputDir
+	if opts.AtomicDir && !opts.DryRun {
+		staged, err := stageOutput(target)
+		if err != nil {
+			return err
+		}
+		// Until the move below, a failure discards everything generated so far
+		defer func() { _ = os.RemoveAll(staged) }()
+		opts.OutputDir = staged
+	}
+
 	// Generate files
-	if err := generateFiles(templatePath, opts.OutputDir, values, cfg.Template); err != nil {
+	renderOpts := RenderOptions{
+		Header:      header,
+		Incremental: opts.Incremental,
+		KeepGoing:   opts.KeepGoing,
+		Atomic:      opts.Atomic,
+		Only:        opts.Only,
+		Skip:        opts.Skip,
+		MaxFileSize: opts.MaxFileSize,
+		StrictNames: opts.StrictNames,
+		Files:       changed,
+		DryRun:      opts.DryRun,
+		// Generating into a directory that already has content merges into it
+		Merge:     dirHasEntries(opts.OutputDir),
+		Conflicts: opts.Conflicts,
+	}
+	if renderOpts.Merge && (opts.Conflicts == "" || opts.Conflicts == ConflictFail) {
+		// Hold every write back until it is clear that no file conflicts
+		renderOpts.Atomic = true
+	}
+	// Files still matching the manifest of an earlier run are replaced without a conflict
+	if opts.Incremental || renderOpts.Merge {
+		previous, err := LoadManifest(opts.OutputDir)
+		if err != nil {
+			return err
+		}
+		renderOpts.Previous = previous
+	}
+
+	rend, err := generateFiles(renderPath, opts.OutputDir, data, cfg.Template, renderOpts, prompt)
+	if opts.Explain {
+		showExplanations(rend.Explanations())
+	}
+	var conflicts ConflictError
+	if errors.As(err, &conflicts) {
+		return fmt.Errorf("%w\nnothing was written; pass --force to overwrite them, --skip-existing to keep them or --interactive-conflicts to decide for each", err)
+	}
+	if err != nil {
+		return failed(err)
+	}
+	for _, msg := range rend.NonPortable() {
+		warn("%s", msg)
+	}
+	if opts.DryRun {
+		showPlan(target, rend.Plan())
+		return nil
+	}
+	for _, rel := range rend.Oversized() {
+		warn("%s exceeds the maximum file size and was copied without rendering", rel)
+	}
+	if renderOpts.Merge {
+		showMergeReport(rend.MergeReport())
+	}
+
+	if opts.Incremental || opts.Manifest {
+		manifest := rend.Manifest()
+		if changed != nil {
+			// Only the changed files were rendered, so keep the other entries of the last run
+			previous, err := LoadManifest(opts.OutputDir)
+			if err != nil {
+				return err
+			}
+			maps.Copy(previous.Files, manifest.Files)
+			manifest = previous
+		}
+		if err := manifest.Save(opts.OutputDir); err != nil {
+			return err
+		}
+		if err := saveAnswers(opts.OutputDir, cfg.Variables, values); err != nil {
+			return err
+		}
+	}
+	if opts.VersionFile != "" {
+		if err := newProvenance(cfg, opts.Source, templatePath).Save(filepath.Join(opts.OutputDir, opts.VersionFile)); err != nil {
+			return err
+		}
+	}
+	state := newState(cfg, opts.Source, templatePath, values)
+	state.Only, state.Skip, state.Files = opts.Only, opts.Skip, rend.Manifest().Files
+	if changed != nil {
+		// Only the changed files were rendered, so keep the checksums of the last run
+		if previous, err := LoadState(opts.OutputDir); err == nil && previous.Files != nil {
+			maps.Copy(previous.Files, state.Files)
+			state.Files = previous.Files
+		}
+	}
+	if err := state.Save(opts.OutputDir); err != nil {
 		return err
 	}
+	if opts.ExportAnswers != "" {
+		if err := writeAnswersFile(opts.ExportAnswers, withoutSecrets(cfg.Variables, values)); err != nil {
+			return err
+		}
+	}
+	if opts.Incremental {
+		stats := rend.Stats()
+		tap.Box(fmt.Sprintf("%d changed, %d unchanged, %d skipped", stats.Changed, stats.Unchanged, stats.Skipped),
+			"Incremental generation", tap.BoxOptions{WidthAuto: true, Rounded: true, IncludePrefix: true})
+	}
+
+	if opts.PostRender != nil {
+		if err := opts.PostRender(opts.OutputDir); err != nil {
+			return failed(fmt.Errorf("post-render: %w", err))
+		}
+	}
 
 	// Execute post-generation hooks
-	if err := executeHooks(cfg.Hooks.PostGeneration, "post-generation", opts.OutputDir, values); err != nil {
-		return err
+	if err := executeHooks(cfg.Hooks.PostGeneration, "post-generation", opts.OutputDir, hookData, hooks); err != nil {
+		return failed(err)
+	}
+
+	if opts.InitGit {
+		if opts.SkipHooks {
+			warn("skipping git init")
+		} else if initialized, err := initGit(opts.OutputDir, opts.InitialCommit); err != nil {
+			return err
+		} else if !initialized {
+			warn("%s is already inside a git repository, skipping git init", target)
+		}
+	}
+
+	if opts.AtomicDir {
+		if err := commitOutput(opts.OutputDir, target); err != nil {
+			return err
+		}
 	}
 
 	// Success message
@@ -61,10 +507,89 @@ func Generate(opts Options) error {
 	return nil
 }
 
-// loadConfig loads and parses the template configuration
+// resolvePaths joins the relative paths in opts to WorkingDir. Git sources are left alone.
+func (opts Options) resolvePaths() Options {
+	if opts.WorkingDir == "" {
+		return opts
+	}
+
+	join := func(path string) string {
+		if filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(opts.WorkingDir, path)
+	}
+	if !isGitLike(opts.Source) {
+		opts.Source = join(opts.Source)
+	}
+	opts.OutputDir = join(opts.OutputDir)
+	if opts.AnswersFile != "" {
+		opts.AnswersFile = join(opts.AnswersFile)
+	}
+	if opts.ExportAnswers != "" {
+		opts.ExportAnswers = join(opts.ExportAnswers)
+	}
+	if opts.Record != "" {
+		opts.Record = join(opts.Record)
+	}
+	if opts.DumpContext != "" && opts.DumpContext != "-" {
+		opts.DumpContext = join(opts.DumpContext)
+	}
+	return opts
+}
+
+// renderHeader renders the template's generated-file banner, which can refer to the
+// answers plus _template (the template source) and _template_version
+func renderHeader(cfg Config, source string, data map[string]any) (string, error) {
+	if cfg.Template.Header.Text == "" {
+		return "", nil
+	}
+
+	headerData := make(map[string]any, len(data)+2)
+	for name, value := range data {
+		headerData[name] = value
+	}
+	headerData["_template"] = source
+	headerData["_template_version"] = cfg.Version
+
+	header, err := NewRenderer().renderString(cfg.Template.Header.Text, headerData)
+	if err != nil {
+		return "", fmt.Errorf("render header: %w", err)
+	}
+	return header, nil
+}
+
+// renderRoot returns the directory of the template whose contents are rendered:
+// the configured root, or the template directory itself
+func renderRoot(templatePath string, settings TemplateSettings) (string, error) {
+	if settings.Root == "" {
+		return templatePath, nil
+	}
+
+	root := filepath.Join(templatePath, settings.Root)
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", fmt.Errorf("template root %q: %w", settings.Root, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("template root %q is not a directory", settings.Root)
+	}
+	return root, nil
+}
+
+// loadConfig loads and parses the template configuration, falling back to the
+// cookiecutter.json of a Cookiecutter template when there is no kick.yaml
 func loadConfig(templatePath string) (Config, error) {
 	cfgPath := filepath.Join(templatePath, KickYAML)
 	cfgData, err := os.ReadFile(cfgPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if cfg, ok, err := loadCookiecutter(templatePath); ok {
+			if err != nil {
+				return Config{}, err
+			}
+			return cfg, addScriptHooks(templatePath, &cfg)
+		}
+	}
 	if err != nil {
 		return Config{}, fmt.Errorf("read %s: %v", cfgPath, err)
 	}
@@ -74,12 +599,135 @@ func loadConfig(templatePath string) (Config, error) {
 		return Config{}, fmt.Errorf("parse config: %v", err)
 	}
 
-	return cfg, nil
+	return cfg, addScriptHooks(templatePath, &cfg)
+}
+
+// configUnknownKeys lists the keys of a template's kick.yaml that kick does not recognize
+func configUnknownKeys(templatePath string) ([]string, error) {
+	cfgPath := filepath.Join(templatePath, KickYAML)
+	cfgData, err := os.ReadFile(cfgPath)
+	if errors.Is(err, os.ErrNotExist) {
+		// A Cookiecutter template has only cookiecutter.json, whose keys are all variables
+		if _, statErr := os.Stat(filepath.Join(templatePath, CookiecutterJSON)); statErr == nil {
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %v", cfgPath, err)
+	}
+	return unknownKeys(cfgData), nil
+}
+
+// implicitValues returns the context kick provides to every template. Names start
+// with "_" so they cannot clash with template variables. Answers override them, e.g.
+// _os=windows to generate for another platform.
+func implicitValues(outputDir string) map[string]any {
+	return map[string]any{
+		"_git_remote": gitRemoteURL(outputDir),
+		"_os":         runtime.GOOS,
+		"_arch":       runtime.GOARCH,
+	}
+}
+
+// showSources prints each collected variable with where its value came from
+func showSources(variables map[string]Variable, order []string, values map[string]any, sources map[string]Source) {
+	width := 0
+	for _, name := range order {
+		width = max(width, len(name))
+	}
+
+	var lines []string
+	for _, name := range order {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		if variables[name].Type == "secret" {
+			value = secretMask
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  %v (%s)", width, name, value, sources[name]))
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	tap.Box(strings.Join(lines, "\n"), "Answers", tap.BoxOptions{WidthAuto: true, Rounded: true, IncludePrefix: true})
+}
+
+// warn prints a non-fatal problem to stderr
+func warn(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, "warning: "+format+"\n", a...)
+}
+
+// templateData builds the render context from collected values. Declared variables
+// left unset (e.g. a skipped tri-state boolean) map to nil, so templates can test
+// them with `if` while undeclared keys still fail with missingkey=error.
+func templateData(variables map[string]Variable, values map[string]any) map[string]any {
+	data := make(map[string]any, len(variables))
+	for name, variable := range variables {
+		if variable.Type != "note" {
+			data[name] = nil
+		}
+	}
+	for name, value := range values {
+		data[name] = value
+	}
+	return data
+}
+
+// cookiecutterAlias is the key under which the answers are repeated for Jinja templates
+const cookiecutterAlias = "cookiecutter"
+
+// renderData builds the render context of a template from collected values
+func renderData(cfg Config, values map[string]any) map[string]any {
+	data := templateData(cfg.Variables, values)
+	if cfg.Template.Engine == EngineJinja {
+		if _, declared := data[cookiecutterAlias]; !declared {
+			// Jinja templates ported from Cookiecutter refer to answers as cookiecutter.name
+			data[cookiecutterAlias] = maps.Clone(data)
+		}
+	}
+	return data
+}
+
+// hookSettings holds what every hook of a run shares besides the template data
+type hookSettings struct {
+	policy HookPolicy
+	env    []string // added to the hook environment, e.g. KICK_SECRET_<NAME>=value for secrets
+
+	// setValues, when set, gives hooks a KICK_OUTPUT file to write values to. It merges the
+	// values a hook wrote and returns the data and environment of the hooks that follow.
+	setValues func(set map[string]any) (map[string]any, []string, error)
+}
+
+// hookEnv returns the environment every hook gets besides secrets: KICK_VAR_<NAME> for each
+// variable that is not a secret, followed by the rendered hooks.env entries, which win
+func hookEnv(cfg Config, data map[string]any) ([]string, error) {
+	var env []string
+	for _, name := range cfg.GetVariableOrder() {
+		if cfg.Variables[name].Type == "secret" {
+			continue
+		}
+		value := ""
+		if v := data[name]; v != nil {
+			value = fmt.Sprint(v)
+		}
+		env = append(env, variableEnvName(name)+"="+value)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(cfg.Hooks.Env)) {
+		value, err := RenderString(cfg.Hooks.Env[name], data)
+		if err != nil {
+			return nil, fmt.Errorf("render hooks env %s: %w", name, err)
+		}
+		env = append(env, name+"="+value)
+	}
+	return env, nil
 }
 
 // executeHooks runs pre or post generation hooks with tap stream display
-func executeHooks(hookCommands []string, hookType, workDir string, data map[string]any) error {
-	if len(hookCommands) == 0 {
+func executeHooks(hooks []Hook, hookType, workDir string, data map[string]any, settings hookSettings) error {
+	if len(hooks) == 0 {
 		return nil
 	}
 
@@ -91,39 +739,70 @@ func executeHooks(hookCommands []string, hookType, workDir string, data map[stri
 	}
 	successMessage := fmt.Sprintf("%s hooks executed", displayType)
 
-	// Create and start tap stream for live output
-	stream := tap.NewStream(tap.StreamOptions{ShowTimer: true})
-	stream.Start(message)
+	// Each hook has its own timeout
+	ctx := context.Background()
 
-	defer func() {
-		// Always stop the stream when function exits
-		stream.Stop(successMessage, 0)
-	}()
+	var output string
+	if settings.setValues != nil {
+		file, err := os.CreateTemp("", "kick-output-")
+		if err != nil {
+			return fmt.Errorf("create hook output file: %w", err)
+		}
+		output = file.Name()
+		_ = file.Close()
+		defer func() { _ = os.Remove(output) }()
+	}
 
-	// Create hook executor with stream
-	hookExecutor := NewWithStream(stream)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+	// Interactive hooks need the terminal to themselves, so the stream is stopped
+	// before them and a new one started for the streamed hooks that follow
+	var stream *tap.Stream
+	for _, hook := range hooks {
+		if hook.Interactive {
+			if stream != nil {
+				stream.Stop(successMessage, 0)
+				stream = nil
+			}
+		} else if stream == nil {
+			stream = tap.NewStream(tap.StreamOptions{ShowTimer: true})
+			stream.Start(message)
+		}
 
-	var err error
-	if hookType == "pre-generation" {
-		err = hookExecutor.ExecutePreGeneration(ctx, Hooks{PreGeneration: hookCommands}, workDir, data)
-	} else {
-		err = hookExecutor.ExecutePostGeneration(ctx, Hooks{PostGeneration: hookCommands}, workDir, data)
+		executor := NewWithStream(stream)
+		executor.policy, executor.env = settings.policy, settings.env
+		if output != "" {
+			executor.env = append(slices.Clip(settings.env), HookOutputEnv+"="+output)
+		}
+		err := executor.executeHook(ctx, hook, workDir, data)
+		if err == nil && output != "" {
+			var set map[string]any
+			if set, err = readHookOutput(output); err == nil && set != nil {
+				data, settings.env, err = settings.setValues(set)
+			}
+		}
+		if err != nil {
+			if stream != nil {
+				stream.Stop("Hook execution failed", 2)
+			}
+			return fmt.Errorf("execute %s hook: %w", hookType, err)
+		}
 	}
 
-	if err != nil {
-		stream.Stop("Hook execution failed", 2)
-		return err
+	if stream != nil {
+		stream.Stop(successMessage, 0)
 	}
-
 	return nil
 }
 
 // generateFiles renders the template tree with progress display
-func generateFiles(templatePath, outputDir string, data map[string]any, settings TemplateSettings) error {
-	return ShowProgress("📁 Rendering template files", "Template rendering complete", func() error {
-		rend := NewRenderer()
+func generateFiles(templatePath, outputDir string, data map[string]any, settings TemplateSettings, renderOpts RenderOptions, prompt prompter) (*Renderer, error) {
+	rend := NewRendererWithOptions(renderOpts)
+	rend.prompt = prompt
+	if renderOpts.Conflicts == ConflictPrompt && !renderOpts.DryRun {
+		// Conflict prompts cannot share the terminal with the progress bar
+		return rend, rend.RenderTreeWithSettings(templatePath, outputDir, data, settings)
+	}
+	err := ShowProgress("📁 Rendering template files", "Template rendering complete", func() error {
 		return rend.RenderTreeWithSettings(templatePath, outputDir, data, settings)
 	})
+	return rend, err
 }
@@ -0,0 +1,459 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// writeTemplate creates a template directory with the given kick.yaml and files.
+func writeTemplate(t *testing.T, kickYAML string, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, KickYAML), []byte(kickYAML), 0644))
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestGenerate_ImplicitValues(t *testing.T) {
+	src := writeTemplate(t, "name: test\n", map[string]string{
+		"platform.txt": "{{ ._os }}/{{ ._arch }}",
+	})
+
+	t.Run("current platform", func(t *testing.T) {
+		out := t.TempDir()
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out}))
+
+		content, err := os.ReadFile(filepath.Join(out, "platform.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, string(content))
+	})
+
+	t.Run("overridden by answers", func(t *testing.T) {
+		out := t.TempDir()
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"_os": "windows", "_arch": "arm64"}}))
+
+		content, err := os.ReadFile(filepath.Join(out, "platform.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "windows/arm64", string(content))
+	})
+}
+
+func TestGenerate_PreviousAnswers(t *testing.T) {
+	src := writeTemplate(t, `name: test
+variables:
+  name:
+    type: string
+  db:
+    type: choice
+    choices: [postgres, mysql]
+`, map[string]string{
+		"app.txt": "{{ .name }}/{{ .db }}",
+	})
+	out := t.TempDir()
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out,
+		Answers: map[string]string{"name": "api", "db": "mysql"}}))
+
+	state, err := LoadState(out)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "api", "db": "mysql"}, state.Answers)
+
+	// A plain re-run reuses the recorded answers, and command-line answers still override them
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Conflicts: ConflictOverwrite, Answers: map[string]string{"name": "web"}}))
+
+	content, err := os.ReadFile(filepath.Join(out, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "web/mysql", string(content))
+}
+
+func TestGenerate_TemplateRoot(t *testing.T) {
+	src := writeTemplate(t, "name: test\ntemplate:\n  root: template\n", map[string]string{
+		"template/main.go":       "package main",
+		"examples/basic/main.go": "package basic",
+		"README.md":              "# Template docs",
+	})
+	out := t.TempDir()
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out}))
+
+	assert.FileExists(t, filepath.Join(out, "main.go"))
+	assert.NoFileExists(t, filepath.Join(out, "README.md"))
+	assert.NoDirExists(t, filepath.Join(out, "examples"))
+	assert.NoDirExists(t, filepath.Join(out, "template"))
+}
+
+func TestGenerate_WorkingDir(t *testing.T) {
+	workDir := t.TempDir()
+	src := writeTemplate(t, "name: test\nvariables:\n  name: {type: string}\n", map[string]string{"app.txt": "{{ .name }}"})
+	require.NoError(t, os.Rename(src, filepath.Join(workDir, "tpl")))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "in.yaml"), []byte("name: api\n"), 0o644))
+
+	require.NoError(t, Generate(Options{
+		WorkingDir:    workDir,
+		Source:        "tpl",
+		OutputDir:     "out",
+		AnswersFile:   "in.yaml",
+		ExportAnswers: "answers.yaml",
+	}))
+
+	content, err := os.ReadFile(filepath.Join(workDir, "out", "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "api", string(content))
+	assert.FileExists(t, filepath.Join(workDir, "answers.yaml"))
+}
+
+func TestGenerate_SafeGuards(t *testing.T) {
+	t.Run("skip hooks", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - touch hooked.txt\n", map[string]string{"app.txt": "ok"})
+		out := t.TempDir()
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, SkipHooks: true}))
+		assert.FileExists(t, filepath.Join(out, "app.txt"))
+		assert.NoFileExists(t, filepath.Join(out, "hooked.txt"))
+	})
+
+	t.Run("local only", func(t *testing.T) {
+		err := Generate(Options{Source: "gh://kick-cli/template", OutputDir: t.TempDir(), LocalOnly: true})
+		assert.ErrorContains(t, err, "only local paths")
+	})
+}
+
+func TestGenerate_PostRender(t *testing.T) {
+	src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - touch hooked.txt\n", map[string]string{"app.txt": "ok"})
+
+	t.Run("runs before post-generation hooks", func(t *testing.T) {
+		out := t.TempDir()
+		var sawRendered, sawHook bool
+		err := Generate(Options{Source: src, OutputDir: out, PostRender: func(dir string) error {
+			_, err := os.Stat(filepath.Join(dir, "app.txt"))
+			sawRendered = err == nil
+			_, err = os.Stat(filepath.Join(dir, "hooked.txt"))
+			sawHook = err == nil
+			return os.WriteFile(filepath.Join(dir, "extra.txt"), []byte("extra"), 0644)
+		}})
+		require.NoError(t, err)
+		assert.True(t, sawRendered)
+		assert.False(t, sawHook)
+		assert.FileExists(t, filepath.Join(out, "extra.txt"))
+		assert.FileExists(t, filepath.Join(out, "hooked.txt"))
+	})
+
+	t.Run("error skips post-generation hooks", func(t *testing.T) {
+		out := t.TempDir()
+		err := Generate(Options{Source: src, OutputDir: out, PostRender: func(string) error {
+			return errors.New("format failed")
+		}})
+		assert.ErrorContains(t, err, "post-render: format failed")
+		assert.NoFileExists(t, filepath.Join(out, "hooked.txt"))
+	})
+}
+
+func TestGenerate_DumpContext(t *testing.T) {
+	const kickYAML = `name: test
+variables:
+  name:
+    type: string
+    default: app
+  token:
+    type: secret
+    prompt: API token
+`
+	answers := map[string]string{"token": "s3cret"}
+
+	t.Run("written when rendering fails", func(t *testing.T) {
+		src := writeTemplate(t, kickYAML, map[string]string{"app.txt": "{{ .name }} {{ .missing }}"})
+		out := t.TempDir()
+		dump := filepath.Join(t.TempDir(), "context.yaml")
+
+		err := Generate(Options{Source: src, OutputDir: out, Answers: answers, DumpContext: dump})
+		require.Error(t, err)
+
+		content, err := os.ReadFile(dump)
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "s3cret")
+		var context map[string]any
+		require.NoError(t, yaml.Unmarshal(content, &context))
+		assert.Equal(t, "app", context["name"])
+		assert.Equal(t, secretMask, context["token"])
+		assert.Equal(t, runtime.GOOS, context["_os"])
+	})
+
+	t.Run("secrets redacted under cookiecutter", func(t *testing.T) {
+		cfg := Config{
+			Variables: map[string]Variable{"name": {Type: "string"}, "token": {Type: "secret"}},
+			Template:  TemplateSettings{Engine: EngineJinja},
+		}
+		dump := filepath.Join(t.TempDir(), "context.yaml")
+
+		require.NoError(t, dumpContext(dump, cfg.Variables, renderData(cfg, map[string]any{"name": "app", "token": "s3cret"})))
+		content, err := os.ReadFile(dump)
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "s3cret")
+		var context map[string]any
+		require.NoError(t, yaml.Unmarshal(content, &context))
+		assert.Equal(t, map[string]any{"name": "app", "token": secretMask}, context[cookiecutterAlias])
+	})
+
+	t.Run("not written on success", func(t *testing.T) {
+		src := writeTemplate(t, kickYAML, map[string]string{"app.txt": "{{ .name }}"})
+		out := t.TempDir()
+		dump := filepath.Join(t.TempDir(), "context.yaml")
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: answers, DumpContext: dump}))
+		assert.NoFileExists(t, dump)
+	})
+}
+
+func TestGenerate_AtomicDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use a POSIX shell")
+	}
+
+	t.Run("moved into place on success", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - touch hooked.txt\n", map[string]string{"app.txt": "ok"})
+		parent := t.TempDir()
+		out := filepath.Join(parent, "app")
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, AtomicDir: true}))
+		assert.FileExists(t, filepath.Join(out, "app.txt"))
+		assert.FileExists(t, filepath.Join(out, "hooked.txt"))
+		entries, err := os.ReadDir(parent)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "staging directory left behind")
+	})
+
+	t.Run("output untouched when a hook fails", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - \"false\"\n", map[string]string{"app.txt": "ok"})
+		parent := t.TempDir()
+		out := filepath.Join(parent, "app")
+
+		require.Error(t, Generate(Options{Source: src, OutputDir: out, AtomicDir: true}))
+		assert.NoDirExists(t, out)
+		entries, err := os.ReadDir(parent)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "staging directory left behind")
+	})
+
+	t.Run("empty output directory replaced", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\n", map[string]string{"app.txt": "ok"})
+		out := t.TempDir()
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, AtomicDir: true}))
+		assert.FileExists(t, filepath.Join(out, "app.txt"))
+	})
+
+	t.Run("working directory kept", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - touch hooked.txt\n", map[string]string{"app.txt": "ok"})
+		parent := t.TempDir()
+		out := filepath.Join(parent, "app")
+		require.NoError(t, os.Mkdir(out, 0o755))
+		t.Chdir(out)
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: ".", AtomicDir: true}))
+		assert.FileExists(t, filepath.Join(out, "app.txt"))
+		assert.FileExists(t, filepath.Join(out, "hooked.txt"))
+		entries, err := os.ReadDir(parent)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "staging directory left behind")
+	})
+
+	t.Run("populated output directory refused", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\n", map[string]string{"app.txt": "ok"})
+		out := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(out, "keep.txt"), []byte("mine"), 0644))
+
+		err := Generate(Options{Source: src, OutputDir: out, AtomicDir: true})
+		assert.ErrorContains(t, err, "is not empty")
+		assert.NoFileExists(t, filepath.Join(out, "app.txt"))
+	})
+}
+
+func TestGenerate_DryRun(t *testing.T) {
+	src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - touch hooked.txt\n", map[string]string{"app.txt": "ok"})
+	out := filepath.Join(t.TempDir(), "app")
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, DryRun: true, Manifest: true, AtomicDir: true}))
+	assert.NoDirExists(t, out)
+	entries, err := os.ReadDir(filepath.Dir(out))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no staging directory either")
+}
+
+func TestCopyTree(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "bin", "run.sh"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "README.md"), []byte("# app\n"), 0644))
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	require.NoError(t, copyTree(src, dst))
+
+	content, err := os.ReadFile(filepath.Join(dst, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# app\n", string(content))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dst, "bin", "run.sh"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	}
+}
+
+func TestGenerate_ChangedSince(t *testing.T) {
+	src := t.TempDir()
+	repo := initRepo(t, src, map[string]string{
+		KickYAML:       "name: test\nvariables:\n  name:\n    type: string\n",
+		"app.txt":      "{{ .name }} v1",
+		"docs/use.txt": "usage v1",
+	})
+	first, err := repo.Head()
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(src, "app.txt"), []byte("{{ .name }} v2"), 0644))
+	_, err = worktree.Add("app.txt")
+	require.NoError(t, err)
+	_, err = worktree.Commit("fix app", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	out := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(out, ManifestFile), []byte("files:\n  docs/use.txt: abc\n"), 0644))
+	err = Generate(Options{
+		Source:       src,
+		OutputDir:    out,
+		Values:       map[string]any{"name": "demo"},
+		Manifest:     true,
+		ChangedSince: first.Hash().String(),
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(out, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "demo v2", string(content))
+	assert.NoFileExists(t, filepath.Join(out, "docs", "use.txt"))
+
+	manifest, err := LoadManifest(out)
+	require.NoError(t, err)
+	assert.Contains(t, manifest.Files, "app.txt")
+	assert.Equal(t, "abc", manifest.Files["docs/use.txt"], "entries of untouched files are kept")
+}
+
+func TestGenerate_VersionFile(t *testing.T) {
+	src := t.TempDir()
+	repo := initRepo(t, src, map[string]string{
+		KickYAML:  "name: service\nversion: 1.3.0\n",
+		"app.txt": "ok",
+	})
+	head, err := repo.Head()
+	require.NoError(t, err)
+
+	out := t.TempDir()
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, VersionFile: VersionFile}))
+
+	data, err := os.ReadFile(filepath.Join(out, VersionFile))
+	require.NoError(t, err)
+	var marker Provenance
+	require.NoError(t, yaml.Unmarshal(data, &marker))
+	assert.Equal(t, "service", marker.Template)
+	assert.Equal(t, "1.3.0", marker.Version)
+	assert.Equal(t, src, marker.Source)
+	assert.Equal(t, head.Hash().String(), marker.Ref)
+	_, err = time.Parse(time.RFC3339, marker.Generated)
+	assert.NoError(t, err)
+
+	out = t.TempDir()
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out}))
+	assert.NoFileExists(t, filepath.Join(out, VersionFile), "no marker unless requested")
+}
+
+func TestGenerate_Conflicts(t *testing.T) {
+	src := writeTemplate(t, "name: test\n", map[string]string{
+		"app.txt": "generated",
+		"new.txt": "new",
+	})
+	out := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(out, "app.txt"), []byte("hand-written"), 0644))
+
+	err := Generate(Options{Source: src, OutputDir: out})
+	require.ErrorContains(t, err, "1 existing files differ from the template\n  app.txt")
+	assert.ErrorContains(t, err, "nothing was written; pass --force")
+	assert.NoFileExists(t, filepath.Join(out, "new.txt"))
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Conflicts: ConflictSkip}))
+	content, err := os.ReadFile(filepath.Join(out, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "hand-written", string(content))
+	assert.FileExists(t, filepath.Join(out, "new.txt"))
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Conflicts: ConflictOverwrite}))
+	content, err = os.ReadFile(filepath.Join(out, "app.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "generated", string(content))
+}
+
+func TestGenerate_SecretsStayOffDisk(t *testing.T) {
+	src := writeTemplate(t, `name: test
+variables:
+  name:
+    type: string
+  api_key:
+    type: secret
+    env: TEST_KICK_API_KEY
+`, map[string]string{
+		".env": "NAME={{ .name }}\nAPI_KEY={{ .api_key }}\n",
+	})
+	t.Setenv("TEST_KICK_API_KEY", "sk-123")
+	out := t.TempDir()
+	exported := filepath.Join(t.TempDir(), "answers.yaml")
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Manifest: true, ExportAnswers: exported,
+		Answers: map[string]string{"name": "api"}}))
+
+	content, err := os.ReadFile(filepath.Join(out, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "NAME=api\nAPI_KEY=sk-123\n", string(content), "the secret comes from the environment")
+
+	for _, path := range []string{filepath.Join(out, AnswersFile), exported} {
+		saved, err := LoadAnswersFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "api", saved["name"], path)
+		assert.NotContains(t, saved, "api_key", path)
+	}
+}
+
+func TestGenerate_Record(t *testing.T) {
+	src := writeTemplate(t, `name: test
+variables:
+  name:
+    type: string
+  token:
+    type: secret
+    env: TEST_KICK_RECORD_TOKEN
+`, map[string]string{"app.txt": "{{ .name }} {{ .missing }}"})
+	t.Setenv("TEST_KICK_RECORD_TOKEN", "sk-123")
+	recorded := filepath.Join(t.TempDir(), "answers.yaml")
+
+	err := Generate(Options{Source: src, OutputDir: t.TempDir(), Record: recorded, Answers: map[string]string{"name": "api"}})
+	require.Error(t, err, "the template refers to an undeclared variable")
+
+	saved, err := LoadAnswersFile(recorded)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "api"}, saved, "answers are recorded before rendering, without secrets or implicit values")
+}
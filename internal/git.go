@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// checkClean returns an error when dir lies inside a git working tree that has
+// uncommitted changes under dir. Directories outside a repository, or that do
+// not exist yet, are considered clean.
+func checkClean(dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("resolve output directory: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	} else if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	repo, err := git.PlainOpenWithOptions(absDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open git repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("open git worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return fmt.Errorf("git status: %w", err)
+	}
+
+	prefix, err := repoPrefix(worktree.Filesystem.Root(), absDir)
+	if err != nil {
+		return err
+	}
+
+	dirty := 0
+	for path, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+		if prefix == "." || path == prefix || strings.HasPrefix(path, prefix+"/") {
+			dirty++
+		}
+	}
+
+	if dirty > 0 {
+		return fmt.Errorf("%s has uncommitted changes (%d files); commit or stash them first", dir, dirty)
+	}
+	return nil
+}
+
+// gitRemoteURL returns the origin remote URL of the git repository containing dir,
+// or of its nearest existing parent. It returns "" when there is no repository or origin.
+func gitRemoteURL(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if _, err := os.Stat(absDir); err == nil {
+			break
+		}
+		parent := filepath.Dir(absDir)
+		if parent == absDir {
+			return ""
+		}
+		absDir = parent
+	}
+
+	repo, err := git.PlainOpenWithOptions(absDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return remote.Config().URLs[0]
+}
+
+// initGit initializes a git repository in dir, on the branch named by the user's
+// init.defaultBranch when set, and commits every file when message is not empty.
+// It returns false without changes when dir already lies inside a repository.
+func initGit(dir, message string) (bool, error) {
+	if _, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true}); err == nil {
+		return false, nil
+	}
+
+	initOpts := &git.PlainInitOptions{}
+	if global, err := config.LoadConfig(config.GlobalScope); err == nil && global.Init.DefaultBranch != "" {
+		initOpts.InitOptions.DefaultBranch = plumbing.NewBranchReferenceName(global.Init.DefaultBranch)
+	}
+	repo, err := git.PlainInitWithOptions(dir, initOpts)
+	if err != nil {
+		return false, fmt.Errorf("git init: %w", err)
+	}
+	if message == "" {
+		return true, nil
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return true, fmt.Errorf("open git worktree: %w", err)
+	}
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return true, fmt.Errorf("git add: %w", err)
+	}
+	if _, err := worktree.Commit(message, &git.CommitOptions{}); err != nil {
+		return true, fmt.Errorf("initial commit: %w", err)
+	}
+	return true, nil
+}
+
+// changedFiles lists the files added or modified between ref and HEAD in the git repository
+// containing dir, as slash-separated paths relative to dir. Both commits must be in the
+// repository's history, so a shallow clone usually cannot answer it.
+func changedFiles(dir, ref string) ([]string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open git repository: %w", err)
+	}
+
+	from, err := commitTree(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	to, err := commitTree(repo, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("diff %s..HEAD: %w", ref, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("open git worktree: %w", err)
+	}
+	prefix, err := repoPrefix(worktree.Filesystem.Root(), dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, change := range changes {
+		name := change.To.Name
+		if name == "" {
+			continue // deleted files have nothing to render
+		}
+		if prefix != "." {
+			var ok bool
+			if name, ok = strings.CutPrefix(name, prefix+"/"); !ok {
+				continue
+			}
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// commitTree returns the tree of the commit a revision such as a branch, tag or SHA points to
+func commitTree(repo *git.Repository, rev string) (*object.Tree, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("read commit %s: %w", rev, err)
+	}
+	return commit.Tree()
+}
+
+// repoPrefix returns the slash-separated path of dir relative to the repository root
+func repoPrefix(root, dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve directory: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	prefix, err := filepath.Rel(root, absDir)
+	if err != nil {
+		return "", fmt.Errorf("compute repository path: %w", err)
+	}
+	return filepath.ToSlash(prefix), nil
+}
+
+// checkoutLocal clones the git repository containing the local template dir into a temporary
+// directory at commit, and returns the template's directory in the clone
+func checkoutLocal(dir, commit string) (string, func(), error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", nil, fmt.Errorf("open git repository: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", nil, fmt.Errorf("open git worktree: %w", err)
+	}
+	root := worktree.Filesystem.Root()
+	prefix, err := repoPrefix(root, dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "kick-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+
+	clone, err := git.PlainClone(tmp, false, &git.CloneOptions{URL: root, NoCheckout: true})
+	if err != nil {
+		return "", cleanup, fmt.Errorf("clone %s: %w", root, err)
+	}
+	if err := checkoutCommit(clone, commit, root); err != nil {
+		return "", cleanup, err
+	}
+	return filepath.Join(tmp, filepath.FromSlash(prefix)), cleanup, nil
+}
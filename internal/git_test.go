@@ -0,0 +1,192 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initRepo creates a git repository in dir with files committed.
+func initRepo(t *testing.T, dir string, files map[string]string) *git.Repository {
+	t.Helper()
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+
+	_, err = worktree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return repo
+}
+
+func TestCheckClean(t *testing.T) {
+	t.Run("outside a repository", func(t *testing.T) {
+		assert.NoError(t, checkClean(t.TempDir()))
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		assert.NoError(t, checkClean(filepath.Join(t.TempDir(), "new")))
+	})
+
+	t.Run("clean repository", func(t *testing.T) {
+		dir := t.TempDir()
+		initRepo(t, dir, map[string]string{"README.md": "hello"})
+		assert.NoError(t, checkClean(dir))
+	})
+
+	t.Run("modified file", func(t *testing.T) {
+		dir := t.TempDir()
+		initRepo(t, dir, map[string]string{"README.md": "hello"})
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed"), 0644))
+
+		err := checkClean(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "uncommitted changes (1 files)")
+	})
+
+	t.Run("untracked file in output subdirectory", func(t *testing.T) {
+		dir := t.TempDir()
+		initRepo(t, dir, map[string]string{"svc/main.go": "package main"})
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "svc", "new.go"), []byte("package main"), 0644))
+
+		assert.Error(t, checkClean(filepath.Join(dir, "svc")))
+	})
+
+	t.Run("changes outside the output subdirectory are ignored", func(t *testing.T) {
+		dir := t.TempDir()
+		initRepo(t, dir, map[string]string{"svc/main.go": "package main", "README.md": "hello"})
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("changed"), 0644))
+
+		assert.NoError(t, checkClean(filepath.Join(dir, "svc")))
+	})
+}
+
+func TestGitRemoteURL(t *testing.T) {
+	t.Run("outside a repository", func(t *testing.T) {
+		assert.Equal(t, "", gitRemoteURL(t.TempDir()))
+	})
+
+	t.Run("repository without origin", func(t *testing.T) {
+		dir := t.TempDir()
+		initRepo(t, dir, map[string]string{"README.md": "hello"})
+		assert.Equal(t, "", gitRemoteURL(dir))
+	})
+
+	t.Run("origin from a missing subdirectory", func(t *testing.T) {
+		dir := t.TempDir()
+		repo := initRepo(t, dir, map[string]string{"README.md": "hello"})
+		_, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"git@github.com:acme/widgets.git"}})
+		require.NoError(t, err)
+
+		assert.Equal(t, "git@github.com:acme/widgets.git", gitRemoteURL(filepath.Join(dir, "services", "new")))
+	})
+}
+
+func TestInitGit(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"),
+		[]byte("[user]\n\tname = test\n\temail = test@example.com\n[init]\n\tdefaultBranch = trunk\n"), 0644))
+
+	t.Run("init without commit", func(t *testing.T) {
+		dir := t.TempDir()
+		initialized, err := initGit(dir, "")
+		require.NoError(t, err)
+		assert.True(t, initialized)
+
+		repo, err := git.PlainOpen(dir)
+		require.NoError(t, err)
+		head, err := repo.Storer.Reference("HEAD")
+		require.NoError(t, err)
+		assert.Equal(t, "refs/heads/trunk", head.Target().String())
+	})
+
+	t.Run("initial commit", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+
+		initialized, err := initGit(dir, "Initial commit")
+		require.NoError(t, err)
+		assert.True(t, initialized)
+
+		repo, err := git.PlainOpen(dir)
+		require.NoError(t, err)
+		head, err := repo.Head()
+		require.NoError(t, err)
+		commit, err := repo.CommitObject(head.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, "Initial commit", commit.Message)
+		assert.Equal(t, "test", commit.Author.Name)
+		_, err = commit.File("main.go")
+		assert.NoError(t, err)
+	})
+
+	t.Run("inside a repository", func(t *testing.T) {
+		dir := t.TempDir()
+		initRepo(t, dir, map[string]string{"README.md": "hello"})
+		sub := filepath.Join(dir, "service")
+		require.NoError(t, os.Mkdir(sub, 0755))
+
+		initialized, err := initGit(sub, "Initial commit")
+		require.NoError(t, err)
+		assert.False(t, initialized)
+		assert.NoDirExists(t, filepath.Join(sub, ".git"))
+	})
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	repo := initRepo(t, dir, map[string]string{
+		"README.md":          "repo",
+		"template/main.go":   "package main",
+		"template/README.md": "old",
+		"template/gone.txt":  "bye",
+	})
+	first, err := repo.Head()
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("repo v2"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "template", "README.md"), []byte("new"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "template", "added.txt"), []byte("hi"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "template", "gone.txt")))
+	require.NoError(t, worktree.AddWithOptions(&git.AddOptions{All: true}))
+	_, err = worktree.Commit("second", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	files, err := changedFiles(filepath.Join(dir, "template"), first.Hash().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "added.txt"}, files)
+
+	files, err = changedFiles(dir, first.Hash().String())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "template/README.md", "template/added.txt"}, files)
+
+	_, err = changedFiles(dir, "v9.9.9")
+	assert.ErrorContains(t, err, "resolve v9.9.9")
+
+	_, err = changedFiles(t.TempDir(), "HEAD")
+	assert.ErrorContains(t, err, "not inside a git repository")
+}
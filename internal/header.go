@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// HeaderSettings configures the banner prepended to generated text files
+type HeaderSettings struct {
+	// Text is a template rendered with the answers plus _template and _template_version,
+	// e.g. "Generated by kick from {{ ._template }}@{{ ._template_version }}. DO NOT EDIT."
+	Text string `yaml:"text"`
+	// Skip lists glob patterns of files that get no header
+	Skip []string `yaml:"skip,omitempty"`
+}
+
+// commentStyle describes how a file type spells a comment
+type commentStyle struct {
+	line   string // prefix of a line comment, e.g. "// "
+	open   string // start of a block comment when the type has no line comments
+	closer string // end of a block comment
+}
+
+// commentStyles maps file extensions to their comment syntax. Types without an entry,
+// such as JSON, cannot carry a comment and get no header.
+var commentStyles = map[string]commentStyle{
+	".go": {line: "// "}, ".js": {line: "// "}, ".jsx": {line: "// "}, ".mjs": {line: "// "},
+	".ts": {line: "// "}, ".tsx": {line: "// "}, ".java": {line: "// "}, ".kt": {line: "// "},
+	".c": {line: "// "}, ".h": {line: "// "}, ".cc": {line: "// "}, ".cpp": {line: "// "},
+	".hpp": {line: "// "}, ".cs": {line: "// "}, ".rs": {line: "// "}, ".swift": {line: "// "},
+	".scala": {line: "// "}, ".dart": {line: "// "}, ".proto": {line: "// "}, ".scss": {line: "// "},
+
+	".py": {line: "# "}, ".rb": {line: "# "}, ".sh": {line: "# "}, ".bash": {line: "# "},
+	".zsh": {line: "# "}, ".ps1": {line: "# "}, ".pl": {line: "# "}, ".r": {line: "# "},
+	".yaml": {line: "# "}, ".yml": {line: "# "}, ".toml": {line: "# "}, ".tf": {line: "# "},
+	".mk": {line: "# "}, ".cfg": {line: "# "}, ".conf": {line: "# "}, ".env": {line: "# "},
+	"dockerfile": {line: "# "}, "makefile": {line: "# "}, ".gitignore": {line: "# "},
+
+	".sql": {line: "-- "}, ".lua": {line: "-- "}, ".hs": {line: "-- "},
+	".ini": {line: "; "},
+	".bat": {line: "REM "}, ".cmd": {line: "REM "},
+
+	".html": {open: "<!--", closer: "-->"}, ".xml": {open: "<!--", closer: "-->"},
+	".md": {open: "<!--", closer: "-->"}, ".svg": {open: "<!--", closer: "-->"},
+	".vue": {open: "<!--", closer: "-->"},
+	".css": {open: "/*", closer: "*/"},
+}
+
+// commentStyleFor returns the comment syntax for a file, looked up by extension or,
+// for files like Dockerfile and Makefile, by name
+func commentStyleFor(relPath string) (commentStyle, bool) {
+	name := strings.ToLower(filepath.Base(relPath))
+	if style, ok := commentStyles[name]; ok {
+		return style, true
+	}
+	style, ok := commentStyles[filepath.Ext(name)]
+	return style, ok
+}
+
+// formatHeader spells text as a comment in the given style, ending with a blank line
+func formatHeader(text string, style commentStyle) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	var b strings.Builder
+	if style.line != "" {
+		for _, line := range lines {
+			b.WriteString(strings.TrimRight(style.line+line, " "))
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString(style.open + " " + strings.Join(lines, "\n") + " " + style.closer + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// injectHeader prepends header to content, keeping a leading shebang or XML declaration first
+func injectHeader(content []byte, header string) []byte {
+	var prefix []byte
+	if bytes.HasPrefix(content, []byte("#!")) || bytes.HasPrefix(content, []byte("<?xml")) {
+		end := bytes.IndexByte(content, '\n')
+		if end < 0 {
+			prefix, content = append(bytes.Clone(content), '\n'), nil
+		} else {
+			prefix, content = content[:end+1], content[end+1:]
+		}
+	}
+
+	out := make([]byte, 0, len(prefix)+len(header)+len(content))
+	out = append(out, prefix...)
+	out = append(out, header...)
+	return append(out, content...)
+}
+
+// headerFor returns the comment header for a generated file, or "" when the file is skipped
+// or its type cannot carry a comment
+func (r *Renderer) headerFor(f fileTarget, settings TemplateSettings) string {
+	if r.opts.Header == "" {
+		return ""
+	}
+	for _, pattern := range settings.Header.Skip {
+		if matchesPattern(pattern, f.rel) {
+			return ""
+		}
+	}
+
+	style, ok := commentStyleFor(f.targetRel)
+	if !ok {
+		return ""
+	}
+	return formatHeader(r.opts.Header, style)
+}
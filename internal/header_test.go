@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+		skipped bool
+	}{
+		{
+			name:    "go line comment",
+			file:    "main.go",
+			content: "package main\n",
+			want:    "// Generated by kick.\n// DO NOT EDIT.\n\npackage main\n",
+		},
+		{
+			name:    "shebang stays first",
+			file:    "run.sh",
+			content: "#!/bin/sh\necho hi\n",
+			want:    "#!/bin/sh\n# Generated by kick.\n# DO NOT EDIT.\n\necho hi\n",
+		},
+		{
+			name:    "xml declaration stays first",
+			file:    "pom.xml",
+			content: "<?xml version=\"1.0\"?>\n<project/>\n",
+			want:    "<?xml version=\"1.0\"?>\n<!-- Generated by kick.\nDO NOT EDIT. -->\n\n<project/>\n",
+		},
+		{
+			name:    "file type by name",
+			file:    "Dockerfile",
+			content: "FROM scratch\n",
+			want:    "# Generated by kick.\n# DO NOT EDIT.\n\nFROM scratch\n",
+		},
+		{
+			name:    "json has no comments",
+			file:    "package.json",
+			skipped: true,
+		},
+		{
+			name:    "unknown type",
+			file:    "data.bin.txt",
+			skipped: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style, ok := commentStyleFor(tt.file)
+			if tt.skipped {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			got := injectHeader([]byte(tt.content), formatHeader("Generated by kick.\nDO NOT EDIT.", style))
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestGenerate_Header(t *testing.T) {
+	src := writeTemplate(t, `name: test
+version: "1.2.0"
+variables:
+  name:
+    type: string
+template:
+  header:
+    text: "Generated by kick from {{ ._template_version }} for {{ .name }}. DO NOT EDIT."
+    skip: ["LICENSE.md"]
+`, map[string]string{
+		"main.go":      "package {{ .name }}\n",
+		"package.json": "{}\n",
+		"LICENSE.md":   "MIT\n",
+	})
+	out := t.TempDir()
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"name": "app"}}))
+
+	for file, want := range map[string]string{
+		"main.go":      "// Generated by kick from 1.2.0 for app. DO NOT EDIT.\n\npackage app\n",
+		"package.json": "{}\n",
+		"LICENSE.md":   "MIT\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(out, file))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content), file)
+	}
+}
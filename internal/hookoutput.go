@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// HookOutputEnv names the environment variable holding the file a pre-generation hook can
+// write a JSON object of values to
+const HookOutputEnv = "KICK_OUTPUT"
+
+// readHookOutput returns the values a hook wrote to its output file, and empties the file for
+// the next hook. An empty file yields no values.
+func readHookOutput(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read hook output: %w", err)
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, nil
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return nil, fmt.Errorf("reset hook output: %w", err)
+	}
+
+	var set map[string]any
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("hook output in %s is not a JSON object: %w", HookOutputEnv, err)
+	}
+	return set, nil
+}
+
+// mergeHookValues adds the values a hook set to the collected values. Values of declared
+// variables are converted to the variable's type and validated like answers; other names
+// become new template values. Names starting with _ are reserved for kick.
+func mergeHookValues(variables map[string]Variable, values, set map[string]any) error {
+	for name, value := range coerceFileAnswers(variables, set) {
+		if strings.HasPrefix(name, "_") {
+			return fmt.Errorf("hook output sets %q: names starting with _ are reserved", name)
+		}
+		if variable, ok := variables[name]; ok && value != nil {
+			if err := variable.Validate(value); err != nil {
+				return fmt.Errorf("hook output sets %s: %w", name, err)
+			}
+		}
+		values[name] = value
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_HookOutput(t *testing.T) {
+	requirePOSIXShell(t)
+
+	tests := []struct {
+		name        string
+		kickYAML    string
+		files       map[string]string
+		policy      HookPolicy
+		want        map[string]string
+		errContains string
+	}{
+		{
+			name: "values merged before rendering",
+			kickYAML: `name: test
+variables:
+  project_name: {type: string}
+  go_version: {type: string, default: "1.22"}
+  port: {type: number, default: 8080}
+hooks:
+  pre_generation:
+    - printf '{"go_version":1.24,"port":"9090","remote":"git@example.com:{{ .project_name }}.git"}' > "$KICK_OUTPUT"
+    - test "$KICK_VAR_GO_VERSION" = 1.24 && printf '{"checked":true}' > "$KICK_OUTPUT"
+`,
+			files: map[string]string{
+				"app.txt": "{{ .go_version }} {{ .port }} {{ .remote }} {{ .checked }}",
+			},
+			want: map[string]string{"app.txt": "1.24 9090 git@example.com:demo.git true"},
+		},
+		{
+			name: "post-generation scripts see the values",
+			kickYAML: `name: test
+variables:
+  remote: {type: string, default: ""}
+hooks:
+  pre_generation:
+    - echo '{"remote":"origin"}' > "$KICK_OUTPUT"
+`,
+			files: map[string]string{
+				"hooks/post_gen.sh": "printf '%s' '{{ .remote }}' > remote.txt\n",
+			},
+			want: map[string]string{"remote.txt": "origin"},
+		},
+		{
+			name: "invalid JSON",
+			kickYAML: `name: test
+hooks:
+  pre_generation:
+    - echo 'go_version=1.24' > "$KICK_OUTPUT"
+`,
+			errContains: "is not a JSON object",
+		},
+		{
+			name: "value failing validation",
+			kickYAML: `name: test
+variables:
+  port: {type: number, default: 8080}
+hooks:
+  pre_generation:
+    - echo '{"port":"high"}' > "$KICK_OUTPUT"
+`,
+			errContains: "hook output sets port",
+		},
+		{
+			name: "reserved name",
+			kickYAML: `name: test
+hooks:
+  pre_generation:
+    - echo '{"_kick":1}' > "$KICK_OUTPUT"
+`,
+			errContains: "names starting with _ are reserved",
+		},
+		{
+			name: "post-generation hooks checked again",
+			kickYAML: `name: test
+variables:
+  tool: {type: string, default: "true"}
+hooks:
+  pre_generation:
+    - echo '{"tool":"curl"}' > "$KICK_OUTPUT"
+  post_generation:
+    - "{{ .tool }} https://example.com"
+`,
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := writeTemplate(t, tt.kickYAML, tt.files)
+			out := t.TempDir()
+
+			err := Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"project_name": "demo"}, HookPolicy: tt.policy})
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			for name, want := range tt.want {
+				content, err := os.ReadFile(filepath.Join(out, name))
+				require.NoError(t, err, name)
+				assert.Equal(t, want, string(content), name)
+			}
+		})
+	}
+}
+
+func TestGenerate_HookOutputRecorded(t *testing.T) {
+	requirePOSIXShell(t)
+
+	src := writeTemplate(t, `name: test
+hooks:
+  pre_generation:
+    - echo '{"remote":"origin"}' > "$KICK_OUTPUT"
+`, map[string]string{"app.txt": "{{ .remote }}"})
+	recorded := filepath.Join(t.TempDir(), "answers.yaml")
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: t.TempDir(), Record: recorded}))
+	saved, err := LoadAnswersFile(recorded)
+	require.NoError(t, err)
+	assert.Equal(t, "origin", saved["remote"])
+}
+
+func TestReadHookOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	require.NoError(t, os.WriteFile(path, []byte("  \n"), 0o644))
+	set, err := readHookOutput(path)
+	require.NoError(t, err)
+	assert.Nil(t, set, "an empty file sets nothing")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"name": "api"}`), 0o644))
+	set, err = readHookOutput(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "api"}, set)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, content, "the file is emptied for the next hook")
+}
@@ -3,17 +3,26 @@ package internal
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/yarlson/tap"
 )
 
+// DefaultHookTimeout is how long a hook may run when it sets no timeout
+const DefaultHookTimeout = 5 * time.Minute
+
 // Executor handles hook execution operations.
 type Executor struct {
 	stream *tap.Stream
+	policy HookPolicy
+	env    []string // added to the inherited environment of every hook
 }
 
 // New creates a new hook executor.
@@ -28,8 +37,8 @@ func NewWithStream(stream *tap.Stream) *Executor {
 
 // ExecutePreGeneration executes pre-generation hooks.
 func (e *Executor) ExecutePreGeneration(ctx context.Context, hooks Hooks, workDir string, data map[string]any) error {
-	for _, command := range hooks.PreGeneration {
-		if err := e.executeCommand(ctx, command, workDir, data); err != nil {
+	for _, hook := range hooks.PreGeneration {
+		if err := e.executeHook(ctx, hook, workDir, data); err != nil {
 			return fmt.Errorf("execute pre-generation hook: %w", err)
 		}
 	}
@@ -38,26 +47,249 @@ func (e *Executor) ExecutePreGeneration(ctx context.Context, hooks Hooks, workDi
 
 // ExecutePostGeneration executes post-generation hooks.
 func (e *Executor) ExecutePostGeneration(ctx context.Context, hooks Hooks, workDir string, data map[string]any) error {
-	for _, command := range hooks.PostGeneration {
-		if err := e.executeCommand(ctx, command, workDir, data); err != nil {
+	for _, hook := range hooks.PostGeneration {
+		if err := e.executeHook(ctx, hook, workDir, data); err != nil {
 			return fmt.Errorf("execute post-generation hook: %w", err)
 		}
 	}
 	return nil
 }
 
-// executeCommand executes a single hook command with template rendering.
-func (e *Executor) executeCommand(ctx context.Context, command string, workDir string, data map[string]any) error {
+// Shells a hook command can run in
+const (
+	ShellSh         = "sh"
+	ShellBash       = "bash"
+	ShellZsh        = "zsh"
+	ShellCmd        = "cmd"
+	ShellPowerShell = "powershell"
+	ShellPwsh       = "pwsh"
+)
+
+// hookShells lists the shells a hook may select
+var hookShells = []string{ShellSh, ShellBash, ShellZsh, ShellCmd, ShellPowerShell, ShellPwsh}
+
+// hookShell returns the shell that runs a hook: its own, or the platform's default
+func hookShell(hook Hook) string {
+	if hook.Shell != "" {
+		return hook.Shell
+	}
+	if runtime.GOOS == "windows" {
+		return ShellPowerShell
+	}
+	return ShellSh
+}
+
+// shellCommand returns the process that runs a rendered hook command in shell
+func shellCommand(ctx context.Context, shell, command string) *exec.Cmd {
+	switch shell {
+	case ShellCmd:
+		return cmdCommand(ctx, command)
+	case ShellPowerShell, ShellPwsh:
+		return exec.CommandContext(ctx, shell, "-NoProfile", "-NonInteractive", "-Command", command)
+	default:
+		return exec.CommandContext(ctx, shell, "-c", command)
+	}
+}
+
+// shellBuiltins are command words that need no PATH lookup.
+var shellBuiltins = map[string]bool{
+	"if": true, "then": true, "else": true, "fi": true, "for": true, "while": true, "do": true,
+	"done": true, "case": true, "esac": true, "[": true, "test": true, "true": true, "false": true,
+	"cd": true, "echo": true, "printf": true, "export": true, "set": true, "unset": true,
+	"exit": true, "source": true, ".": true, ":": true, "read": true, "eval": true, "exec": true,
+}
+
+// CheckCommands verifies that the program run by each hook is available on PATH. Hooks whose
+// when condition is false are left out.
+func (e *Executor) CheckCommands(hooks Hooks, data map[string]any) error {
+	for _, hook := range hooks.all() {
+		enabled, err := hookEnabled(hook, data)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			continue
+		}
+		missing, err := e.missingCommand(hook, data)
+		if err != nil {
+			return err
+		}
+		if missing != "" {
+			return fmt.Errorf("hook requires '%s' which was not found", missing)
+		}
+	}
+	return nil
+}
+
+// missingCommand returns the hook's shell when it is not on PATH, or else renders the hook
+// command and returns its program name when that is not on PATH.
+func (e *Executor) missingCommand(hook Hook, data map[string]any) (string, error) {
+	if strings.TrimSpace(hook.Command) == "" {
+		return "", nil
+	}
+	shell := hookShell(hook)
+	if _, err := exec.LookPath(shell); err != nil {
+		return shell, nil
+	}
+
+	rendered, err := e.renderCommand(hook.Command, data)
+	if err != nil {
+		return "", fmt.Errorf("render hook command: %w", err)
+	}
+
+	name := hookCommandName(rendered)
+	if name == "" {
+		return "", nil
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return name, nil
+	}
+	return "", nil
+}
+
+// hookCommandName returns the program a rendered hook command starts with.
+// Leading variable assignments are skipped; shell builtins and empty commands yield "".
+func hookCommandName(command string) string {
+	for _, field := range strings.Fields(command) {
+		if name, _, ok := strings.Cut(field, "="); ok && name != "" && !strings.ContainsAny(name, "/'\"") {
+			continue
+		}
+
+		field = strings.TrimLeft(field, "({")
+		field = strings.TrimRight(field, ";&|)}")
+		if field == "" || shellBuiltins[field] {
+			return ""
+		}
+		return field
+	}
+	return ""
+}
+
+// hookEnabled evaluates a hook's when condition; hooks without one always run
+func hookEnabled(hook Hook, data map[string]any) (bool, error) {
+	if hook.When == "" {
+		return true, nil
+	}
+	rendered, err := RenderString(hook.When, data)
+	if err != nil {
+		return false, fmt.Errorf("evaluate hook condition %s: %w", hook.When, err)
+	}
+	return asBool(strings.TrimSpace(rendered)), nil
+}
+
+// executeHook runs a hook when its condition holds, attached to the terminal when it is
+// interactive, and stops it after its timeout. A failure of a hook that allows failure is
+// reported as a warning.
+func (e *Executor) executeHook(ctx context.Context, hook Hook, workDir string, data map[string]any) error {
+	if enabled, err := hookEnabled(hook, data); err != nil || !enabled {
+		return err
+	}
+	if err := e.policy.checkScript(hook); err != nil {
+		return err
+	}
+
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	shell := hookShell(hook)
+	data = e.shellSecrets(shell, data)
+	var err error
+	if hook.Interactive {
+		err = e.executeInteractive(ctx, hook.Command, shell, workDir, data)
+	} else {
+		err = e.executeCommand(ctx, hook.Command, shell, workDir, data)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	if err != nil && hook.AllowFailure {
+		warn("hook %q failed, continuing: %v", hook.Command, err)
+		return nil
+	}
+	return err
+}
+
+// shellSecrets rewrites the $KICK_SECRET_<NAME> references hookSecrets puts in place of secrets,
+// including those in nested maps such as the cookiecutter alias, into the syntax of shell,
+// e.g. $env:KICK_SECRET_<NAME> for PowerShell
+func (e *Executor) shellSecrets(shell string, data map[string]any) map[string]any {
+	if shell != ShellCmd && shell != ShellPowerShell && shell != ShellPwsh {
+		return data
+	}
+
+	refs := make(map[string]string, len(e.env))
+	for _, entry := range e.env {
+		name, _, _ := strings.Cut(entry, "=")
+		if shell == ShellCmd {
+			refs["$"+name] = "%" + name + "%"
+		} else {
+			refs["$"+name] = "$env:" + name
+		}
+	}
+	return replaceRefs(data, refs)
+}
+
+// replaceRefs returns a copy of data in which every string value that is a key of refs, at
+// any depth, is replaced by its value in refs
+func replaceRefs(data map[string]any, refs map[string]string) map[string]any {
+	replaced := make(map[string]any, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			if ref, ok := refs[v]; ok {
+				value = ref
+			}
+		case map[string]any:
+			value = replaceRefs(v, refs)
+		}
+		replaced[key] = value
+	}
+	return replaced
+}
+
+// executeInteractive runs a hook command with the real stdin, stdout and stderr,
+// so tools that prompt or need a terminal behave as if run by hand.
+func (e *Executor) executeInteractive(ctx context.Context, command, shell, workDir string, data map[string]any) error {
+	renderedCommand, err := e.renderCommand(command, data)
+	if err != nil {
+		return fmt.Errorf("render hook command: %w", err)
+	}
+	if err := e.policy.check(renderedCommand); err != nil {
+		return err
+	}
+
+	cmd := shellCommand(ctx, shell, renderedCommand)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), e.env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("execute hook command: %w", err)
+	}
+	return nil
+}
+
+// executeCommand executes a single hook command in shell with template rendering.
+func (e *Executor) executeCommand(ctx context.Context, command, shell, workDir string, data map[string]any) error {
 	// Render the command template
 	renderedCommand, err := e.renderCommand(command, data)
 	if err != nil {
 		return fmt.Errorf("render hook command: %w", err)
 	}
+	if err := e.policy.check(renderedCommand); err != nil {
+		return err
+	}
 
 	// Execute the command
-	cmd := exec.CommandContext(ctx, "sh", "-c", renderedCommand)
+	cmd := shellCommand(ctx, shell, renderedCommand)
 	cmd.Dir = workDir
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), e.env...)
 
 	if e.stream != nil {
 		// Use tap stream's built-in Pipe method for simple streaming
@@ -3,7 +3,9 @@ package internal
 import (
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -12,7 +14,17 @@ import (
 	"github.com/yarlson/tap"
 )
 
+// requirePOSIXShell skips tests whose hook commands need sh, the default shell outside Windows
+func requirePOSIXShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook commands run with PowerShell on Windows")
+	}
+}
+
 func TestExecutor_ExecutePreGeneration(t *testing.T) {
+	requirePOSIXShell(t)
+
 	tests := []struct {
 		name         string
 		hooks        Hooks
@@ -31,16 +43,16 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 		{
 			name: "single echo command",
 			hooks: Hooks{
-				PreGeneration: []string{"echo 'Starting generation'"},
+				PreGeneration: []Hook{{Command: "echo 'Starting generation'"}},
 			},
 			data: map[string]any{"name": "test"},
 		},
 		{
 			name: "multiple commands",
 			hooks: Hooks{
-				PreGeneration: []string{
-					"echo 'First command'",
-					"echo 'Second command'",
+				PreGeneration: []Hook{
+					{Command: "echo 'First command'"},
+					{Command: "echo 'Second command'"},
 				},
 			},
 			data: map[string]any{"name": "test"},
@@ -48,8 +60,8 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 		{
 			name: "template rendering in command",
 			hooks: Hooks{
-				PreGeneration: []string{
-					"echo 'Project: {{.name}}'",
+				PreGeneration: []Hook{
+					{Command: "echo 'Project: {{.name}}'"},
 				},
 			},
 			data: map[string]any{"name": "my-project"},
@@ -57,8 +69,8 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 		{
 			name: "create file hook",
 			hooks: Hooks{
-				PreGeneration: []string{
-					"touch pre-generation-marker.txt",
+				PreGeneration: []Hook{
+					{Command: "touch pre-generation-marker.txt"},
 				},
 			},
 			data: map[string]any{"name": "test"},
@@ -70,8 +82,8 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 		{
 			name: "invalid command",
 			hooks: Hooks{
-				PreGeneration: []string{
-					"nonexistent-command-xyz",
+				PreGeneration: []Hook{
+					{Command: "nonexistent-command-xyz"},
 				},
 			},
 			data:        map[string]any{"name": "test"},
@@ -81,8 +93,8 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 		{
 			name: "command with exit code 1",
 			hooks: Hooks{
-				PreGeneration: []string{
-					"exit 1",
+				PreGeneration: []Hook{
+					{Command: "exit 1"},
 				},
 			},
 			data:        map[string]any{"name": "test"},
@@ -92,8 +104,8 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 		{
 			name: "template rendering error",
 			hooks: Hooks{
-				PreGeneration: []string{
-					"echo '{{.nonexistent}}'",
+				PreGeneration: []Hook{
+					{Command: "echo '{{.nonexistent}}'"},
 				},
 			},
 			data:        map[string]any{"name": "test"},
@@ -136,6 +148,8 @@ func TestExecutor_ExecutePreGeneration(t *testing.T) {
 }
 
 func TestExecutor_ExecutePostGeneration(t *testing.T) {
+	requirePOSIXShell(t)
+
 	tests := []struct {
 		name         string
 		hooks        Hooks
@@ -154,8 +168,8 @@ func TestExecutor_ExecutePostGeneration(t *testing.T) {
 		{
 			name: "git init command",
 			hooks: Hooks{
-				PostGeneration: []string{
-					"git init",
+				PostGeneration: []Hook{
+					{Command: "git init"},
 				},
 			},
 			data: map[string]any{"name": "test"},
@@ -167,9 +181,9 @@ func TestExecutor_ExecutePostGeneration(t *testing.T) {
 		{
 			name: "multiple post commands",
 			hooks: Hooks{
-				PostGeneration: []string{
-					"touch post-marker-1.txt",
-					"touch post-marker-2.txt",
+				PostGeneration: []Hook{
+					{Command: "touch post-marker-1.txt"},
+					{Command: "touch post-marker-2.txt"},
 				},
 			},
 			data: map[string]any{"name": "test"},
@@ -183,8 +197,8 @@ func TestExecutor_ExecutePostGeneration(t *testing.T) {
 		{
 			name: "template with project name",
 			hooks: Hooks{
-				PostGeneration: []string{
-					"echo '{{.project_name}}' > project-name.txt",
+				PostGeneration: []Hook{
+					{Command: "echo '{{.project_name}}' > project-name.txt"},
 				},
 			},
 			data: map[string]any{"project_name": "awesome-project"},
@@ -230,6 +244,8 @@ func TestExecutor_ExecutePostGeneration(t *testing.T) {
 }
 
 func TestExecutor_ExecuteWithTimeout(t *testing.T) {
+	requirePOSIXShell(t)
+
 	tests := []struct {
 		name        string
 		command     string
@@ -261,7 +277,7 @@ func TestExecutor_ExecuteWithTimeout(t *testing.T) {
 			defer cancel()
 
 			executor := New()
-			err = executor.executeCommand(ctx, tt.command, workDir, map[string]any{})
+			err = executor.executeCommand(ctx, tt.command, ShellSh, workDir, map[string]any{})
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -277,19 +293,21 @@ func TestExecutor_ExecuteWithTimeout(t *testing.T) {
 }
 
 func TestExecutor_ExecuteBothHooks(t *testing.T) {
+	requirePOSIXShell(t)
+
 	t.Run("complete hook workflow", func(t *testing.T) {
 		workDir, err := os.MkdirTemp("", "kick-workflow-*")
 		require.NoError(t, err)
 		defer func() { _ = os.RemoveAll(workDir) }()
 
 		hooks := Hooks{
-			PreGeneration: []string{
-				"echo 'pre: {{.name}}' > pre.txt",
-				"mkdir -p src",
+			PreGeneration: []Hook{
+				{Command: "echo 'pre: {{.name}}' > pre.txt"},
+				{Command: "mkdir -p src"},
 			},
-			PostGeneration: []string{
-				"echo 'post: {{.name}}' > post.txt",
-				"touch src/main.go",
+			PostGeneration: []Hook{
+				{Command: "echo 'post: {{.name}}' > post.txt"},
+				{Command: "touch src/main.go"},
 			},
 		}
 
@@ -324,6 +342,8 @@ func TestExecutor_ExecuteBothHooks(t *testing.T) {
 }
 
 func TestExecutor_WithStreamingOutput(t *testing.T) {
+	requirePOSIXShell(t)
+
 	t.Run("executor with stream executes hooks successfully", func(t *testing.T) {
 		workDir, err := os.MkdirTemp("", "kick-stream-*")
 		require.NoError(t, err)
@@ -334,10 +354,10 @@ func TestExecutor_WithStreamingOutput(t *testing.T) {
 		executor := NewWithStream(stream)
 
 		hooks := Hooks{
-			PreGeneration: []string{
-				"echo 'Line 1'",
-				"echo 'Line 2 from command'",
-				"touch streaming-test.txt",
+			PreGeneration: []Hook{
+				{Command: "echo 'Line 1'"},
+				{Command: "echo 'Line 2 from command'"},
+				{Command: "touch streaming-test.txt"},
 			},
 		}
 
@@ -368,10 +388,10 @@ func TestExecutor_WithStreamingOutput(t *testing.T) {
 		executor := NewWithStream(stream)
 
 		hooks := Hooks{
-			PreGeneration: []string{
-				"echo 'stdout message'",
-				"echo 'another line'",
-				"touch mixed-test.txt",
+			PreGeneration: []Hook{
+				{Command: "echo 'stdout message'"},
+				{Command: "echo 'another line'"},
+				{Command: "touch mixed-test.txt"},
 			},
 		}
 
@@ -401,9 +421,9 @@ func TestExecutor_WithStreamingOutput(t *testing.T) {
 		executor := New()
 
 		hooks := Hooks{
-			PreGeneration: []string{
-				"echo 'This should work without streaming'",
-				"touch fallback-test.txt",
+			PreGeneration: []Hook{
+				{Command: "echo 'This should work without streaming'"},
+				{Command: "touch fallback-test.txt"},
 			},
 		}
 
@@ -436,9 +456,9 @@ func TestExecutor_WithStreamingOutput(t *testing.T) {
 		executor := NewWithStream(stream)
 
 		hooks := Hooks{
-			PreGeneration: []string{
-				"echo 'This is output only'",
-				"echo 'No commands should be shown'",
+			PreGeneration: []Hook{
+				{Command: "echo 'This is output only'"},
+				{Command: "echo 'No commands should be shown'"},
 			},
 		}
 
@@ -459,3 +479,417 @@ func TestExecutor_WithStreamingOutput(t *testing.T) {
 		// The key point is that only command output should appear in the stream.
 	})
 }
+
+func TestExecutor_InteractiveHook(t *testing.T) {
+	requirePOSIXShell(t)
+
+	workDir := t.TempDir()
+	stream := tap.NewStream(tap.StreamOptions{})
+	executor := NewWithStream(stream)
+
+	hooks := Hooks{
+		PostGeneration: []Hook{
+			{Command: "echo '{{.name}}' > interactive.txt", Interactive: true},
+			{Command: "test -t 0 || echo piped > streamed.txt"},
+		},
+	}
+
+	stream.Start("Testing interactive hooks")
+	err := executor.ExecutePostGeneration(context.Background(), hooks, workDir, map[string]any{"name": "demo"})
+	stream.Stop("done", 0)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(workDir, "interactive.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "demo\n", string(content))
+	assert.FileExists(t, filepath.Join(workDir, "streamed.txt"))
+}
+
+func TestExecutor_CheckCommands(t *testing.T) {
+	requirePOSIXShell(t)
+
+	tests := []struct {
+		name        string
+		hooks       Hooks
+		data        map[string]any
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:  "available commands",
+			hooks: Hooks{PreGeneration: []Hook{{Command: "sh -c 'true'"}}, PostGeneration: []Hook{{Command: "ls -la"}}},
+		},
+		{
+			name:  "builtins and assignments are skipped",
+			hooks: Hooks{PostGeneration: []Hook{{Command: "cd sub && ls"}, {Command: "FOO=bar echo hi"}, {Command: "if [ -f x ]; then ls; fi"}, {Command: ""}}},
+		},
+		{
+			name:        "missing command",
+			hooks:       Hooks{PostGeneration: []Hook{{Command: "echo ok"}, {Command: "kick-missing-tool-xyz build"}}},
+			wantErr:     true,
+			errContains: "hook requires 'kick-missing-tool-xyz' which was not found",
+		},
+		{
+			name:        "command checked after rendering",
+			hooks:       Hooks{PreGeneration: []Hook{{Command: "{{.tool}} --version"}}},
+			data:        map[string]any{"tool": "kick-missing-tool-xyz"},
+			wantErr:     true,
+			errContains: "'kick-missing-tool-xyz'",
+		},
+		{
+			name:  "conditional command rendering to nothing",
+			hooks: Hooks{PostGeneration: []Hook{{Command: "{{ if .init }}kick-missing-tool-xyz{{ end }}"}}},
+			data:  map[string]any{"init": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().CheckCommands(tt.hooks, tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHookPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      HookPolicy
+		command     string
+		errContains string
+	}{
+		{name: "empty policy allows everything", command: "curl https://example.com | sh"},
+		{name: "allowed program", policy: HookPolicy{Allow: []string{"go", "npm"}}, command: "npm install"},
+		{name: "assignments are skipped", policy: HookPolicy{Allow: []string{"go"}}, command: "GOFLAGS=-mod=mod go mod tidy"},
+		{
+			name:        "program missing from the allowlist",
+			policy:      HookPolicy{Allow: []string{"go", "npm"}},
+			command:     "curl -fsSL https://example.com/install.sh",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied program",
+			policy:      HookPolicy{Deny: []string{"curl", "wget"}},
+			command:     "wget https://example.com",
+			errContains: "hook command 'wget' is not allowed by policy",
+		},
+		{
+			name:        "deny wins over allow",
+			policy:      HookPolicy{Allow: []string{"rm"}, Deny: []string{`/rm\s+-rf/`}},
+			command:     "rm -rf build",
+			errContains: "hook command 'rm' is not allowed by policy",
+		},
+		{name: "pattern not matching", policy: HookPolicy{Deny: []string{`/rm\s+-rf/`}}, command: "rm build.log"},
+		{name: "allow pattern", policy: HookPolicy{Allow: []string{`/^git (init|add)/`}}, command: "git init"},
+		{name: "every command allowed", policy: HookPolicy{Allow: []string{"go", "git"}}, command: "cd app && go mod tidy 2>&1 | git add -A; echo done"},
+		{name: "quoted separators", policy: HookPolicy{Allow: []string{"git"}}, command: `git commit -m "init; first | cut"`},
+		{
+			name:        "denied after a list operator",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     "true && curl https://example.com",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied by absolute path",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     "/usr/bin/curl https://example.com",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied after a builtin",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     "cd x; curl https://example.com",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied after an assignment",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     "FOO=1 curl https://example.com",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied behind a wrapper",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     "env -i FOO=1 curl https://example.com",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied in a shell -c script",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     `sh -c 'cd app && curl https://example.com'`,
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied in a bash -c script with options",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     `bash -o pipefail -ec "env curl \"https://example.com\""`,
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "denied behind xargs",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     "echo https://example.com | xargs -n 1 curl -O",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{name: "shell -c script allowed", policy: HookPolicy{Allow: []string{"sh", "go"}}, command: `sh -c "go mod tidy && go vet ./..."`},
+		{name: "shell running a script file", policy: HookPolicy{Deny: []string{"curl"}}, command: "sh ./curl-free.sh -c"},
+		{
+			name:        "denied in a command substitution",
+			policy:      HookPolicy{Deny: []string{"curl"}},
+			command:     `echo "$(curl https://example.com)"`,
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "allow pattern checked for each command",
+			policy:      HookPolicy{Allow: []string{`/^git (init|add)/`}},
+			command:     "git init\ncurl https://example.com | sh",
+			errContains: "hook command 'curl' is not allowed by policy",
+		},
+		{
+			name:        "program after a pipe",
+			policy:      HookPolicy{Allow: []string{"go"}},
+			command:     "go env GOPATH | sh",
+			errContains: "hook command 'sh' is not allowed by policy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.check(tt.command)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.errContains)
+		})
+	}
+}
+
+func TestExecutor_HookPolicy(t *testing.T) {
+	requirePOSIXShell(t)
+
+	workDir := t.TempDir()
+	executor := New()
+	executor.policy = HookPolicy{Deny: []string{"touch"}}
+
+	hooks := Hooks{PostGeneration: []Hook{{Command: "{{.tool}} created.txt"}}}
+	err := executor.ExecutePostGeneration(context.Background(), hooks, workDir, map[string]any{"tool": "touch"})
+	assert.ErrorContains(t, err, "hook command 'touch' is not allowed by policy")
+	assert.NoFileExists(t, filepath.Join(workDir, "created.txt"))
+
+	assert.ErrorContains(t, executor.policy.checkHooks(hooks, map[string]any{"tool": "touch"}), "not allowed by policy")
+	assert.NoError(t, executor.policy.checkHooks(hooks, map[string]any{"tool": "ls"}))
+
+	// What a script runs cannot be checked, so an active policy refuses it
+	script := Hooks{PreGeneration: []Hook{{Script: "hooks/pre_gen.sh", Command: "sh /tmp/pre_gen.sh"}}}
+	assert.ErrorContains(t, HookPolicy{Allow: []string{"sh"}}.checkHooks(script, nil), "hook script hooks/pre_gen.sh is not allowed by policy")
+	assert.ErrorContains(t, executor.ExecutePreGeneration(context.Background(), script, workDir, nil), "hook script hooks/pre_gen.sh is not allowed by policy")
+	assert.NoError(t, HookPolicy{}.checkHooks(script, nil))
+}
+
+func TestHookSecrets(t *testing.T) {
+	requirePOSIXShell(t)
+
+	variables := map[string]Variable{
+		"name":         {Type: "string"},
+		"deploy-token": {Type: "secret"},
+		"api_key":      {Type: "secret", Optional: true},
+	}
+	data := map[string]any{"name": "demo", "deploy-token": "s3cr3t", "api_key": nil}
+
+	hookData, env := hookSecrets(variables, data)
+	assert.Equal(t, map[string]any{"name": "demo", "deploy-token": "$KICK_SECRET_DEPLOY_TOKEN", "api_key": nil}, hookData)
+	assert.Equal(t, []string{"KICK_SECRET_DEPLOY_TOKEN=s3cr3t"}, env)
+	assert.Equal(t, "s3cr3t", data["deploy-token"], "the template data keeps the secret")
+
+	workDir := t.TempDir()
+	executor := New()
+	executor.env = env
+	hooks := Hooks{PostGeneration: []Hook{{Command: `printf '%s' {{ index . "deploy-token" }} > token.txt`}}}
+	command, err := executor.renderCommand(hooks.PostGeneration[0].Command, hookData)
+	require.NoError(t, err)
+	assert.NotContains(t, command, "s3cr3t")
+
+	require.NoError(t, executor.ExecutePostGeneration(context.Background(), hooks, workDir, hookData))
+	content, err := os.ReadFile(filepath.Join(workDir, "token.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(content))
+
+	// Jinja templates see the answers again under cookiecutter
+	aliased := renderData(Config{Variables: variables, Template: TemplateSettings{Engine: EngineJinja}}, data)
+	hookData, _ = hookSecrets(variables, aliased)
+	command, err = executor.renderCommand(`printf '%s' {{ index .cookiecutter "deploy-token" }}`, hookData)
+	require.NoError(t, err)
+	assert.Equal(t, "printf '%s' $KICK_SECRET_DEPLOY_TOKEN", command)
+	assert.Equal(t, "s3cr3t", aliased[cookiecutterAlias].(map[string]any)["deploy-token"], "the template data keeps the secret")
+}
+
+func TestExecutor_HookOptions(t *testing.T) {
+	requirePOSIXShell(t)
+
+	t.Run("when", func(t *testing.T) {
+		workDir := t.TempDir()
+		hooks := Hooks{PostGeneration: []Hook{
+			{Command: "touch docker.txt", When: "{{ .use_docker }}"},
+			{Command: "touch go.txt", When: `{{ eq .lang "go" }}`},
+		}}
+		require.NoError(t, New().ExecutePostGeneration(context.Background(), hooks, workDir, map[string]any{"use_docker": false, "lang": "go"}))
+		assert.NoFileExists(t, filepath.Join(workDir, "docker.txt"))
+		assert.FileExists(t, filepath.Join(workDir, "go.txt"))
+	})
+
+	t.Run("when skips command checks and policy", func(t *testing.T) {
+		hooks := Hooks{PostGeneration: []Hook{{Command: "kick-missing-tool-xyz", When: "{{ .enabled }}"}}}
+		data := map[string]any{"enabled": false}
+		assert.NoError(t, New().CheckCommands(hooks, data))
+		assert.NoError(t, HookPolicy{Deny: []string{"kick-missing-tool-xyz"}}.checkHooks(hooks, data))
+
+		data["enabled"] = true
+		assert.Error(t, New().CheckCommands(hooks, data))
+	})
+
+	t.Run("allow failure", func(t *testing.T) {
+		workDir := t.TempDir()
+		hooks := Hooks{PostGeneration: []Hook{
+			{Command: "exit 1", AllowFailure: true},
+			{Command: "touch after.txt"},
+		}}
+		require.NoError(t, New().ExecutePostGeneration(context.Background(), hooks, workDir, nil))
+		assert.FileExists(t, filepath.Join(workDir, "after.txt"))
+
+		hooks.PostGeneration[0].AllowFailure = false
+		assert.Error(t, New().ExecutePostGeneration(context.Background(), hooks, workDir, nil))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		hooks := Hooks{PreGeneration: []Hook{{Command: "sleep 2", Timeout: 100 * time.Millisecond}}}
+		err := New().ExecutePreGeneration(context.Background(), hooks, t.TempDir(), nil)
+		assert.ErrorContains(t, err, "timed out after 100ms")
+	})
+}
+
+func TestHookEnv(t *testing.T) {
+	cfg, err := ParseKickYAML([]byte(`name: test
+variables:
+  project_name: {type: string}
+  port: {type: number}
+  use_docker: {type: boolean}
+  api-token: {type: secret}
+  db_name: {type: string, optional: true}
+hooks:
+  env:
+    GOFLAGS: "-mod=mod"
+    IMAGE: "{{ .project_name }}:latest"
+`))
+	require.NoError(t, err)
+
+	data := templateData(cfg.Variables, map[string]any{"project_name": "demo", "port": 8080, "use_docker": true, "api-token": "s3cr3t"})
+	env, err := hookEnv(cfg, data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"KICK_VAR_PROJECT_NAME=demo",
+		"KICK_VAR_PORT=8080",
+		"KICK_VAR_USE_DOCKER=true",
+		"KICK_VAR_DB_NAME=",
+		"GOFLAGS=-mod=mod",
+		"IMAGE=demo:latest",
+	}, env)
+}
+
+func TestGenerate_HookEnv(t *testing.T) {
+	requirePOSIXShell(t)
+
+	src := writeTemplate(t, `name: test
+variables:
+  project_name: {type: string}
+hooks:
+  env:
+    GREETING: "hello {{ .project_name }}"
+  post_generation:
+    - printf '%s/%s' "$KICK_VAR_PROJECT_NAME" "$GREETING" > env.txt
+`, map[string]string{"app.txt": "ok"})
+	out := t.TempDir()
+
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"project_name": "demo"}}))
+	content, err := os.ReadFile(filepath.Join(out, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "demo/hello demo", string(content))
+}
+
+func TestShellCommand(t *testing.T) {
+	tests := []struct {
+		shell    string
+		wantArgs []string
+	}{
+		{shell: ShellSh, wantArgs: []string{"sh", "-c", "echo hi"}},
+		{shell: ShellBash, wantArgs: []string{"bash", "-c", "echo hi"}},
+		{shell: ShellPowerShell, wantArgs: []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", "echo hi"}},
+		{shell: ShellPwsh, wantArgs: []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command", "echo hi"}},
+	}
+	for _, tt := range tests {
+		cmd := shellCommand(context.Background(), tt.shell, "echo hi")
+		assert.Equal(t, tt.wantArgs, cmd.Args, tt.shell)
+	}
+
+	want := ShellSh
+	if runtime.GOOS == "windows" {
+		want = ShellPowerShell
+	}
+	assert.Equal(t, want, hookShell(Hook{Command: "echo hi"}))
+	assert.Equal(t, ShellBash, hookShell(Hook{Command: "echo hi", Shell: ShellBash}))
+}
+
+func TestExecutor_HookShell(t *testing.T) {
+	tests := []struct {
+		shell   string
+		command string
+	}{
+		{shell: ShellSh, command: "printf '%s' {{.name}} > out.txt"},
+		{shell: ShellBash, command: "[[ -n {{.name}} ]] && printf '%s' {{.name}} > out.txt"},
+		{shell: ShellPwsh, command: "Set-Content -NoNewline -Path out.txt -Value '{{.name}}'"},
+		{shell: ShellPowerShell, command: "Set-Content -NoNewline -Path out.txt -Value '{{.name}}'"},
+		{shell: ShellCmd, command: "echo|set /p={{.name}}> out.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			if _, err := exec.LookPath(tt.shell); err != nil {
+				t.Skipf("%s is not installed", tt.shell)
+			}
+			workDir := t.TempDir()
+			hooks := Hooks{PostGeneration: []Hook{{Command: tt.command, Shell: tt.shell}}}
+			require.NoError(t, New().ExecutePostGeneration(context.Background(), hooks, workDir, map[string]any{"name": "demo"}))
+
+			content, err := os.ReadFile(filepath.Join(workDir, "out.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "demo", string(content))
+		})
+	}
+
+	t.Run("secret references", func(t *testing.T) {
+		executor := New()
+		executor.env = []string{"KICK_SECRET_TOKEN=s3cr3t"}
+		alias := map[string]any{"name": "demo", "token": "$KICK_SECRET_TOKEN"}
+		data := map[string]any{"name": "demo", "token": "$KICK_SECRET_TOKEN", cookiecutterAlias: alias}
+
+		assert.Equal(t, data, executor.shellSecrets(ShellBash, data))
+		assert.Equal(t, "$env:KICK_SECRET_TOKEN", executor.shellSecrets(ShellPwsh, data)["token"])
+		assert.Equal(t, "%KICK_SECRET_TOKEN%", executor.shellSecrets(ShellCmd, data)["token"])
+		assert.Equal(t, "%KICK_SECRET_TOKEN%", executor.shellSecrets(ShellCmd, data)[cookiecutterAlias].(map[string]any)["token"], "nested maps are rewritten too")
+		assert.Equal(t, "$KICK_SECRET_TOKEN", data["token"], "the shared data is left alone")
+		assert.Equal(t, "$KICK_SECRET_TOKEN", alias["token"], "the shared data is left alone")
+	})
+
+	t.Run("missing shell", func(t *testing.T) {
+		hooks := Hooks{PostGeneration: []Hook{{Command: "echo hi", Shell: ShellZsh}}}
+		if _, err := exec.LookPath(ShellZsh); err == nil {
+			t.Skip("zsh is installed")
+		}
+		assert.EqualError(t, New().CheckCommands(hooks, nil), "hook requires 'zsh' which was not found")
+	})
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// HooksDir is the directory of a template holding hook scripts
+const HooksDir = "hooks"
+
+// hookScriptStages maps the base names of hook scripts, without extension, to their stage.
+// The _project names are Cookiecutter's.
+var hookScriptStages = map[string]string{
+	"pre_gen":          "pre_generation",
+	"pre_gen_project":  "pre_generation",
+	"post_gen":         "post_generation",
+	"post_gen_project": "post_generation",
+}
+
+// addScriptHooks appends the scripts in the template's hooks directory to the hooks of their
+// stage, after those of kick.yaml. When the whole template is rendered, the hooks directory
+// is left out of the output.
+func addScriptHooks(templatePath string, cfg *Config) error {
+	entries, err := os.ReadDir(filepath.Join(templatePath, HooksDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", HooksDir, err)
+	}
+
+	seen := map[string]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		stage, ok := hookScriptStages[strings.TrimSuffix(name, filepath.Ext(name))]
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if other, ok := seen[stage]; ok {
+			return fmt.Errorf("%s: %s and %s are both %s hook scripts", HooksDir, other, name, stage)
+		}
+		seen[stage] = name
+
+		hook := Hook{Script: filepath.Join(HooksDir, name)}
+		if stage == "pre_generation" {
+			cfg.Hooks.PreGeneration = append(cfg.Hooks.PreGeneration, hook)
+		} else {
+			cfg.Hooks.PostGeneration = append(cfg.Hooks.PostGeneration, hook)
+		}
+	}
+	if len(seen) > 0 && cfg.Template.Root == "" {
+		cfg.Template.hooksDir = HooksDir
+	}
+	return nil
+}
+
+// prepareScriptHooks renders the hook scripts of a template into a temporary directory, like
+// template files, and sets the command of each script hook to run its rendered copy. The
+// returned function removes the rendered scripts.
+//
+// Scripts are rendered with the hook data, so a secret renders as its environment variable
+// reference, e.g. $KICK_SECRET_TOKEN, which only a shell script expands. Scripts in other
+// languages must read secrets from the environment.
+func prepareScriptHooks(hooks *Hooks, templatePath string, data map[string]any, settings TemplateSettings) (func(), error) {
+	var dir string
+	cleanup := func() {
+		if dir != "" {
+			_ = os.RemoveAll(dir)
+		}
+	}
+
+	for _, list := range [][]Hook{hooks.PreGeneration, hooks.PostGeneration} {
+		for i := range list {
+			hook := &list[i]
+			if hook.Script == "" {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(templatePath, hook.Script))
+			if err != nil {
+				return cleanup, fmt.Errorf("read hook script: %w", err)
+			}
+			tmpl, err := prepareTemplate(string(stripBOM(content)), settings)
+			if err != nil {
+				return cleanup, fmt.Errorf("prepare hook script %s: %w", hook.Script, err)
+			}
+			rend := NewRenderer()
+			rend.delims = settings.Delimiters
+			script, err := rend.renderBytes([]byte(tmpl), data)
+			if err != nil {
+				return cleanup, fmt.Errorf("render hook script %s: %w", hook.Script, err)
+			}
+
+			if dir == "" {
+				if dir, err = os.MkdirTemp("", "kick-hooks-"); err != nil {
+					return cleanup, fmt.Errorf("create hook script directory: %w", err)
+				}
+			}
+			path := filepath.Join(dir, filepath.Base(hook.Script))
+			if err := os.WriteFile(path, script, 0o700); err != nil {
+				return cleanup, fmt.Errorf("write hook script: %w", err)
+			}
+			hook.Shell, hook.Command = scriptCommand(hook.Shell, path)
+		}
+	}
+	return cleanup, nil
+}
+
+// scriptCommand returns the shell and command that run a hook script: shell, PowerShell and
+// batch scripts in their shell, Python scripts with the platform's interpreter and anything
+// else directly, by its #! line. A shell set on the hook runs the script when it can: bash
+// or zsh for a .sh script, pwsh or powershell for a .ps1 script, and any shell for the rest.
+func scriptCommand(shell, path string) (string, string) {
+	switch filepath.Ext(path) {
+	case ".sh":
+		if shell != ShellBash && shell != ShellZsh {
+			shell = ShellSh
+		}
+		return shell, shell + " " + shellQuote(shell, path)
+	case ".ps1":
+		if shell != ShellPowerShell && shell != ShellPwsh {
+			if shell = hookShell(Hook{}); shell != ShellPowerShell {
+				shell = ShellPwsh
+			}
+		}
+		return shell, "& " + shellQuote(shell, path)
+	case ".bat", ".cmd":
+		return ShellCmd, shellQuote(ShellCmd, path)
+	case ".py":
+		shell = hookShell(Hook{Shell: shell})
+		if runtime.GOOS == "windows" {
+			return shell, "python " + shellQuote(shell, path)
+		}
+		return shell, "python3 " + shellQuote(shell, path)
+	default:
+		if shell == "" {
+			shell = ShellSh
+		}
+		return shell, shellQuote(shell, path)
+	}
+}
+
+// shellQuote quotes s as a single word for shell
+func shellQuote(shell, s string) string {
+	switch shell {
+	case ShellCmd:
+		return `"` + s + `"`
+	case ShellPowerShell, ShellPwsh:
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+}
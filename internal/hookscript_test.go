@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_HookScripts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use a POSIX shell")
+	}
+
+	t.Run("rendered and run after kick.yaml hooks", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\nhooks:\n  post_generation:\n    - touch inline.txt\n", map[string]string{
+			"app.txt":          "{{ .name }}",
+			"hooks/pre_gen.sh": "test -f kick.yaml\n",
+			"hooks/post_gen.sh": "set -e\ntest -f inline.txt\n" +
+				"{{ if .name }}printf '%s' '{{ .name }}' > from-script.txt{{ end }}\n",
+		})
+		out := t.TempDir()
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"name": "demo"}}))
+		content, err := os.ReadFile(filepath.Join(out, "from-script.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "demo", string(content))
+		assert.NoDirExists(t, filepath.Join(out, HooksDir), "hook scripts are not generated")
+	})
+
+	t.Run("failing script stops generation", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\n", map[string]string{
+			"app.txt":          "ok",
+			"hooks/pre_gen.sh": "exit 3\n",
+		})
+		out := t.TempDir()
+
+		err := Generate(Options{Source: src, OutputDir: out})
+		assert.ErrorContains(t, err, "execute pre-generation hook")
+		assert.NoFileExists(t, filepath.Join(out, "app.txt"))
+	})
+
+	t.Run("python script", func(t *testing.T) {
+		if _, err := exec.LookPath("python3"); err != nil {
+			t.Skip("python3 is not installed")
+		}
+		src := writeTemplate(t, "name: test\n", map[string]string{
+			"hooks/post_gen_project.py": "open('{{ .name }}.txt', 'w').write('from python')\n",
+		})
+		out := t.TempDir()
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, Answers: map[string]string{"name": "demo"}}))
+		content, err := os.ReadFile(filepath.Join(out, "demo.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "from python", string(content))
+	})
+
+	t.Run("skipped with the other hooks", func(t *testing.T) {
+		src := writeTemplate(t, "name: test\n", map[string]string{
+			"app.txt":           "ok",
+			"hooks/post_gen.sh": "touch hooked.txt\n",
+		})
+		out := t.TempDir()
+
+		require.NoError(t, Generate(Options{Source: src, OutputDir: out, SkipHooks: true}))
+		assert.FileExists(t, filepath.Join(out, "app.txt"))
+		assert.NoFileExists(t, filepath.Join(out, "hooked.txt"))
+	})
+}
+
+func TestAddScriptHooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		files       []string
+		wantPre     []Hook
+		wantPost    []Hook
+		wantHookDir string
+		errContains string
+	}{
+		{
+			name:        "scripts of both stages",
+			config:      Config{Hooks: Hooks{PostGeneration: []Hook{{Command: "make"}}}},
+			files:       []string{"pre_gen.sh", "post_gen_project.py", "helpers.sh"},
+			wantPre:     []Hook{{Script: filepath.Join(HooksDir, "pre_gen.sh")}},
+			wantPost:    []Hook{{Command: "make"}, {Script: filepath.Join(HooksDir, "post_gen_project.py")}},
+			wantHookDir: HooksDir,
+		},
+		{
+			name:     "hooks directory outside the rendered root",
+			config:   Config{Template: TemplateSettings{Root: "template"}},
+			files:    []string{"post_gen"},
+			wantPost: []Hook{{Script: filepath.Join(HooksDir, "post_gen")}},
+		},
+		{
+			name:  "no hook scripts",
+			files: []string{"pre-commit"},
+		},
+		{
+			name:        "two scripts for one stage",
+			files:       []string{"pre_gen.sh", "pre_gen.py"},
+			errContains: "are both pre_generation hook scripts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(dir, HooksDir), 0o755))
+			for _, name := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, HooksDir, name), []byte("true\n"), 0o644))
+			}
+
+			cfg := tt.config
+			err := addScriptHooks(dir, &cfg)
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPre, cfg.Hooks.PreGeneration)
+			assert.Equal(t, tt.wantPost, cfg.Hooks.PostGeneration)
+			assert.Equal(t, tt.wantHookDir, cfg.Template.hooksDir)
+		})
+	}
+}
+
+func TestScriptCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("expects the POSIX default shell")
+	}
+
+	tests := []struct {
+		shell       string
+		path        string
+		wantShell   string
+		wantCommand string
+	}{
+		{path: "/tmp/hooks/pre_gen.sh", wantShell: ShellSh, wantCommand: "sh '/tmp/hooks/pre_gen.sh'"},
+		{shell: ShellBash, path: "/tmp/hooks/pre_gen.sh", wantShell: ShellBash, wantCommand: "bash '/tmp/hooks/pre_gen.sh'"},
+		{shell: ShellCmd, path: "/tmp/hooks/pre_gen.sh", wantShell: ShellSh, wantCommand: "sh '/tmp/hooks/pre_gen.sh'"},
+		{shell: ShellPowerShell, path: "/tmp/hooks/post_gen.ps1", wantShell: ShellPowerShell, wantCommand: "& '/tmp/hooks/post_gen.ps1'"},
+		{shell: ShellBash, path: "/tmp/hooks/post_gen.py", wantShell: ShellBash, wantCommand: "python3 '/tmp/hooks/post_gen.py'"},
+		{path: "/tmp/hooks/post_gen.py", wantShell: ShellSh, wantCommand: "python3 '/tmp/hooks/post_gen.py'"},
+		{path: "/tmp/hooks/post_gen.ps1", wantShell: ShellPwsh, wantCommand: "& '/tmp/hooks/post_gen.ps1'"},
+		{path: "/tmp/hooks/post_gen.bat", wantShell: ShellCmd, wantCommand: `"/tmp/hooks/post_gen.bat"`},
+		{path: "/tmp/it's/post_gen", wantShell: ShellSh, wantCommand: `'/tmp/it'\''s/post_gen'`},
+	}
+	for _, tt := range tests {
+		shell, command := scriptCommand(tt.shell, tt.path)
+		assert.Equal(t, tt.wantShell, shell, tt.path)
+		assert.Equal(t, tt.wantCommand, command, tt.path)
+	}
+}
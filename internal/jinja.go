@@ -0,0 +1,690 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Template engines supported by TemplateSettings.Engine
+const (
+	EngineGo    = "go"
+	EngineJinja = "jinja"
+)
+
+// jinjaFilters maps supported Jinja filters to template functions.
+// The default filter is handled separately because it translates to `or`.
+var jinjaFilters = map[string]string{
+	"lower":   "lower",
+	"upper":   "upper",
+	"title":   "title",
+	"trim":    "trim",
+	"replace": "replace",
+	"length":  "len",
+	"count":   "len",
+}
+
+// jinjaMethods maps the Python string methods Cookiecutter defaults call, such as
+// {{ cookiecutter.name.lower().replace(' ', '_') }}, to template functions.
+var jinjaMethods = map[string]string{
+	"lower":   "lower",
+	"upper":   "upper",
+	"title":   "title",
+	"strip":   "trim",
+	"replace": "replace",
+}
+
+// jinjaCompare maps Jinja comparison operators to template functions.
+var jinjaCompare = map[string]string{
+	"==": "eq",
+	"!=": "ne",
+	"<":  "lt",
+	"<=": "le",
+	">":  "gt",
+	">=": "ge",
+}
+
+var endRawPattern = regexp.MustCompile(`\{%-?\s*endraw\s*-?%\}`)
+
+// kickRawPattern matches the body of a {{/* kick:raw */}} marker, and kickEndRawPattern a
+// whole {{/* kick:endraw */}} marker, which Jinja templates can use like Go templates
+var (
+	kickRawPattern    = regexp.MustCompile(`^/\*\s*kick:raw\s*\*/$`)
+	kickEndRawPattern = regexp.MustCompile(`\{\{/\*\s*kick:endraw\s*\*/\}\}`)
+)
+
+// prepareTemplate converts template source into Go template syntax for the configured engine.
+func prepareTemplate(src string, settings TemplateSettings) (string, error) {
+	if settings.Engine == EngineJinja {
+		return translateJinja(src)
+	}
+	return src, nil
+}
+
+// translateJinja converts the subset of Jinja used by Cookiecutter templates into
+// Go template syntax: {{ expr }} with filters, {% if/elif/else/endif %},
+// {% for x in xs %}/{% endfor %}, {% set x = expr %}, {% raw %} blocks,
+// {# comments #} and whitespace control. Any other statement is reported as unsupported.
+func translateJinja(src string) (string, error) {
+	return (&jinjaTranslator{}).translate(src)
+}
+
+// translate converts Jinja source as described for translateJinja
+func (t *jinjaTranslator) translate(src string) (string, error) {
+	var out strings.Builder
+	var blocks []string
+	// starts holds where each open block's opening action begins in out, and hoists the
+	// declarations to insert there, for names first set inside an if block
+	var starts []jinjaBlockStart
+	var hoists []jinjaHoist
+	t.setVars = [][]string{nil}
+
+	rest := src
+	for {
+		i := nextJinjaTag(rest)
+		if i < 0 {
+			out.WriteString(rest)
+			break
+		}
+		out.WriteString(rest[:i])
+		rest = rest[i:]
+
+		open := rest[:2]
+		closer := map[string]string{"{{": "}}", "{%": "%}", "{#": "#}"}[open]
+		end := strings.Index(rest[2:], closer)
+		if end < 0 {
+			return "", fmt.Errorf("unclosed %s tag", open)
+		}
+		inner := rest[2 : 2+end]
+		rest = rest[2+end+2:]
+
+		trimLeft := strings.HasPrefix(inner, "-")
+		trimRight := strings.HasSuffix(inner, "-")
+		inner = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(inner, "-"), "-"))
+
+		switch open {
+		case "{#":
+			// Comments are dropped; only their whitespace control survives
+			if trimLeft || trimRight {
+				left, right := "{{/*", "*/}}"
+				if trimLeft {
+					left = "{{- /*"
+				}
+				if trimRight {
+					right = "*/ -}}"
+				}
+				out.WriteString(left + right)
+			}
+
+		case "{{":
+			// kick:raw regions are kept for the renderer, which emits them as written
+			if kickRawPattern.MatchString(inner) {
+				loc := kickEndRawPattern.FindStringIndex(rest)
+				if loc == nil {
+					return "", fmt.Errorf("kick:raw region is not closed with {{/* kick:endraw */}}")
+				}
+				out.WriteString("{{/* kick:raw */}}" + rest[:loc[0]] + "{{/* kick:endraw */}}")
+				rest = rest[loc[1]:]
+				continue
+			}
+			if kickEndRawPattern.MatchString("{{" + inner + "}}") {
+				return "", fmt.Errorf("kick:endraw without a matching {{/* kick:raw */}}")
+			}
+			expr, err := t.expression(inner)
+			if err != nil {
+				return "", fmt.Errorf("translate {{ %s }}: %w", inner, err)
+			}
+			out.WriteString(goAction(expr, trimLeft, trimRight))
+
+		case "{%":
+			keyword, args, _ := strings.Cut(inner, " ")
+			args = strings.TrimSpace(args)
+
+			switch keyword {
+			case "raw":
+				loc := endRawPattern.FindStringIndex(rest)
+				if loc == nil {
+					return "", fmt.Errorf("unclosed {%% raw %%} block")
+				}
+				out.WriteString(strings.ReplaceAll(rest[:loc[0]], "{{", `{{"{{"}}`))
+				rest = rest[loc[1]:]
+
+			case "if", "elif":
+				expr, err := t.expression(args)
+				if err != nil {
+					return "", fmt.Errorf("translate {%% %s %%}: %w", inner, err)
+				}
+				if keyword == "if" {
+					blocks = append(blocks, "if")
+					starts = append(starts, jinjaBlockStart{out.Len(), trimLeft})
+					t.setVars = append(t.setVars, nil)
+					out.WriteString(goAction("if "+expr, trimLeft, trimRight))
+					continue
+				}
+				if len(blocks) == 0 || blocks[len(blocks)-1] != "if" {
+					return "", fmt.Errorf("{%% elif %%} outside of {%% if %%}")
+				}
+				// Each branch is a scope of its own
+				t.setVars[len(t.setVars)-1] = nil
+				out.WriteString(goAction("else if "+expr, trimLeft, trimRight))
+
+			case "else":
+				if len(blocks) == 0 {
+					return "", fmt.Errorf("{%% else %%} outside of a block")
+				}
+				t.setVars[len(t.setVars)-1] = nil
+				out.WriteString(goAction("else", trimLeft, trimRight))
+
+			case "for":
+				action, err := t.forLoop(args)
+				if err != nil {
+					return "", fmt.Errorf("translate {%% %s %%}: %w", inner, err)
+				}
+				blocks = append(blocks, "for")
+				starts = append(starts, jinjaBlockStart{out.Len(), trimLeft})
+				t.setVars = append(t.setVars, nil)
+				out.WriteString(goAction(action, trimLeft, trimRight))
+
+			case "set":
+				// An if block does not open a scope in Jinja, so a name first set inside one
+				// is declared before the outermost if around it, up to the innermost for loop
+				scope := len(blocks)
+				for scope > 0 && blocks[scope-1] == "if" {
+					scope--
+				}
+				action, decl, err := t.set(args, scope)
+				if err != nil {
+					return "", fmt.Errorf("translate {%% %s %%}: %w", inner, err)
+				}
+				if decl != "" {
+					start := starts[scope]
+					hoists = append(hoists, jinjaHoist{start.pos, goAction(decl, start.trimLeft, false)})
+				}
+				out.WriteString(goAction(action, trimLeft, trimRight))
+
+			case "endif", "endfor":
+				want := strings.TrimPrefix(keyword, "end")
+				if len(blocks) == 0 || blocks[len(blocks)-1] != want {
+					return "", fmt.Errorf("unexpected {%% %s %%}", keyword)
+				}
+				blocks = blocks[:len(blocks)-1]
+				starts = starts[:len(starts)-1]
+				t.setVars = t.setVars[:len(t.setVars)-1]
+				if want == "for" {
+					t.loopVars = t.loopVars[:len(t.loopVars)-1]
+				}
+				out.WriteString(goAction("end", trimLeft, trimRight))
+
+			default:
+				return "", fmt.Errorf("unsupported Jinja statement {%% %s %%}", keyword)
+			}
+		}
+	}
+
+	if len(blocks) > 0 {
+		return "", fmt.Errorf("unclosed {%% %s %%} block", blocks[len(blocks)-1])
+	}
+
+	// Insert the hoisted declarations back to front, so earlier positions stay valid
+	result := out.String()
+	slices.SortStableFunc(hoists, func(a, b jinjaHoist) int { return b.pos - a.pos })
+	for _, h := range hoists {
+		result = result[:h.pos] + h.action + result[h.pos:]
+	}
+	return result, nil
+}
+
+// jinjaBlockStart records where the opening action of a block begins in the translated output,
+// and whether it trims the whitespace before it
+type jinjaBlockStart struct {
+	pos      int
+	trimLeft bool
+}
+
+// jinjaHoist is a declaration to insert into the translated output at pos
+type jinjaHoist struct {
+	pos    int
+	action string
+}
+
+// nextJinjaTag returns the index of the next {{, {% or {# opener, or -1.
+func nextJinjaTag(s string) int {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] == '{' && (s[i+1] == '{' || s[i+1] == '%' || s[i+1] == '#') {
+			return i
+		}
+	}
+	return -1
+}
+
+// goAction wraps an action body in Go template delimiters with optional whitespace trimming.
+func goAction(body string, trimLeft, trimRight bool) string {
+	left, right := "{{ ", " }}"
+	if trimLeft {
+		left = "{{- "
+	}
+	if trimRight {
+		right = " -}}"
+	}
+	return left + body + right
+}
+
+// jinjaTranslator tracks the state needed to translate expressions, such as loop variables in scope.
+type jinjaTranslator struct {
+	flatten  bool // translate cookiecutter.x to .x
+	loopVars [][]string
+	// setVars holds the names assigned with {% set %}, per open block, outermost first
+	setVars [][]string
+	tokens  []string
+	pos     int
+}
+
+// forLoop translates "x in xs" or "k, v in m.items()" into a range action.
+func (t *jinjaTranslator) forLoop(args string) (string, error) {
+	target, iterable, ok := strings.Cut(args, " in ")
+	if !ok {
+		return "", fmt.Errorf("expected 'for <name> in <expression>'")
+	}
+
+	var names []string
+	for _, name := range strings.Split(target, ",") {
+		name = strings.TrimSpace(name)
+		if !isJinjaIdent(name) {
+			return "", fmt.Errorf("invalid loop variable %q", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) > 2 {
+		return "", fmt.Errorf("at most two loop variables are supported")
+	}
+
+	iterable = strings.TrimSuffix(strings.TrimSpace(iterable), ".items()")
+	expr, err := t.expression(iterable)
+	if err != nil {
+		return "", err
+	}
+
+	// Loop variables only come into scope inside the loop body
+	t.loopVars = append(t.loopVars, names)
+	vars := make([]string, len(names))
+	for i, name := range names {
+		vars[i] = "$" + name
+	}
+	return "range " + strings.Join(vars, ", ") + " := " + expr, nil
+}
+
+// set translates "name = expr" into a variable declaration, or an assignment when the name is
+// already in scope. A new name belongs to the block scope given, counted from the outermost;
+// when that is not the innermost block, set returns an assignment along with the declaration
+// the caller has to place before the enclosing blocks.
+func (t *jinjaTranslator) set(args string, scope int) (action, decl string, err error) {
+	name, value, ok := strings.Cut(args, "=")
+	name = strings.TrimSpace(name)
+	if !ok || !isJinjaIdent(name) {
+		return "", "", fmt.Errorf("expected 'set <name> = <expression>'")
+	}
+	expr, err := t.expression(value)
+	if err != nil {
+		return "", "", err
+	}
+
+	if t.isLocal(name) {
+		return "$" + name + " = " + expr, "", nil
+	}
+	t.setVars[scope] = append(t.setVars[scope], name)
+	if scope < len(t.setVars)-1 {
+		return "$" + name + " = " + expr, "$" + name + ` := ""`, nil
+	}
+	return "$" + name + " := " + expr, "", nil
+}
+
+// expression translates a Jinja expression into a Go template pipeline.
+func (t *jinjaTranslator) expression(src string) (string, error) {
+	tokens, err := tokenizeJinja(src)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("empty expression")
+	}
+
+	t.tokens, t.pos = tokens, 0
+	expr, err := t.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if t.pos < len(t.tokens) {
+		return "", fmt.Errorf("unexpected %q", t.tokens[t.pos])
+	}
+	return expr, nil
+}
+
+func (t *jinjaTranslator) peek() string {
+	if t.pos < len(t.tokens) {
+		return t.tokens[t.pos]
+	}
+	return ""
+}
+
+func (t *jinjaTranslator) next() string {
+	tok := t.peek()
+	t.pos++
+	return tok
+}
+
+func (t *jinjaTranslator) parseOr() (string, error) {
+	left, err := t.parseAnd()
+	if err != nil {
+		return "", err
+	}
+	for t.peek() == "or" {
+		t.next()
+		right, err := t.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		left = "(or " + left + " " + right + ")"
+	}
+	return left, nil
+}
+
+func (t *jinjaTranslator) parseAnd() (string, error) {
+	left, err := t.parseNot()
+	if err != nil {
+		return "", err
+	}
+	for t.peek() == "and" {
+		t.next()
+		right, err := t.parseNot()
+		if err != nil {
+			return "", err
+		}
+		left = "(and " + left + " " + right + ")"
+	}
+	return left, nil
+}
+
+func (t *jinjaTranslator) parseNot() (string, error) {
+	if t.peek() == "not" {
+		t.next()
+		operand, err := t.parseNot()
+		if err != nil {
+			return "", err
+		}
+		return "(not " + operand + ")", nil
+	}
+	return t.parseCompare()
+}
+
+func (t *jinjaTranslator) parseCompare() (string, error) {
+	left, err := t.parseFilter()
+	if err != nil {
+		return "", err
+	}
+
+	// x in xs and x not in xs test membership
+	negate := t.peek() == "not" && t.pos+1 < len(t.tokens) && t.tokens[t.pos+1] == "in"
+	if negate {
+		t.next()
+	}
+	if t.peek() == "in" {
+		t.next()
+		right, err := t.parseFilter()
+		if err != nil {
+			return "", err
+		}
+		if negate {
+			return "(not (has " + left + " " + right + "))", nil
+		}
+		return "(has " + left + " " + right + ")", nil
+	}
+
+	fn, ok := jinjaCompare[t.peek()]
+	if !ok {
+		return left, nil
+	}
+	t.next()
+	right, err := t.parseFilter()
+	if err != nil {
+		return "", err
+	}
+	return "(" + fn + " " + left + " " + right + ")", nil
+}
+
+func (t *jinjaTranslator) parseFilter() (string, error) {
+	value, err := t.parsePrimary()
+	if err != nil {
+		return "", err
+	}
+
+	for t.peek() == "|" {
+		t.next()
+		name := t.next()
+
+		var args []string
+		if t.peek() == "(" {
+			if args, err = t.arguments("filter", name); err != nil {
+				return "", err
+			}
+		}
+
+		if name == "default" || name == "d" {
+			if len(args) != 1 {
+				return "", fmt.Errorf("filter %q takes one argument", name)
+			}
+			value = "(or " + value + " " + args[0] + ")"
+			continue
+		}
+
+		fn, ok := jinjaFilters[name]
+		if !ok {
+			return "", fmt.Errorf("unsupported Jinja filter %q", name)
+		}
+		value = "(" + strings.Join(append([]string{fn, value}, args...), " ") + ")"
+	}
+	return value, nil
+}
+
+func (t *jinjaTranslator) parsePrimary() (string, error) {
+	tok := t.next()
+	switch {
+	case tok == "":
+		return "", fmt.Errorf("unexpected end of expression")
+
+	case tok == "(":
+		inner, err := t.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if t.next() != ")" {
+			return "", fmt.Errorf("missing ')'")
+		}
+		return inner, nil
+
+	case tok[0] == '"' || tok[0] == '\'':
+		return strconv.Quote(unquoteJinja(tok)), nil
+
+	case unicode.IsDigit(rune(tok[0])):
+		return tok, nil
+
+	case tok == "-":
+		// Only number literals can be negated; there is no arithmetic
+		if num := t.peek(); num != "" && unicode.IsDigit(rune(num[0])) {
+			return "-" + t.next(), nil
+		}
+		return "", fmt.Errorf("unsupported '-': only number literals can be negative")
+	}
+
+	switch tok {
+	case "true", "True":
+		return "true", nil
+	case "false", "False":
+		return "false", nil
+	case "none", "None":
+		return "nil", nil
+	}
+
+	// A path can end in a string method call, e.g. name.lower()
+	var method string
+	if i := strings.LastIndex(tok, "."); i > 0 && t.peek() == "(" {
+		if _, ok := jinjaMethods[tok[i+1:]]; ok {
+			tok, method = tok[:i], tok[i+1:]
+		}
+	}
+	if !isJinjaPath(tok) {
+		return "", fmt.Errorf("unexpected %q", tok)
+	}
+
+	value := t.variable(tok)
+	for method != "" {
+		args, err := t.arguments("method", method)
+		if err != nil {
+			return "", err
+		}
+		value = "(" + strings.Join(append([]string{jinjaMethods[method], value}, args...), " ") + ")"
+
+		// Calls chain, e.g. .lower().replace(' ', '_')
+		method = ""
+		if next, ok := strings.CutPrefix(t.peek(), "."); ok && t.pos+1 < len(t.tokens) && t.tokens[t.pos+1] == "(" {
+			if _, known := jinjaMethods[next]; !known {
+				return "", fmt.Errorf("unsupported method %q", next)
+			}
+			t.next()
+			method = next
+		}
+	}
+	return value, nil
+}
+
+// variable translates a dotted name. With flatten, Cookiecutter's cookiecutter.x refers to
+// the answer x directly, for contexts such as defaults that have no cookiecutter map.
+func (t *jinjaTranslator) variable(path string) string {
+	if rest, ok := strings.CutPrefix(path, "cookiecutter."); ok && t.flatten {
+		path = rest
+	}
+	root, _, _ := strings.Cut(path, ".")
+	if t.isLocal(root) {
+		return "$" + path
+	}
+	return "." + path
+}
+
+// arguments parses the parenthesized, comma-separated arguments of a filter or method call
+func (t *jinjaTranslator) arguments(kind, name string) ([]string, error) {
+	t.next() // (
+	var args []string
+	for t.peek() != ")" {
+		if t.peek() == "" {
+			return nil, fmt.Errorf("missing ')' after arguments of %s %q", kind, name)
+		}
+		arg, err := t.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if t.peek() == "," {
+			t.next()
+		} else if t.peek() != ")" {
+			return nil, fmt.Errorf("expected ',' or ')' in arguments of %s %q", kind, name)
+		}
+	}
+	t.next()
+	return args, nil
+}
+
+// isLocal reports whether name is a loop variable of an enclosing for block or was set
+// with {% set %} in scope.
+func (t *jinjaTranslator) isLocal(name string) bool {
+	for _, names := range append(append([][]string{}, t.loopVars...), t.setVars...) {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tokenizeJinja splits an expression into identifiers, literals and operators.
+func tokenizeJinja(src string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, src[i:j+1])
+			i = j + 1
+
+		case strings.HasPrefix(src[i:], "==") || strings.HasPrefix(src[i:], "!=") ||
+			strings.HasPrefix(src[i:], "<=") || strings.HasPrefix(src[i:], ">="):
+			tokens = append(tokens, src[i:i+2])
+			i += 2
+
+		case strings.ContainsRune("<>|(),-", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+
+		case c == '_' || c == '.' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)):
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unsupported character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+// unquoteJinja strips the quotes of a string literal and resolves backslash escapes.
+func unquoteJinja(tok string) string {
+	body := tok[1 : len(tok)-1]
+	var out strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] == '\\' && i+1 < len(body) {
+			i++
+		}
+		out.WriteByte(body[i])
+	}
+	return out.String()
+}
+
+func isJinjaIdent(s string) bool {
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isJinjaPath(s string) bool {
+	for _, part := range strings.Split(s, ".") {
+		if !isJinjaIdent(part) {
+			return false
+		}
+	}
+	return true
+}
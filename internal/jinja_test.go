@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateJinja(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:  "plain text",
+			input: "no tags here }}",
+			want:  "no tags here }}",
+		},
+		{
+			name:  "variable",
+			input: "{{ cookiecutter.project_name }}",
+			want:  "{{ .cookiecutter.project_name }}",
+		},
+		{
+			name:  "filters with arguments",
+			input: "{{ name | lower | replace(' ', '-') }}",
+			want:  `{{ (replace (lower .name) " " "-") }}`,
+		},
+		{
+			name:  "string methods",
+			input: "{{ cookiecutter.name.strip().lower().replace(' ', '_') }}",
+			want:  `{{ (replace (lower (trim .cookiecutter.name)) " " "_") }}`,
+		},
+		{
+			name:        "unsupported method",
+			input:       "{{ name.lower().split() }}",
+			wantErr:     true,
+			errContains: `unsupported method "split"`,
+		},
+		{
+			name:  "default filter",
+			input: "{{ license | default('MIT') }}",
+			want:  `{{ (or .license "MIT") }}`,
+		},
+		{
+			name:  "if elif else",
+			input: "{% if db == 'postgres' %}pg{% elif db != 'none' and not legacy %}other{% else %}none{% endif %}",
+			want:  `{{ if (eq .db "postgres") }}pg{{ else if (and (ne .db "none") (not .legacy)) }}other{{ else }}none{{ end }}`,
+		},
+		{
+			name:  "for loop with loop variable",
+			input: "{% for svc in services %}- {{ svc | upper }}\n{% endfor %}",
+			want:  "{{ range $svc := .services }}- {{ (upper $svc) }}\n{{ end }}",
+		},
+		{
+			name:  "for loop over items",
+			input: "{% for k, v in env.items() %}{{ k }}={{ v }}{% endfor %}",
+			want:  "{{ range $k, $v := .env }}{{ $k }}={{ $v }}{{ end }}",
+		},
+		{
+			name:  "whitespace control",
+			input: "a\n{%- if x -%}\nb\n{%- endif %}",
+			want:  "a\n{{- if .x -}}\nb\n{{- end }}",
+		},
+		{
+			name:  "set declares and then assigns",
+			input: "{% set pkg = name | lower %}{{ pkg }}{% if x %}{% set pkg = 'x' %}{% endif %}{{ pkg }}",
+			want:  `{{ $pkg := (lower .name) }}{{ $pkg }}{{ if .x }}{{ $pkg = "x" }}{{ end }}{{ $pkg }}`,
+		},
+		{
+			name:  "set inside a for loop is scoped to it",
+			input: "{% for s in xs %}{% set n = s %}{{ n }}{% endfor %}",
+			want:  "{{ range $s := .xs }}{{ $n := $s }}{{ $n }}{{ end }}",
+		},
+		{
+			name:  "set inside an if block is visible after it",
+			input: "{% if y %}{% if z %}{% set n = 1 %}{% endif %}{% else %}{% set n = 2 %}{% endif %}{{ n }}",
+			want:  `{{ $n := "" }}{{ if .y }}{{ if .z }}{{ $n = 1 }}{{ end }}{{ else }}{{ $n = 2 }}{{ end }}{{ $n }}`,
+		},
+		{
+			name:  "set inside an if block in a for loop",
+			input: "{% for s in xs %}\n{%- if s %}{% set n = s %}{% endif %}{{ n }}{% endfor %}",
+			want:  "{{ range $s := .xs }}\n{{- $n := \"\" }}{{- if $s }}{{ $n = $s }}{{ end }}{{ $n }}{{ end }}",
+		},
+		{
+			name:        "invalid set",
+			input:       "{% set a.b = 1 %}",
+			wantErr:     true,
+			errContains: "expected 'set <name> = <expression>'",
+		},
+		{
+			name:  "comments are dropped",
+			input: "a{# note #}b{#- trimmed -#}c",
+			want:  "ab{{- /**/ -}}c",
+		},
+		{
+			name:  "raw block is emitted literally",
+			input: "{% raw %}${{ github.sha }}{% endraw %}",
+			want:  `${{"{{"}} github.sha }}`,
+		},
+		{
+			name:  "in and not in",
+			input: "{% if 'api' in services %}{{ 'x' not in name }}{% endif %}",
+			want:  `{{ if (has "api" .services) }}{{ (not (has "x" .name)) }}{{ end }}`,
+		},
+		{
+			name:  "negative number",
+			input: "{% if count > -1 %}{{ -2.5 }}{% endif %}",
+			want:  "{{ if (gt .count -1) }}{{ -2.5 }}{{ end }}",
+		},
+		{
+			name:        "negated variable",
+			input:       "{{ -count }}",
+			wantErr:     true,
+			errContains: "only number literals can be negative",
+		},
+		{
+			name:  "kick:raw region is kept for the renderer",
+			input: "{{/* kick:raw */}}{{ x }}{% if %}{{/* kick:endraw */}}{{ y }}",
+			want:  "{{/* kick:raw */}}{{ x }}{% if %}{{/* kick:endraw */}}{{ .y }}",
+		},
+		{
+			name:        "unclosed kick:raw region",
+			input:       "{{/* kick:raw */}}{{ x }}",
+			wantErr:     true,
+			errContains: "kick:raw region is not closed",
+		},
+		{
+			name:        "kick:endraw without kick:raw",
+			input:       "{{ x }}{{/* kick:endraw */}}",
+			wantErr:     true,
+			errContains: "kick:endraw without a matching",
+		},
+		{
+			name:        "unsupported statement",
+			input:       "{% include 'x.txt' %}",
+			wantErr:     true,
+			errContains: "unsupported Jinja statement {% include %}",
+		},
+		{
+			name:        "unsupported filter",
+			input:       "{{ name | slugify }}",
+			wantErr:     true,
+			errContains: `unsupported Jinja filter "slugify"`,
+		},
+		{
+			name:        "unclosed block",
+			input:       "{% if x %}yes",
+			wantErr:     true,
+			errContains: "unclosed {% if %} block",
+		},
+		{
+			name:        "mismatched end",
+			input:       "{% for x in xs %}{% endif %}",
+			wantErr:     true,
+			errContains: "unexpected {% endif %}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := translateJinja(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderer_JinjaEngine(t *testing.T) {
+	srcRoot := t.TempDir()
+	outRoot := t.TempDir()
+
+	dir := filepath.Join(srcRoot, "{{ cookiecutter.slug }}")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	content := "{% set title = cookiecutter.name | title %}# {{ title }}\n{% if cookiecutter.use_docker == 'y' %}docker: true\n{% endif %}" +
+		"{% if 'api' in cookiecutter.services %}{% set first = 'api' %}{% endif %}{{ first }}\n{{/* kick:raw */}}${{ env.HOME }}{{/* kick:endraw */}}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644))
+
+	data := map[string]any{
+		"cookiecutter": map[string]any{"slug": "my-app", "name": "my app", "use_docker": "y", "services": []any{"api"}},
+	}
+	err := NewRenderer().RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{Engine: EngineJinja})
+	require.NoError(t, err)
+
+	got, err := os.ReadFile(filepath.Join(outRoot, "my-app", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# My App\ndocker: true\napi\n${{ env.HOME }}\n", string(got))
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+)
+
+// Lint resolves a template and checks it for likely authoring mistakes.
+// It returns one human-readable finding per problem; an error means the template could not be checked at all.
+func Lint(source string) ([]string, error) {
+	resolver := NewResolver()
+	templatePath, cleanup, err := resolver.Resolve(source)
+	if err != nil {
+		return nil, fmt.Errorf("resolve template: %v", err)
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+
+	cfg, err := loadConfig(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := lintTemplate(templatePath, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := configUnknownKeys(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		findings = append(findings, fmt.Sprintf("%s %s", KickYAML, key))
+	}
+	return findings, nil
+}
+
+// lintTemplate runs every lint check against a loaded template
+func lintTemplate(templatePath string, cfg Config) ([]string, error) {
+	renderPath, err := renderRoot(templatePath, cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := checkIgnorePatterns(renderPath, cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(findings, checkHookCommands(cfg)...), nil
+}
+
+// checkHookCommands reports hooks whose program is not available on PATH.
+// Commands are rendered with the variables' defaults; ones that cannot be rendered are skipped.
+func checkHookCommands(cfg Config) []string {
+	defaults := make(map[string]any, len(cfg.Variables))
+	for name, variable := range cfg.Variables {
+		if variable.Default != nil {
+			defaults[name] = variable.Default
+		}
+	}
+	data := templateData(cfg.Variables, defaults)
+
+	executor := New()
+	var findings []string
+	seen := make(map[string]bool)
+	for _, hook := range cfg.Hooks.all() {
+		missing, err := executor.missingCommand(hook, data)
+		if err != nil || missing == "" || seen[missing] {
+			continue
+		}
+		seen[missing] = true
+		findings = append(findings, fmt.Sprintf("hook requires '%s' which was not found", missing))
+	}
+	return findings
+}
+
+// checkIgnorePatterns reports ignore patterns that exclude every entry at the template root,
+// which would almost certainly produce an empty project.
+func checkIgnorePatterns(templatePath string, settings TemplateSettings) ([]string, error) {
+	entries, err := os.ReadDir(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("read template root: %w", err)
+	}
+
+	r := NewRenderer()
+	var findings []string
+	for _, pattern := range settings.IgnorePatterns {
+		single := TemplateSettings{IgnorePatterns: []string{pattern}}
+
+		matched, total := 0, 0
+		for _, entry := range entries {
+			if r.shouldSkip(entry.Name(), entry.IsDir()) {
+				continue
+			}
+			total++
+			if r.shouldIgnoreWithSettings(entry.Name(), entry.IsDir(), single) {
+				matched++
+			}
+		}
+
+		if total > 0 && matched == total {
+			findings = append(findings, fmt.Sprintf("ignore pattern %q matches every file at the template root", pattern))
+		}
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,143 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIgnorePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		patterns []string
+		want     []string
+	}{
+		{
+			name:     "catch-all pattern",
+			files:    []string{"README.md", "main.go"},
+			patterns: []string{"*"},
+			want:     []string{`ignore pattern "*" matches every file at the template root`},
+		},
+		{
+			name:     "pattern matching some files",
+			files:    []string{"README.md", "main.go", "debug.log"},
+			patterns: []string{"*.log", "*.md"},
+		},
+		{
+			name:     "config file is not counted",
+			files:    []string{KickYAML, "values.yaml"},
+			patterns: []string{"*.yaml"},
+			want:     []string{`ignore pattern "*.yaml" matches every file at the template root`},
+		},
+		{
+			name:     "empty template root",
+			patterns: []string{"*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, name := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("x"), 0644))
+			}
+
+			findings, err := checkIgnorePatterns(root, TemplateSettings{IgnorePatterns: tt.patterns})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, findings)
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name: "known keys",
+			input: `name: test
+variables:
+  project:
+    type: string
+    default: app
+template:
+  keep_permissions: true`,
+		},
+		{
+			name: "misspelled top-level key",
+			input: `name: test
+varaibles:
+  project:
+    type: string
+hooks_pre:
+  - echo hi`,
+			want: []string{`line 2: unknown key "varaibles"`, `line 5: unknown key "hooks_pre"`},
+		},
+		{
+			name: "misspelled variable key",
+			input: `name: test
+variables:
+  project:
+    type: string
+    defualt: app`,
+			want: []string{`line 5: unknown key "defualt"`},
+		},
+		{
+			name: "misspelled hook keys",
+			input: `name: test
+hooks:
+  pre_generation:
+    - echo hi
+    - command: make
+      alow_failure: true
+  post_generation:
+    - command: go mod tidy
+      timout: 5m
+      allow_failure: true`,
+			want: []string{`line 6: unknown key "alow_failure"`, `line 9: unknown key "timout"`},
+		},
+		{
+			name:  "invalid yaml is left to the parser",
+			input: "name: [",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, unknownKeys([]byte(tt.input)))
+		})
+	}
+}
+
+func TestLint_UnknownKeys(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, KickYAML), []byte("name: test\nvaraibles: {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("x"), 0644))
+
+	findings, err := Lint(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`kick.yaml line 2: unknown key "varaibles"`}, findings)
+
+	err = Generate(Options{Source: root, OutputDir: t.TempDir(), StrictConfig: true})
+	assert.EqualError(t, err, `parse config: line 2: unknown key "varaibles"`)
+	assert.NoError(t, Generate(Options{Source: root, OutputDir: t.TempDir()}), "unknown keys are ignored without StrictConfig")
+}
+
+func TestGenerate_StrictConfigHookKeys(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, KickYAML), []byte("name: test\nhooks:\n  post_generation:\n    - command: \"true\"\n      alow_failure: true\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("x"), 0644))
+
+	err := Generate(Options{Source: root, OutputDir: t.TempDir(), StrictConfig: true, SkipHooks: true})
+	assert.EqualError(t, err, `parse config: line 5: unknown key "alow_failure"`)
+
+	findings, err := Lint(root)
+	require.NoError(t, err)
+	assert.Contains(t, findings, `kick.yaml line 5: unknown key "alow_failure"`)
+}
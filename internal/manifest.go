@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest written into the output directory
+const ManifestFile = ".kick-manifest.yaml"
+
+// Manifest records the SHA-256 of every file produced by a generation run,
+// keyed by slash-separated path relative to the output directory
+type Manifest struct {
+	Files map[string]string `yaml:"files"`
+}
+
+// NewManifest creates an empty manifest
+func NewManifest() Manifest {
+	return Manifest{Files: make(map[string]string)}
+}
+
+// LoadManifest reads the manifest from dir. A missing manifest yields an empty one.
+func LoadManifest(dir string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return NewManifest(), nil
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("read manifest: %w", err)
+	}
+
+	manifest := NewManifest()
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("parse manifest: %w", err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]string)
+	}
+
+	return manifest, nil
+}
+
+// Save writes the manifest into dir
+func (m Manifest) Save(dir string) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// FileChange describes a manifest entry whose file no longer matches what kick generated
+type FileChange struct {
+	Path   string // slash-separated path relative to the output directory
+	Status string // "modified" or "missing"
+}
+
+// Verify compares the files in dir with the hashes recorded in its manifest
+// and returns the files that were modified or removed since generation.
+func Verify(dir string) ([]FileChange, error) {
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err != nil {
+		return nil, fmt.Errorf("no %s found in %s", ManifestFile, dir)
+	}
+
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(manifest.Files))
+	for path := range manifest.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var changes []FileChange
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if errors.Is(err, os.ErrNotExist) {
+			changes = append(changes, FileChange{Path: path, Status: "missing"})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		if hashContent(content) != manifest.Files[path] {
+			changes = append(changes, FileChange{Path: path, Status: "modified"})
+		}
+	}
+
+	return changes, nil
+}
+
+// hashContent returns the hex-encoded SHA-256 of content
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashFile returns the hex-encoded SHA-256 of the file at path without reading it into memory
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
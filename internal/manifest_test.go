@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	loaded, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Files, "missing manifest should load empty")
+
+	manifest := NewManifest()
+	manifest.Files["README.md"] = hashContent([]byte("hello"))
+	manifest.Files["cmd/root.go"] = hashContent([]byte("package cmd"))
+	require.NoError(t, manifest.Save(dir))
+
+	loaded, err = LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, loaded)
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("no manifest", func(t *testing.T) {
+		_, err := Verify(t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no "+ManifestFile)
+	})
+
+	t.Run("detects modified and missing files", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"README.md":   "hello",
+			"cmd/root.go": "package cmd",
+			"main.go":     "package main",
+		}
+
+		manifest := NewManifest()
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			manifest.Files[name] = hashContent([]byte(content))
+		}
+		require.NoError(t, manifest.Save(dir))
+
+		changes, err := Verify(dir)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("edited"), 0644))
+		require.NoError(t, os.Remove(filepath.Join(dir, "cmd", "root.go")))
+
+		changes, err = Verify(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []FileChange{
+			{Path: "README.md", Status: "modified"},
+			{Path: "cmd/root.go", Status: "missing"},
+		}, changes)
+	})
+}
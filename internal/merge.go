@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yarlson/tap"
+)
+
+// MergeReport sorts the files of a render into an output directory that already had
+// content by what happened to each of them. Paths are relative to the output directory.
+type MergeReport struct {
+	Created     []string // files that did not exist before
+	Overwritten []string // existing files replaced with different content
+	Identical   []string // existing files that already had the rendered content and were left alone
+	Conflicts   []string // existing files that differ from the rendered content but were not overwritten
+}
+
+// ConflictPolicy decides what happens to an existing file whose content differs from the
+// rendered content
+type ConflictPolicy string
+
+const (
+	ConflictFail      ConflictPolicy = "fail"      // write nothing and report every conflicting file
+	ConflictOverwrite ConflictPolicy = "overwrite" // replace the existing file
+	ConflictSkip      ConflictPolicy = "skip"      // keep the existing file
+	ConflictPrompt    ConflictPolicy = "prompt"    // ask for each file
+)
+
+// validConflictPolicy returns an error unless policy is empty or one of the conflict policies
+func validConflictPolicy(policy ConflictPolicy) error {
+	switch policy {
+	case "", ConflictFail, ConflictOverwrite, ConflictSkip, ConflictPrompt:
+		return nil
+	}
+	return fmt.Errorf("unknown conflict policy %q, must be one of [%s, %s, %s, %s]", policy, ConflictFail, ConflictOverwrite, ConflictSkip, ConflictPrompt)
+}
+
+// ConflictError is returned by RenderTreeWithSettings when existing files differ from the
+// rendered content and the conflict policy is to fail
+type ConflictError struct {
+	Paths []string // slash-separated paths relative to the output directory
+}
+
+func (e ConflictError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d existing files differ from the template", len(e.Paths))
+	for _, path := range e.Paths {
+		fmt.Fprintf(&b, "\n  %s", path)
+	}
+	return b.String()
+}
+
+// Empty reports whether no file was recorded
+func (m MergeReport) Empty() bool {
+	return len(m.Created)+len(m.Overwritten)+len(m.Identical)+len(m.Conflicts) == 0
+}
+
+// mergeTarget classifies a file about to be written with content of the given hash. It reports
+// true when the write is to be skipped, because the target already has that content or an
+// existing file is kept by the conflict policy.
+func (r *Renderer) mergeTarget(f fileTarget, hash string) (bool, error) {
+	existing, err := hashFile(f.targetPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		r.merge.Created = append(r.merge.Created, f.targetRel)
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("read existing file: %w", err)
+	case existing == hash:
+		r.merge.Identical = append(r.merge.Identical, f.targetRel)
+		r.stats.Unchanged++
+		r.explain(f.rel, "unchanged", "the output already has this content")
+		r.plan(f.targetRel, PlanUnchanged, "", false)
+		return true, nil
+	case existing == r.opts.Previous.Files[f.targetRel]:
+		// The file is still what the previous run generated, so replacing it loses nothing
+		r.merge.Overwritten = append(r.merge.Overwritten, f.targetRel)
+		return false, nil
+	default:
+		return r.resolveConflict(f)
+	}
+}
+
+// resolveConflict applies the conflict policy to an existing file that differs from the
+// rendered content, reporting true when the file is to be kept
+func (r *Renderer) resolveConflict(f fileTarget) (bool, error) {
+	policy := r.opts.Conflicts
+	if r.opts.DryRun && (policy == ConflictPrompt || policy == ConflictFail || policy == "") {
+		r.explain(f.rel, "conflict", "the output already has a different file")
+		r.plan(f.targetRel, PlanConflict, "differs from the template", false)
+		return true, nil
+	}
+
+	overwrite := false
+	switch policy {
+	case ConflictOverwrite:
+		overwrite = true
+	case ConflictSkip:
+	case ConflictPrompt:
+		var err error
+		if overwrite, err = r.askConflict(f.targetRel); err != nil {
+			return true, err
+		}
+	default:
+		r.conflicts = append(r.conflicts, f.targetRel)
+		r.explain(f.rel, "conflict", "the output already has a different file")
+		return true, nil
+	}
+
+	if overwrite {
+		r.merge.Overwritten = append(r.merge.Overwritten, f.targetRel)
+		return false, nil
+	}
+	r.merge.Conflicts = append(r.merge.Conflicts, f.targetRel)
+	r.explain(f.rel, "kept", "the output already has a different file")
+	r.plan(f.targetRel, PlanSkip, "kept existing file", false)
+	return true, nil
+}
+
+// Answers to a conflict prompt
+const (
+	conflictOverwrite    = "overwrite"
+	conflictKeep         = "keep"
+	conflictOverwriteAll = "overwrite-all"
+	conflictKeepAll      = "keep-all"
+)
+
+// askConflict asks whether to overwrite an existing file. An answer for all files is
+// remembered and applies to the remaining conflicts without asking again.
+func (r *Renderer) askConflict(path string) (bool, error) {
+	answer := r.conflictAnswer
+	if answer == "" {
+		selected, err := r.prompt.selectOption(path+" already exists with different content", []tap.SelectOption[string]{
+			{Value: conflictOverwrite, Label: "Overwrite"},
+			{Value: conflictKeep, Label: "Keep existing"},
+			{Value: conflictOverwriteAll, Label: "Overwrite all", Hint: "and every remaining conflict"},
+			{Value: conflictKeepAll, Label: "Keep all", Hint: "and every remaining conflict"},
+		}, nil)
+		if err != nil {
+			return false, err
+		}
+		if promptCancelled(selected == "") {
+			return false, ErrCancelled
+		}
+		answer = selected
+		if answer == conflictOverwriteAll || answer == conflictKeepAll {
+			r.conflictAnswer = answer
+		}
+	}
+	return answer == conflictOverwrite || answer == conflictOverwriteAll, nil
+}
+
+// mergeKept classifies an existing file left untouched because its rendered content did not
+// change since the previous run. Local edits to it are a conflict.
+func (r *Renderer) mergeKept(f fileTarget, hash string) {
+	if existing, err := hashFile(f.targetPath); err == nil && existing != hash {
+		r.merge.Conflicts = append(r.merge.Conflicts, f.targetRel)
+		return
+	}
+	r.merge.Identical = append(r.merge.Identical, f.targetRel)
+}
+
+// dirHasEntries reports whether dir exists and contains anything
+func dirHasEntries(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// showMergeReport prints what generating into a populated directory did to each file
+func showMergeReport(report MergeReport) {
+	if report.Empty() {
+		return
+	}
+
+	lines := []string{fmt.Sprintf("%d created, %d overwritten, %d identical, %d conflicts",
+		len(report.Created), len(report.Overwritten), len(report.Identical), len(report.Conflicts))}
+	for _, group := range []struct {
+		label string
+		files []string
+	}{
+		{"created", report.Created},
+		{"overwritten", report.Overwritten},
+		{"identical", report.Identical},
+		{"conflict", report.Conflicts},
+	} {
+		for _, file := range group.files {
+			lines = append(lines, fmt.Sprintf("%-11s  %s", group.label, file))
+		}
+	}
+
+	tap.Box(strings.Join(lines, "\n"), "Merge report", tap.BoxOptions{WidthAuto: true, Rounded: true, IncludePrefix: true})
+}
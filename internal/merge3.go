@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+// Markers around lines that the project and the template both changed, differently
+const (
+	conflictStartMarker  = "<<<<<<< current"
+	conflictMiddleMarker = "======="
+	conflictEndMarker    = ">>>>>>> template"
+)
+
+// maxDiffCells bounds the table used to diff two texts line by line. Larger differences
+// count as a single change, which merges only when the other side left those lines alone.
+const maxDiffCells = 4_000_000
+
+// lineChange replaces the lines base[baseStart:baseEnd] with side[start:end]
+type lineChange struct {
+	baseStart, baseEnd int
+	start, end         int
+	template           bool // the change was made by the template rather than the project
+}
+
+// splitLines splits text into lines that keep their line endings
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the changes that turn base into side, in order
+func diffLines(base, side []string) []lineChange {
+	prefix := 0
+	for prefix < len(base) && prefix < len(side) && base[prefix] == side[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(base)-prefix && suffix < len(side)-prefix && base[len(base)-1-suffix] == side[len(side)-1-suffix] {
+		suffix++
+	}
+	a, b := base[prefix:len(base)-suffix], side[prefix:len(side)-suffix]
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return nil
+	case len(a) == 0 || len(b) == 0 || len(a)*len(b) > maxDiffCells:
+		return []lineChange{{baseStart: prefix, baseEnd: prefix + len(a), start: prefix, end: prefix + len(b)}}
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []lineChange
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		if i < len(a) && j < len(b) && a[i] == b[j] {
+			i, j = i+1, j+1
+			continue
+		}
+		change := lineChange{baseStart: prefix + i, start: prefix + j}
+		for (i < len(a) || j < len(b)) && (i == len(a) || j == len(b) || a[i] != b[j]) {
+			if j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]) {
+				i++
+			} else {
+				j++
+			}
+		}
+		change.baseEnd, change.end = prefix+i, prefix+j
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// merge3 applies the changes the project (current) and the template each made to base.
+// Where both changed the same lines differently, both versions are kept between conflict
+// markers; the number of such conflicts is returned with the merged text.
+func merge3(base, current, template string) (string, int) {
+	baseLines, currentLines, templateLines := splitLines(base), splitLines(current), splitLines(template)
+
+	changes := diffLines(baseLines, currentLines)
+	for _, change := range diffLines(baseLines, templateLines) {
+		change.template = true
+		changes = append(changes, change)
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].baseStart < changes[j].baseStart })
+
+	var out strings.Builder
+	conflicts, pos := 0, 0
+	for k := 0; k < len(changes); {
+		// Changes that overlap or touch in base are resolved together
+		lo, hi := changes[k].baseStart, changes[k].baseEnd
+		group := []lineChange{changes[k]}
+		for k++; k < len(changes) && changes[k].baseStart <= hi; k++ {
+			group = append(group, changes[k])
+			hi = max(hi, changes[k].baseEnd)
+		}
+
+		writeLines(&out, baseLines[pos:lo])
+		ours, oursChanged := changedRegion(currentLines, group, false, lo, hi)
+		theirs, theirsChanged := changedRegion(templateLines, group, true, lo, hi)
+		switch {
+		case !theirsChanged || strings.Join(ours, "") == strings.Join(theirs, ""):
+			writeLines(&out, ours)
+		case !oursChanged:
+			writeLines(&out, theirs)
+		default:
+			conflicts++
+			out.WriteString(conflictStartMarker + "\n")
+			writeLines(&out, terminated(ours))
+			out.WriteString(conflictMiddleMarker + "\n")
+			writeLines(&out, terminated(theirs))
+			out.WriteString(conflictEndMarker + "\n")
+		}
+		pos = hi
+	}
+	writeLines(&out, baseLines[pos:])
+	return out.String(), conflicts
+}
+
+// changedRegion returns the lines one side has in place of base[lo:hi], and whether any
+// change of that side falls in the region. Outside its changes a side matches base line for line.
+func changedRegion(side []string, group []lineChange, template bool, lo, hi int) ([]string, bool) {
+	var first, last *lineChange
+	for i := range group {
+		if group[i].template == template {
+			if first == nil {
+				first = &group[i]
+			}
+			last = &group[i]
+		}
+	}
+	if first == nil {
+		return nil, false
+	}
+	return side[first.start-(first.baseStart-lo) : last.end+(hi-last.baseEnd)], true
+}
+
+// terminated makes sure lines end with a newline, so a conflict marker starts its own line
+func terminated(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	lines = append([]string{}, lines...)
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+func writeLines(b *strings.Builder, lines []string) {
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+}
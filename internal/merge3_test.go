@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	tests := []struct {
+		name          string
+		current       string
+		template      string
+		want          string
+		wantConflicts int
+	}{
+		{name: "unchanged", current: base, template: base, want: base},
+		{name: "only the project changed", current: "a\nB\nc\nd\ne\n", template: base, want: "a\nB\nc\nd\ne\n"},
+		{name: "only the template changed", current: base, template: "a\nb\nc\nD\ne\n", want: "a\nb\nc\nD\ne\n"},
+		{name: "separate changes", current: "a\nB\nc\nd\ne\n", template: "a\nb\nc\nD\ne\nf\n", want: "a\nB\nc\nD\ne\nf\n"},
+		{name: "same change on both sides", current: "a\nX\nc\nd\ne\n", template: "a\nX\nc\nd\ne\n", want: "a\nX\nc\nd\ne\n"},
+		{name: "deletion and insertion", current: "a\nc\nd\ne\n", template: "a\nb\nc\nd\ne\nf\n", want: "a\nc\nd\ne\nf\n"},
+		{
+			name:          "conflicting changes",
+			current:       "a\nB1\nc\nd\ne\n",
+			template:      "a\nB2\nc\nd\ne\n",
+			want:          "a\n<<<<<<< current\nB1\n=======\nB2\n>>>>>>> template\nc\nd\ne\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "missing final newline",
+			current:       "a\nb\nc\nd\nE",
+			template:      "a\nb\nc\nd\nF",
+			want:          "a\nb\nc\nd\n<<<<<<< current\nE\n=======\nF\n>>>>>>> template\n",
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := merge3(base, tt.current, tt.template)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantConflicts, conflicts)
+		})
+	}
+
+	t.Run("no base", func(t *testing.T) {
+		got, conflicts := merge3("", "x\n", "y\n")
+		assert.Equal(t, "<<<<<<< current\nx\n=======\ny\n>>>>>>> template\n", got)
+		assert.Equal(t, 1, conflicts)
+	})
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Overlays adjust variable defaults and choices depending on the answer to a selector
+// variable, e.g. different defaults for a dev and a prod environment
+type Overlays struct {
+	// Selector names the variable whose answer picks the overlay
+	Selector string `yaml:"selector"`
+	// Values maps a selector answer to the variables it overrides
+	Values map[string]map[string]VariableOverlay `yaml:"values"`
+}
+
+// VariableOverlay replaces parts of a variable definition while its overlay is active
+type VariableOverlay struct {
+	Default any      `yaml:"default,omitempty"`
+	Choices []string `yaml:"choices,omitempty"`
+}
+
+// apply returns variables with the overlay selected by the selector's answer applied.
+// Variables are returned unchanged when no overlay matches.
+func (o Overlays) apply(variables map[string]Variable, answer any) map[string]Variable {
+	overlay, ok := o.Values[fmt.Sprint(answer)]
+	if !ok {
+		return variables
+	}
+
+	applied := maps.Clone(variables)
+	for name, override := range overlay {
+		variable := applied[name]
+		if override.Default != nil {
+			variable.Default = override.Default
+		}
+		if len(override.Choices) > 0 {
+			variable.Choices = override.Choices
+		}
+		applied[name] = variable
+	}
+	return applied
+}
+
+// validateOverlays checks that overlays select on a declared variable and only override
+// variables that are asked after it
+func validateOverlays(cfg Config) error {
+	o := cfg.Overlays
+	if len(o.Values) == 0 {
+		return nil
+	}
+	if o.Selector == "" {
+		return fmt.Errorf("selector is required")
+	}
+	selector, ok := cfg.Variables[o.Selector]
+	if !ok {
+		return fmt.Errorf("selector %q is not a declared variable", o.Selector)
+	}
+
+	order := cfg.GetVariableOrder()
+	selectorIndex := slices.Index(order, o.Selector)
+	for value, overlay := range o.Values {
+		if len(selector.Choices) > 0 && !slices.Contains(selector.Choices, value) {
+			return fmt.Errorf("overlay %q is not a choice of %q", value, o.Selector)
+		}
+		for name, override := range overlay {
+			variable, ok := cfg.Variables[name]
+			if !ok {
+				return fmt.Errorf("overlay %q: %q is not a declared variable", value, name)
+			}
+			if slices.Index(order, name) <= selectorIndex {
+				return fmt.Errorf("overlay %q: %q must be declared after %q to be affected by it", value, name, o.Selector)
+			}
+			if len(override.Choices) > 0 && len(variable.Choices) == 0 {
+				return fmt.Errorf("overlay %q: %q has no choices to override", value, name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Planned actions recorded by the renderer for every file it visits
+const (
+	PlanCreate    = "create"
+	PlanOverwrite = "overwrite"
+	PlanUnchanged = "unchanged"
+	PlanSkip      = "skip"
+	PlanConflict  = "conflict"
+)
+
+// PlannedFile is what a render does, or in a dry run would do, with one file
+type PlannedFile struct {
+	// Path is slash-separated and relative to the output directory, or for skipped entries,
+	// which have no output path, relative to the template root
+	Path   string
+	Action string // PlanCreate, PlanOverwrite, PlanUnchanged, PlanSkip or PlanConflict
+	Reason string // why an entry is skipped or conflicts
+	Dir    bool   // a skipped directory, whose contents are left out as well
+}
+
+// plan records the action for a file
+func (r *Renderer) plan(path, action, reason string, dir bool) {
+	r.planned = append(r.planned, PlannedFile{Path: filepath.ToSlash(path), Action: action, Reason: reason, Dir: dir})
+}
+
+// planWrite records whether writing a file creates it or replaces an existing one
+func (r *Renderer) planWrite(f fileTarget) error {
+	_, err := os.Lstat(f.targetPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		r.plan(f.targetRel, PlanCreate, "", false)
+	case err != nil:
+		return fmt.Errorf("check existing file: %w", err)
+	default:
+		r.plan(f.targetRel, PlanOverwrite, "", false)
+	}
+	return nil
+}
+
+// planNode is a directory or file of the tree showPlan prints
+type planNode struct {
+	file     *PlannedFile
+	children map[string]*planNode
+}
+
+// writePlan prints the planned files as a tree below root, marking each file with its action:
+// + create, ~ overwrite, = unchanged, - skip and ! conflict
+func writePlan(w io.Writer, root string, planned []PlannedFile) {
+	tree := &planNode{children: map[string]*planNode{}}
+	for i := range planned {
+		node := tree
+		for _, name := range strings.Split(planned[i].Path, "/") {
+			child, ok := node.children[name]
+			if !ok {
+				child = &planNode{children: map[string]*planNode{}}
+				node.children[name] = child
+			}
+			node = child
+		}
+		node.file = &planned[i]
+	}
+
+	_, _ = fmt.Fprintln(w, filepath.ToSlash(root)+"/")
+	writePlanNode(w, tree, "")
+}
+
+func writePlanNode(w io.Writer, node *planNode, indent string) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	marks := map[string]string{PlanCreate: "+", PlanOverwrite: "~", PlanUnchanged: "=", PlanSkip: "-", PlanConflict: "!"}
+	for i, name := range names {
+		child := node.children[name]
+		branch, next := "├── ", "│   "
+		if i == len(names)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		line := name
+		switch {
+		case child.file != nil:
+			line = marks[child.file.Action] + " " + name
+			if child.file.Dir {
+				line += "/"
+			}
+			if child.file.Reason != "" {
+				line += " (" + child.file.Reason + ")"
+			}
+		case len(child.children) > 0:
+			line += "/"
+		}
+		_, _ = fmt.Fprintln(w, indent+branch+line)
+		writePlanNode(w, child, indent+next)
+	}
+}
+
+// showPlan prints what a dry run would have done and a count of each action
+func showPlan(root string, planned []PlannedFile) {
+	counts := map[string]int{}
+	for _, p := range planned {
+		counts[p.Action]++
+	}
+	writePlan(os.Stdout, root, planned)
+	_, _ = fmt.Fprintf(os.Stdout, "\n%d to create, %d to overwrite, %d unchanged, %d skipped; nothing was written\n",
+		counts[PlanCreate], counts[PlanOverwrite], counts[PlanUnchanged], counts[PlanSkip])
+	if counts[PlanConflict] > 0 {
+		_, _ = fmt.Fprintf(os.Stdout, "%d existing files differ from the template (!)\n", counts[PlanConflict])
+	}
+}
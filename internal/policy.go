@@ -0,0 +1,332 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// HookPolicy restricts which commands hooks may run, e.g. for shared templates in a managed
+// environment. Entries are program names matched against every command of a hook, including
+// those run through wrappers such as env or xargs and scripts given to sh -c, or regular
+// expressions written between slashes, e.g. "/rm -rf/". Deny patterns are matched against the
+// whole rendered command, allow patterns against each of its commands. A program that runs
+// commands some other way, such as find -exec or a script file, is only matched by name.
+type HookPolicy struct {
+	// Allow lists the only commands hooks may run; empty allows every command not denied
+	Allow []string `yaml:"allow,omitempty"`
+	// Deny lists commands hooks may never run. It wins over Allow.
+	Deny []string `yaml:"deny,omitempty"`
+}
+
+// validate checks that every regular expression entry compiles
+func (p HookPolicy) validate() error {
+	for _, entry := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if expr, ok := policyPattern(entry); ok {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("invalid hook policy pattern %q: %w", entry, err)
+			}
+		}
+	}
+	return nil
+}
+
+// active reports whether the policy restricts anything
+func (p HookPolicy) active() bool {
+	return len(p.Allow) > 0 || len(p.Deny) > 0
+}
+
+// check returns an error when the policy does not permit a rendered hook command. Every
+// command of its lists, pipelines and command substitutions must be permitted.
+func (p HookPolicy) check(command string) error {
+	commands := policyCommands(command)
+	refused := func(name string) error {
+		return fmt.Errorf("hook command '%s' is not allowed by policy", name)
+	}
+
+	for _, entry := range p.Deny {
+		if expr, ok := policyPattern(entry); ok {
+			if matched, _ := regexp.MatchString(expr, command); matched {
+				name := strings.TrimSpace(command)
+				if len(commands) > 0 {
+					name = commands[0].name
+				}
+				return refused(name)
+			}
+			continue
+		}
+		for _, c := range commands {
+			if c.name == entry {
+				return refused(c.name)
+			}
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	if len(commands) == 0 {
+		return refused(strings.TrimSpace(command))
+	}
+	for _, c := range commands {
+		if !harmlessBuiltins[c.name] && !slices.ContainsFunc(p.Allow, func(entry string) bool {
+			return policyMatches(entry, c.name, c.text)
+		}) {
+			return refused(c.name)
+		}
+	}
+	return nil
+}
+
+// checkScript refuses hook scripts while the policy restricts commands, as what a script
+// runs cannot be told from its command line
+func (p HookPolicy) checkScript(hook Hook) error {
+	if hook.Script != "" && p.active() {
+		return fmt.Errorf("hook script %s is not allowed by policy: the commands of scripts cannot be checked", filepath.ToSlash(hook.Script))
+	}
+	return nil
+}
+
+// checkHooks renders every hook command and checks it against the policy, so a refused hook
+// stops generation before anything runs
+func (p HookPolicy) checkHooks(hooks Hooks, data map[string]any) error {
+	if !p.active() {
+		return nil
+	}
+	for _, hook := range hooks.all() {
+		enabled, err := hookEnabled(hook, data)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			continue
+		}
+		if err := p.checkScript(hook); err != nil {
+			return err
+		}
+		command, err := New().renderCommand(hook.Command, data)
+		if err != nil {
+			return fmt.Errorf("render hook command: %w", err)
+		}
+		if err := p.check(command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// policyPattern returns the regular expression of an entry written between slashes
+func policyPattern(entry string) (string, bool) {
+	if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+		return entry[1 : len(entry)-1], true
+	}
+	return "", false
+}
+
+// policyMatches reports whether a policy entry matches a hook's program name or command
+func policyMatches(entry, name, command string) bool {
+	if expr, ok := policyPattern(entry); ok {
+		matched, _ := regexp.MatchString(expr, command)
+		return matched
+	}
+	return entry == name
+}
+
+// harmlessBuiltins are shell builtins an allow list permits without naming them, as they
+// cannot run other programs
+var harmlessBuiltins = map[string]bool{
+	"[": true, "test": true, "true": true, "false": true, "cd": true, "echo": true, "printf": true,
+	"export": true, "set": true, "unset": true, "exit": true, ":": true, "read": true,
+}
+
+// shellKeywords structure shell commands without running anything themselves
+var shellKeywords = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "fi": true, "do": true, "done": true,
+	"while": true, "until": true, "!": true, "{": true, "}": true,
+}
+
+// commandWrappers run the command that follows them, after their own options. The values
+// list the options that take the next word as their argument.
+var commandWrappers = map[string][]string{
+	"exec": nil, "command": nil, "nohup": nil,
+	"env":   {"-u", "-C"},
+	"time":  {"-f", "-o"},
+	"xargs": {"-a", "-d", "-E", "-I", "-L", "-n", "-P", "-s"},
+}
+
+// policyShells run the script given with -c, whose commands are checked as well
+var policyShells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true,
+}
+
+// policyCommand is one simple command of a rendered hook command
+type policyCommand struct {
+	name string // base name of the program, e.g. curl for /usr/bin/curl
+	text string // the command with its arguments
+}
+
+// policyCommands splits a rendered hook command into its simple commands: those separated by
+// ;, &, &&, ||, | and newlines, grouped in parentheses or braces and in command substitutions.
+// Quoted separators are kept, except that command substitutions inside double quotes still
+// start a command.
+func policyCommands(command string) []policyCommand {
+	var commands []policyCommand
+	var current strings.Builder
+	flush := func() {
+		if c, ok := parsePolicyCommand(current.String()); ok {
+			commands = append(commands, c)
+			if script, ok := shellScript(c); ok {
+				commands = append(commands, policyCommands(script)...)
+			}
+		}
+		current.Reset()
+	}
+
+	var quote rune
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			}
+			current.WriteRune(r)
+		case r == '\\' && i+1 < len(runes):
+			current.WriteRune(r)
+			current.WriteRune(runes[i+1])
+			i++
+		case quote == '"' && r == '"':
+			quote = 0
+			current.WriteRune(r)
+		case r == '`' || (r == '$' && i+1 < len(runes) && runes[i+1] == '('):
+			flush()
+			if r == '$' {
+				i++
+			}
+		case quote == '"':
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			current.WriteRune(r)
+		case r == '&' && (i > 0 && (runes[i-1] == '>' || runes[i-1] == '<') || i+1 < len(runes) && runes[i+1] == '>'):
+			// Part of a redirection such as 2>&1 or &>file
+			current.WriteRune(r)
+		case strings.ContainsRune(";&|\n()", r):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return commands
+}
+
+// parsePolicyCommand finds the program of a simple command, skipping keywords, variable
+// assignments, redirections and wrappers such as env or xargs with their options
+func parsePolicyCommand(text string) (policyCommand, bool) {
+	words := strings.Fields(text)
+	var wrapper []string // options of the innermost wrapper that take an argument
+	wrapped := false
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case shellKeywords[word]:
+			continue
+		case word == "for" || word == "case":
+			// Loop variables and patterns are not commands
+			return policyCommand{}, false
+		case strings.IndexAny(word, "<>") >= 0:
+			if strings.TrimLeft(word, "0123456789&<>") == "" {
+				i++ // the redirection target is the next word
+			}
+			continue
+		case wrapped && strings.HasPrefix(word, "-"):
+			if slices.Contains(wrapper, word) {
+				i++ // the option's argument is the next word
+			}
+			continue
+		}
+		if name, _, ok := strings.Cut(word, "="); ok && name != "" && !strings.ContainsAny(name, "/'\"") {
+			continue
+		}
+		name := filepath.Base(strings.Trim(word, `'"`))
+		if options, ok := commandWrappers[name]; ok {
+			wrapper, wrapped = options, true
+			continue
+		}
+		return policyCommand{name: name, text: strings.TrimSpace(strings.Join(words[i:], " "))}, true
+	}
+	return policyCommand{}, false
+}
+
+// shellScript returns the script a shell such as sh or bash runs with -c, e.g. the quoted
+// curl command of sh -c 'curl https://example.com'
+func shellScript(c policyCommand) (string, bool) {
+	if !policyShells[c.name] {
+		return "", false
+	}
+	words := shellWords(c.text)
+	for i := 1; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case word == "-o" || word == "+o":
+			i++ // the option name is the next word
+		case strings.HasPrefix(word, "-") && !strings.HasPrefix(word, "--") && strings.Contains(word, "c"):
+			if i+1 < len(words) {
+				return words[i+1], true
+			}
+			return "", false
+		case !strings.HasPrefix(word, "-") && !strings.HasPrefix(word, "+"):
+			// A script file or its arguments
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// shellWords splits text into words the way a shell does, removing quotes and backslashes
+func shellWords(text string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// VersionFile is the conventional name of the provenance marker written with --version-file
+const VersionFile = ".kick-version"
+
+// Provenance records which template produced a generated project, for later upgrades and audits
+type Provenance struct {
+	Template  string `yaml:"template"`
+	Version   string `yaml:"version,omitempty"`
+	Source    string `yaml:"source"`
+	Ref       string `yaml:"ref,omitempty"` // commit the template was generated from, when it is a git repository
+	Generated string `yaml:"generated"`     // RFC 3339 time of generation
+}
+
+// newProvenance describes a generation run from source, resolved to templatePath
+func newProvenance(cfg Config, source, templatePath string) Provenance {
+	if isGitLike(source) {
+		source = normalizeGitURL(source)
+	} else if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+
+	return Provenance{
+		Template:  cfg.Name,
+		Version:   cfg.Version,
+		Source:    source,
+		Ref:       headCommit(templatePath),
+		Generated: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// Save writes the provenance marker to path as YAML
+func (p Provenance) Save(path string) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal provenance: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write provenance: %w", err)
+	}
+	return nil
+}
+
+// headCommit returns the commit checked out in the git repository containing dir, or ""
+func headCommit(dir string) string {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
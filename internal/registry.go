@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegistryFile is the name of the template registry inside the kick config directory
+const RegistryFile = "registry.yaml"
+
+// registryNamePattern restricts template names so they cannot be mistaken for a path or URL
+var registryNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// RegisteredTemplate is a short name that stands for a template source
+type RegisteredTemplate struct {
+	Name   string
+	Source string
+}
+
+// registry is the on-disk form of the template registry
+type registry struct {
+	Templates map[string]string `yaml:"templates"`
+}
+
+// registryPath returns the location of the registry file
+func registryPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, RegistryFile), nil
+}
+
+// loadRegistry reads the registry; a missing file yields an empty registry
+func loadRegistry() (registry, error) {
+	path, err := registryPath()
+	if err != nil {
+		return registry{}, err
+	}
+
+	reg := registry{Templates: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return reg, nil
+	}
+	if err != nil {
+		return registry{}, fmt.Errorf("read registry: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &reg); err != nil {
+		return registry{}, fmt.Errorf("parse registry %s: %w", path, err)
+	}
+	if reg.Templates == nil {
+		reg.Templates = map[string]string{}
+	}
+	return reg, nil
+}
+
+// save writes the registry, creating the config directory when needed
+func (reg registry) save() error {
+	path, err := registryPath()
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(reg)
+	if err != nil {
+		return fmt.Errorf("encode registry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write registry: %w", err)
+	}
+	return nil
+}
+
+// ListTemplates returns the registered templates sorted by name
+func ListTemplates() ([]RegisteredTemplate, error) {
+	reg, err := loadRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]RegisteredTemplate, 0, len(reg.Templates))
+	for name, source := range reg.Templates {
+		templates = append(templates, RegisteredTemplate{Name: name, Source: source})
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// AddTemplate registers source under name, replacing an earlier entry with that name.
+// Local template paths are stored as absolute paths so the name works from any directory.
+func AddTemplate(name, source string) error {
+	if !registryNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name %q: use letters, digits, '-' and '_'", name)
+	}
+	if !isGitLike(source) {
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return fmt.Errorf("resolve template path: %w", err)
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return fmt.Errorf("template %s: %w", source, err)
+		}
+		source = abs
+	}
+
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	reg.Templates[name] = source
+	return reg.save()
+}
+
+// RemoveTemplate unregisters name
+func RemoveTemplate(name string) error {
+	reg, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	if _, ok := reg.Templates[name]; !ok {
+		return fmt.Errorf("no template named %q is registered", name)
+	}
+	delete(reg.Templates, name)
+	return reg.save()
+}
+
+// LookupTemplate returns the source registered under a template name. Anything that is
+// not a registered name, or that names an existing local path, is returned unchanged.
+func LookupTemplate(source string) (string, error) {
+	if !registryNamePattern.MatchString(source) {
+		return source, nil
+	}
+	if _, err := os.Stat(source); err == nil {
+		return source, nil
+	}
+
+	reg, err := loadRegistry()
+	if err != nil {
+		return "", err
+	}
+	if registered, ok := reg.Templates[source]; ok {
+		return registered, nil
+	}
+	return source, nil
+}
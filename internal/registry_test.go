@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	templates, err := ListTemplates()
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+
+	require.NoError(t, AddTemplate("go-service", "gh://my-org/go-service"))
+	local := t.TempDir()
+	require.NoError(t, AddTemplate("local", local))
+	require.NoError(t, AddTemplate("go-service", "gh://my-org/go-service-v2"))
+
+	templates, err = ListTemplates()
+	require.NoError(t, err)
+	assert.Equal(t, []RegisteredTemplate{
+		{Name: "go-service", Source: "gh://my-org/go-service-v2"},
+		{Name: "local", Source: local},
+	}, templates)
+
+	require.NoError(t, RemoveTemplate("local"))
+	assert.ErrorContains(t, RemoveTemplate("local"), `no template named "local"`)
+
+	templates, err = ListTemplates()
+	require.NoError(t, err)
+	assert.Len(t, templates, 1)
+}
+
+func TestAddTemplate_Invalid(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		source  string
+		wantErr string
+	}{
+		{name: "path-like name", tmpl: "my/template", source: "gh://a/b", wantErr: "invalid template name"},
+		{name: "dotted name", tmpl: ".hidden", source: "gh://a/b", wantErr: "invalid template name"},
+		{name: "missing local path", tmpl: "missing", source: filepath.Join(t.TempDir(), "nope"), wantErr: "no such file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, AddTemplate(tt.tmpl, tt.source), tt.wantErr)
+		})
+	}
+}
+
+func TestLookupTemplate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	require.NoError(t, AddTemplate("go-service", "gh://my-org/go-service"))
+	require.NoError(t, AddTemplate("shadowed", "gh://my-org/shadowed"))
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "shadowed"), 0o755))
+
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "go-service", want: "gh://my-org/go-service"},
+		{source: "shadowed", want: "shadowed"},
+		{source: "unknown", want: "unknown"},
+		{source: "./go-service", want: "./go-service"},
+		{source: "gh://other/repo", want: "gh://other/repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := LookupTemplate(tt.source)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,10 +2,18 @@ package internal
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"unicode"
@@ -13,19 +21,180 @@ import (
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
 )
 
+// RawSuffix marks a template file whose content is copied verbatim; it is dropped from the output name
+const RawSuffix = ".kickraw"
+
+// DefaultMaxFileSize is the size above which template files are copied verbatim instead of rendered.
+const DefaultMaxFileSize = 64 << 20
+
 // Renderer handles template rendering operations.
 type Renderer struct {
 	// funcMap is cached to avoid recreating it for each template
 	funcMap template.FuncMap
+	opts    RenderOptions
+
+	// manifest records the hash of every file produced by RenderTreeWithSettings
+	manifest Manifest
+	stats    RenderStats
+	failures RenderFailures
+
+	// oversized lists the files copied verbatim because they exceed the size limit
+	oversized []string
+	// nonPortable describes rendered names that are invalid on some operating system
+	nonPortable []string
+	// pending holds the writes held back until every file rendered, in Atomic mode
+	pending []func() error
+	// explanations records the outcome of every entry visited, in walk order
+	explanations []Explanation
+	// merge sorts the files written into a populated output directory, in Merge mode
+	merge MergeReport
+	// planned records the action for every file, which a dry run prints instead of writing
+	planned []PlannedFile
+	// conflicts lists the existing files that differ from the rendered content, under ConflictFail
+	conflicts []string
+	// conflictAnswer is the answer given for every remaining conflict, under ConflictPrompt
+	conflictAnswer string
+	// prompt asks about conflicts, under ConflictPrompt
+	prompt prompter
+	// delims holds the template's action delimiters; empty uses {{ and }}
+	delims []string
+	// following holds the resolved directories of the symlinks being followed, to detect cycles
+	following []string
+}
+
+// RenderOptions controls per-run rendering behavior that is not part of the template config.
+type RenderOptions struct {
+	// Incremental leaves files alone whose rendered content matches Previous.
+	Incremental bool
+	// Previous holds the manifest of an earlier run into the same output directory.
+	Previous Manifest
+	// KeepGoing renders the remaining files after a file fails and reports every failure at the end.
+	KeepGoing bool
+	// Only limits tagged files to those with one of these tags; untagged files are always rendered.
+	Only []string
+	// Skip drops files with any of these tags.
+	Skip []string
+	// Header is the rendered banner prepended as a comment to text files whose type supports
+	// comments, unless they match the template's header skip patterns. Empty disables it.
+	Header string
+	// StrictNames rejects file and directory names that render to text containing a path
+	// separator, so answers cannot add directories to the output, and names that are invalid
+	// on some operating system instead of only reporting them.
+	StrictNames bool
+	// Merge compares every file with what the output directory already holds, leaves files
+	// with identical content alone and records the outcome of each in a MergeReport.
+	Merge bool
+	// Conflicts decides, in Merge mode, what happens to existing files that differ from the
+	// rendered content, unless they still match Previous. Empty fails like ConflictFail;
+	// combine it with Atomic so that nothing is written when any file conflicts.
+	Conflicts ConflictPolicy
+	// Atomic renders every file before writing any, so a template error leaves the output
+	// directory untouched.
+	Atomic bool
+	// Files limits rendering to these template files, given as slash-separated paths relative
+	// to the template root. Nil renders every file.
+	Files []string
+	// MaxFileSize is the size in bytes above which files are copied verbatim instead of being
+	// read into memory and rendered. Zero uses DefaultMaxFileSize.
+	MaxFileSize int64
+	// DryRun renders every file but writes nothing; Plan reports what would have been written.
+	DryRun bool
+}
+
+// RenderStats counts the outcome of the entries visited by RenderTreeWithSettings.
+type RenderStats struct {
+	Changed   int // files written
+	Unchanged int // files left untouched because their content matches the previous manifest
+	Skipped   int // entries dropped by skip rules, ignore patterns or empty rendered names
+}
+
+// RenderFailure records a template entry that could not be rendered.
+type RenderFailure struct {
+	Path string // path relative to the template root
+	Err  error
+}
+
+// RenderFailures is returned by RenderTreeWithSettings in KeepGoing mode when one or more files failed.
+type RenderFailures []RenderFailure
+
+func (f RenderFailures) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d files failed to render", len(f))
+	for _, failure := range f {
+		fmt.Fprintf(&b, "\n  %s: %v", failure.Path, failure.Err)
+	}
+	return b.String()
 }
 
 // New creates a new template renderer.
 func NewRenderer() *Renderer {
+	return NewRendererWithOptions(RenderOptions{})
+}
+
+// NewRendererWithOptions creates a new template renderer with per-run options.
+func NewRendererWithOptions(opts RenderOptions) *Renderer {
 	return &Renderer{
-		funcMap: newTemplateFuncs(),
+		funcMap:  newTemplateFuncs(),
+		opts:     opts,
+		manifest: NewManifest(),
+	}
+}
+
+// Manifest returns the hashes of the files produced by the last render.
+func (r *Renderer) Manifest() Manifest {
+	return r.manifest
+}
+
+// Stats returns the outcome counts of the last render.
+func (r *Renderer) Stats() RenderStats {
+	return r.stats
+}
+
+// Oversized returns the template files copied verbatim because they exceed MaxFileSize.
+func (r *Renderer) Oversized() []string {
+	return r.oversized
+}
+
+// NonPortable describes the rendered file and directory names that some operating system, usually
+// Windows, cannot create. With StrictNames they fail the render instead.
+func (r *Renderer) NonPortable() []string {
+	return r.nonPortable
+}
+
+// MergeReport returns what the last render in Merge mode did to each file.
+func (r *Renderer) MergeReport() MergeReport {
+	return r.merge
+}
+
+// Plan returns the action taken, or in DryRun mode planned, for every file of the last render.
+func (r *Renderer) Plan() []PlannedFile {
+	return r.planned
+}
+
+// Explanations returns the outcome of every template entry visited by the last render and the
+// rule that decided it.
+func (r *Renderer) Explanations() []Explanation {
+	return r.explanations
+}
+
+// maxFileSize returns the effective size limit for rendered files.
+func (r *Renderer) maxFileSize() int64 {
+	if r.opts.MaxFileSize > 0 {
+		return r.opts.MaxFileSize
 	}
+	return DefaultMaxFileSize
+}
+
+// fileTarget describes a template file and where it renders to.
+type fileTarget struct {
+	srcPath    string // source file path
+	rel        string // source path relative to the template root
+	targetPath string // output file path
+	targetRel  string // output path relative to the output root
+	verbatim   string // why the file is copied without rendering; empty when it is rendered
 }
 
 // RenderTree walks the source template directory and renders all files to the output directory.
@@ -57,7 +226,7 @@ func (r *Renderer) RenderTree(srcRoot, outRoot string, data map[string]any) erro
 		}
 
 		// Render each path segment
-		targetRel, err := r.renderPath(rel, data)
+		targetRel, err := r.renderPath(rel, data, TemplateSettings{})
 		if err != nil {
 			return fmt.Errorf("render path %q: %w", rel, err)
 		}
@@ -68,6 +237,9 @@ func (r *Renderer) RenderTree(srcRoot, outRoot string, data map[string]any) erro
 			}
 			return nil
 		}
+		if err := checkWithinRoot(outRoot, targetRel); err != nil {
+			return err
+		}
 		targetPath := filepath.Join(outRoot, targetRel)
 
 		if d.IsDir() {
@@ -82,72 +254,176 @@ func (r *Renderer) RenderTree(srcRoot, outRoot string, data map[string]any) erro
 // RenderTreeWithSettings walks the source template directory and renders all files to the output directory
 // using the provided template settings.
 func (r *Renderer) RenderTreeWithSettings(srcRoot, outRoot string, data map[string]any, settings TemplateSettings) error {
+	r.manifest = NewManifest()
+	r.stats = RenderStats{}
+	r.failures = nil
+	r.nonPortable = nil
+	r.pending = nil
+	r.explanations = nil
+	r.merge = MergeReport{}
+	r.planned = nil
+	r.conflicts = nil
+	r.conflictAnswer = ""
+	r.delims = settings.Delimiters
+	r.following = nil
+
 	// Make sure output exists
-	if err := os.MkdirAll(outRoot, 0o755); err != nil {
+	if err := r.output(func() error { return os.MkdirAll(outRoot, 0o755) }); err != nil {
 		return err
 	}
 
-	return filepath.WalkDir(srcRoot, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	// visit handles one template entry; rel is its path relative to the template root, which
+	// differs from its location on disk inside a followed directory symlink
+	var visit func(path, rel string, d fs.DirEntry) error
+	visit = func(path, rel string, d fs.DirEntry) error {
+		// Skip version control and config files
+		if r.shouldSkip(filepath.Base(path), d.IsDir()) {
+			return r.skip(rel, d, fmt.Sprintf("%s is never rendered", filepath.Base(path)))
+		}
+		if d.IsDir() && rel == settings.hooksDir {
+			return r.skip(rel, d, "holds the hook scripts")
 		}
 
-		rel, err := filepath.Rel(srcRoot, path)
-		if err != nil {
-			return fmt.Errorf("compute relative path: %w", err)
+		// Check ignore patterns
+		if pattern, ok := r.ignorePattern(rel, d.IsDir(), settings); ok {
+			return r.skip(rel, d, fmt.Sprintf("ignore pattern %q", pattern))
 		}
-		if rel == "." {
-			return nil
+
+		// Check tag selection
+		if reason := r.tagExclusion(rel, settings); reason != "" {
+			return r.skip(rel, d, reason)
 		}
 
-		// Skip version control and config files
-		if r.shouldSkip(filepath.Base(path), d.IsDir()) {
-			if d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
+		// Check file conditions
+		reason, err := r.conditionExclusion(rel, data, settings)
+		if err != nil {
+			return r.fail(rel, d, err)
+		}
+		if reason != "" {
+			return r.skip(rel, d, reason)
 		}
 
-		// Check ignore patterns
-		if r.shouldIgnoreWithSettings(rel, d.IsDir(), settings) {
-			if d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
+		if r.opts.Files != nil && !d.IsDir() && !slices.Contains(r.opts.Files, filepath.ToSlash(rel)) {
+			return r.skip(rel, d, "not among the files to render")
 		}
 
 		// Render each path segment
-		targetRel, err := r.renderPath(rel, data)
+		targetRel, err := r.renderPath(rel, data, settings)
 		if err != nil {
-			return fmt.Errorf("render path %q: %w", rel, err)
+			return r.fail(rel, d, fmt.Errorf("render path %q: %w", rel, err))
+		}
+		var raw string
+		if !d.IsDir() {
+			raw = rawReason(rel, settings)
+			targetRel = strings.TrimSuffix(targetRel, RawSuffix)
 		}
 		// Skip empty results (if a segment renders to empty, drop it)
 		if targetRel == "" {
-			if d.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
+			return r.skip(rel, d, "name renders empty")
+		}
+		if err := checkWithinRoot(outRoot, targetRel); err != nil {
+			return r.fail(rel, d, err)
 		}
 		targetPath := filepath.Join(outRoot, targetRel)
 
 		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0o755)
+			r.explain(rel, "created", "")
+			return r.output(func() error { return os.MkdirAll(targetPath, 0o755) })
+		}
+
+		f := fileTarget{
+			srcPath:    path,
+			rel:        rel,
+			targetPath: targetPath,
+			targetRel:  filepath.ToSlash(targetRel),
+			verbatim:   raw,
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			if settings.Symlinks != SymlinksFollow {
+				if err := r.recreateLink(srcRoot, f, data, settings); err != nil {
+					return r.fail(rel, d, err)
+				}
+				return nil
+			}
+			resolved, isDir, err := r.resolveLink(srcRoot, path)
+			if err != nil {
+				return r.fail(rel, d, err)
+			}
+			if isDir {
+				r.explain(rel, "created", "followed symlink")
+				if err := r.output(func() error { return os.MkdirAll(targetPath, 0o755) }); err != nil {
+					return err
+				}
+				return r.followDir(resolved, rel, visit)
+			}
+			// A followed file symlink is rendered like the file it points to
 		}
 
 		// Process file with settings
-		return r.processFileWithSettings(path, targetPath, data, settings)
+		if err := r.processFileWithSettings(f, data, settings); err != nil {
+			return r.fail(rel, d, err)
+		}
+		return nil
+	}
+
+	err := filepath.WalkDir(srcRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcRoot, path)
+		if err != nil {
+			return fmt.Errorf("compute relative path: %w", err)
+		}
+		if rel == "." {
+			return nil
+		}
+		return visit(path, rel, d)
 	})
+	if err != nil {
+		return err
+	}
+	if len(r.failures) > 0 {
+		return r.failures
+	}
+	if len(r.conflicts) > 0 {
+		return ConflictError{Paths: r.conflicts}
+	}
+
+	// Every file rendered, so the held back writes can go ahead
+	for _, write := range r.pending {
+		if err := write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// output performs a write to the output directory, or holds it back in Atomic mode
+func (r *Renderer) output(write func() error) error {
+	if r.opts.DryRun {
+		return nil
+	}
+	if r.opts.Atomic {
+		r.pending = append(r.pending, write)
+		return nil
+	}
+	return write()
 }
 
-func (r *Renderer) renderPath(rel string, data map[string]any) (string, error) {
+func (r *Renderer) renderPath(rel string, data map[string]any, settings TemplateSettings) (string, error) {
 	segs := strings.Split(rel, string(os.PathSeparator))
 	outSegs := make([]string, 0, len(segs))
-	for _, s := range segs {
+	for i, s := range segs {
 		trim := strings.TrimSpace(s)
 		if trim == "" {
 			continue
 		}
-		rendered, err := r.renderString(trim, data)
+		tmpl, err := prepareTemplate(trim, settings)
+		if err != nil {
+			return "", err
+		}
+		rendered, err := r.renderString(tmpl, data)
 		if err != nil {
 			return "", err
 		}
@@ -156,11 +432,111 @@ func (r *Renderer) renderPath(rel string, data map[string]any) (string, error) {
 			// Skip empty-rendered segments
 			continue
 		}
+		if r.opts.StrictNames && strings.ContainsAny(rendered, `/\`) {
+			return "", fmt.Errorf("%q renders to %q, which contains a path separator", trim, rendered)
+		}
+		// Parent directories were checked when the walk visited them
+		if i == len(segs)-1 {
+			if err := r.checkPortable(trim, rendered); err != nil {
+				return "", err
+			}
+		}
 		outSegs = append(outSegs, rendered)
 	}
 	return filepath.Join(outSegs...), nil
 }
 
+// checkWithinRoot returns an error when a rendered path would land outside outRoot,
+// e.g. because a variable value contains "../".
+func checkWithinRoot(outRoot, targetRel string) error {
+	root, err := filepath.Abs(outRoot)
+	if err != nil {
+		return fmt.Errorf("resolve output directory: %w", err)
+	}
+	target, err := filepath.Abs(filepath.Join(outRoot, targetRel))
+	if err != nil {
+		return fmt.Errorf("resolve path %q: %w", targetRel, err)
+	}
+
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("rendered path %q escapes the output directory", targetRel)
+	}
+	return nil
+}
+
+// checkPortable records a rendered name that is invalid on some operating system, or
+// rejects it with StrictNames
+func (r *Renderer) checkPortable(tmpl, rendered string) error {
+	for _, name := range strings.Split(rendered, "/") {
+		err := portableName(name)
+		if err == nil {
+			continue
+		}
+		if r.opts.StrictNames {
+			return fmt.Errorf("%q renders to %q: %w", tmpl, rendered, err)
+		}
+		r.nonPortable = append(r.nonPortable, fmt.Sprintf("%q renders to %q: %v", tmpl, rendered, err))
+	}
+	return nil
+}
+
+// windowsReserved lists device names Windows refuses as file names, with or without an extension
+var windowsReserved = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// portableName returns an error when name cannot be used as a file name on a common
+// operating system. Windows is the most restrictive, so its rules apply.
+func portableName(name string) error {
+	if name == "." || name == ".." {
+		return nil // handled by checkWithinRoot
+	}
+
+	stem, _, _ := strings.Cut(name, ".")
+	if slices.Contains(windowsReserved, strings.ToUpper(strings.TrimRight(stem, " "))) {
+		return fmt.Errorf("%s is a reserved name on Windows", stem)
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("names ending in a dot or space are invalid on Windows")
+	}
+	for _, c := range name {
+		if c < 0x20 || strings.ContainsRune(`<>:"|?*\`, c) {
+			return fmt.Errorf("%q is invalid in names on Windows", c)
+		}
+	}
+	return nil
+}
+
+// skip counts a dropped entry, records the rule that dropped it and tells WalkDir whether
+// to descend into it.
+func (r *Renderer) skip(rel string, d fs.DirEntry, reason string) error {
+	r.stats.Skipped++
+	r.explain(rel, "skipped", reason)
+	if !r.shouldSkip(filepath.Base(rel), d.IsDir()) {
+		r.plan(rel, PlanSkip, reason, d.IsDir())
+	}
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
+// fail aborts the walk with err, or in KeepGoing mode records it and moves on to the next entry.
+func (r *Renderer) fail(rel string, d fs.DirEntry, err error) error {
+	r.explain(rel, "failed", err.Error())
+	if !r.opts.KeepGoing {
+		return err
+	}
+	r.failures = append(r.failures, RenderFailure{Path: filepath.ToSlash(rel), Err: err})
+	if d.IsDir() {
+		return filepath.SkipDir
+	}
+	return nil
+}
+
 // shouldSkip determines if a file or directory should be skipped during rendering.
 func (r *Renderer) shouldSkip(basename string, _ bool) bool {
 	return basename == ".git" || basename == KickYAML
@@ -192,7 +568,7 @@ func (r *Renderer) processFile(srcPath, targetPath string, data map[string]any)
 	}
 
 	// Render text file
-	rendered, err := r.renderBytes(content, data)
+	rendered, err := r.renderBytes(stripBOM(content), data)
 	if err != nil {
 		return fmt.Errorf("render template: %w", err)
 	}
@@ -200,8 +576,29 @@ func (r *Renderer) processFile(srcPath, targetPath string, data map[string]any)
 	return os.WriteFile(targetPath, rendered, mode.Perm())
 }
 
+// RenderString renders a one-off template string with the same functions available to template files.
+func RenderString(tmpl string, data map[string]any) (string, error) {
+	return NewRenderer().renderString(tmpl, data)
+}
+
+// leftDelim and rightDelim return the action delimiters of the template being rendered
+func (r *Renderer) leftDelim() string {
+	if len(r.delims) == 2 {
+		return r.delims[0]
+	}
+	return "{{"
+}
+
+func (r *Renderer) rightDelim() string {
+	if len(r.delims) == 2 {
+		return r.delims[1]
+	}
+	return "}}"
+}
+
 func (r *Renderer) renderString(tmpl string, data map[string]any) (string, error) {
 	t, err := template.New("str").
+		Delims(r.leftDelim(), r.rightDelim()).
 		Funcs(r.funcMap).
 		Option("missingkey=error").
 		Parse(tmpl)
@@ -217,10 +614,16 @@ func (r *Renderer) renderString(tmpl string, data map[string]any) (string, error
 }
 
 func (r *Renderer) renderBytes(b []byte, data map[string]any) ([]byte, error) {
+	src, err := protectRawRegions(string(b), r.leftDelim(), r.rightDelim())
+	if err != nil {
+		return nil, err
+	}
+
 	t, err := template.New("file").
+		Delims(r.leftDelim(), r.rightDelim()).
 		Funcs(r.funcMap).
 		Option("missingkey=error").
-		Parse(string(b))
+		Parse(src)
 	if err != nil {
 		return nil, fmt.Errorf("parse template: %w", err)
 	}
@@ -232,6 +635,65 @@ func (r *Renderer) renderBytes(b []byte, data map[string]any) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// rawMarkerPattern matches the comment of a {{/* kick:raw */}} or {{/* kick:endraw */}} marker.
+// Templates can change the delimiters, so findRawMarker checks them around each match.
+var rawMarkerPattern = regexp.MustCompile(`/\*\s*kick:(raw|endraw)\s*\*/`)
+
+// findRawMarker returns the start and end of the first kick:raw or kick:endraw marker (as
+// given by name) in src written with the given delimiters, or nil when there is none
+func findRawMarker(src, left, right, name string) []int {
+	for _, m := range rawMarkerPattern.FindAllStringSubmatchIndex(src, -1) {
+		if src[m[2]:m[3]] == name && strings.HasSuffix(src[:m[0]], left) && strings.HasPrefix(src[m[1]:], right) {
+			return []int{m[0] - len(left), m[1] + len(right)}
+		}
+	}
+	return nil
+}
+
+// protectRawRegions replaces each {{/* kick:raw */}} ... {{/* kick:endraw */}} region, written
+// with the left and right delimiters, with a string literal action, so its content is emitted
+// exactly as written.
+func protectRawRegions(src, left, right string) (string, error) {
+	var b strings.Builder
+	for {
+		start := findRawMarker(src, left, right, "raw")
+		if start == nil {
+			break
+		}
+		rest := src[start[1]:]
+		end := findRawMarker(rest, left, right, "endraw")
+		if end == nil {
+			return "", fmt.Errorf("kick:raw region is not closed with %s/* kick:endraw */%s", left, right)
+		}
+
+		b.WriteString(src[:start[0]])
+		if raw := rest[:end[0]]; raw != "" {
+			b.WriteString(left + strconv.Quote(raw) + right)
+		}
+		src = rest[end[1]:]
+	}
+	if findRawMarker(src, left, right, "endraw") != nil {
+		return "", fmt.Errorf("kick:endraw without a matching %s/* kick:raw */%s", left, right)
+	}
+
+	b.WriteString(src)
+	return b.String(), nil
+}
+
+// ensureFinalNewline trims trailing line breaks so content ends with exactly one,
+// keeping CRLF when the content uses it. Empty content is left empty.
+func ensureFinalNewline(content []byte) []byte {
+	if len(content) == 0 {
+		return content
+	}
+
+	newline := []byte("\n")
+	if bytes.Contains(content, []byte("\r\n")) {
+		newline = []byte("\r\n")
+	}
+	return append(bytes.TrimRight(content, "\r\n"), newline...)
+}
+
 // isBinary detects if data appears to be binary using heuristics.
 // It checks for null bytes and counts non-printable characters.
 func isBinary(data []byte) bool {
@@ -270,16 +732,263 @@ func isBinary(data []byte) bool {
 func newTemplateFuncs() template.FuncMap {
 	caser := cases.Title(language.English)
 	return template.FuncMap{
-		"upper":   func(s string) string { return cases.Upper(language.English).String(s) },
-		"lower":   func(s string) string { return cases.Lower(language.English).String(s) },
-		"title":   func(s string) string { return caser.String(s) },
-		"trim":    strings.TrimSpace,
-		"snake":   toSnakeCase,
-		"kebab":   toKebabCase,
-		"camel":   toCamelCase,
-		"pascal":  toPascalCase,
-		"replace": strings.ReplaceAll,
+		"upper":    stringFunc(cases.Upper(language.English).String),
+		"lower":    stringFunc(cases.Lower(language.English).String),
+		"title":    stringFunc(caser.String),
+		"trim":     stringFunc(strings.TrimSpace),
+		"snake":    stringFunc(toSnakeCase),
+		"kebab":    stringFunc(toKebabCase),
+		"camel":    stringFunc(toCamelCase),
+		"pascal":   stringFunc(toPascalCase),
+		"replace":  stringFunc3(strings.ReplaceAll),
+		"goSlice":  goSlice,
+		"yamlList": yamlList,
+		"has":      has,
+		"wrap":     wrapText,
+		"wrapWith": wrapWithPrefix,
+
+		"toYaml":       toYAML,
+		"toJson":       toJSON,
+		"toPrettyJson": toPrettyJSON,
+		"indent":       indent,
+		"nindent":      nindent,
+
+		"pathJoin": stringFuncN(pathJoin),
+		"basePath": stringFunc(basePath),
+
+		"trimAll":    stringFunc2(trimAll),
+		"trimPrefix": stringFunc2(trimPrefix),
+		"trimSuffix": stringFunc2(trimSuffix),
+
+		"pluralizeIf": pluralizeIf,
+	}
+}
+
+// String functions
+
+// trimAll removes every leading and trailing character found in cutset. The string comes
+// last so it can be piped, e.g. {{ .path | trimAll "/" }}.
+func trimAll(cutset, s string) string {
+	return strings.Trim(s, cutset)
+}
+
+// trimPrefix removes prefix from the start of s once, if present.
+func trimPrefix(prefix, s string) string {
+	return strings.TrimPrefix(s, prefix)
+}
+
+// trimSuffix removes suffix from the end of s once, if present.
+func trimSuffix(suffix, s string) string {
+	return strings.TrimSuffix(s, suffix)
+}
+
+// Text layout functions
+
+// wrapText word-wraps s so lines fit within width columns.
+func wrapText(width int, s string) string {
+	return wrapWithPrefix(width, "", s)
+}
+
+// wrapWithPrefix word-wraps s, starting every line with prefix (e.g. "// ").
+// The width includes the prefix. Existing line breaks are kept, and words longer
+// than the width are placed on a line of their own rather than split.
+func wrapWithPrefix(width int, prefix, s string) string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, strings.TrimRight(prefix, " \t"))
+			continue
+		}
+
+		line := prefix + words[0]
+		for _, word := range words[1:] {
+			if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+				lines = append(lines, line)
+				line = prefix + word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
 	}
+	return strings.Join(lines, "\n")
+}
+
+// List formatting functions
+
+// goSlice renders a list as a Go string slice literal, e.g. []string{"a", "b"}.
+func goSlice(list any) (string, error) {
+	items, err := toStringSlice(list)
+	if err != nil {
+		return "", err
+	}
+
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = strconv.Quote(item)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}", nil
+}
+
+// yamlList renders a list as a YAML block sequence, quoting values where YAML requires it.
+// An empty list renders as the flow sequence [].
+func yamlList(list any) (string, error) {
+	items, err := toStringSlice(list)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := yaml.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("marshal yaml: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// has reports whether collection holds item, like Python's in operator: an element of a list
+// or a key of a map, compared as text, or a substring of a string. Nil holds nothing.
+func has(item, collection any) (bool, error) {
+	if s, ok := collection.(string); ok {
+		return strings.Contains(s, fmt.Sprint(item)), nil
+	}
+	if collection == nil {
+		return false, nil
+	}
+
+	v := reflect.ValueOf(collection)
+	if v.Kind() == reflect.Map {
+		for _, key := range v.MapKeys() {
+			if fmt.Sprint(key.Interface()) == fmt.Sprint(item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	items, err := toStringSlice(collection)
+	if err != nil {
+		return false, fmt.Errorf("expected a list, map or string, got %T", collection)
+	}
+	return slices.Contains(items, fmt.Sprint(item)), nil
+}
+
+// Structured output functions
+
+// toYAML marshals any value as a YAML document indented by two spaces, without the trailing newline.
+func toYAML(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", fmt.Errorf("marshal yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("marshal yaml: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// toJSON marshals any value as compact JSON.
+func toJSON(v any) (string, error) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("marshal json: %w", err)
+	}
+	return string(out), nil
+}
+
+// toPrettyJSON marshals any value as JSON indented by two spaces.
+func toPrettyJSON(v any) (string, error) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal json: %w", err)
+	}
+	return string(out), nil
+}
+
+// indent prefixes every non-empty line of s with n spaces.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// nindent is indent preceded by a newline, for blocks placed after a key, e.g. `spec: {{ .spec | toYaml | nindent 2 }}`.
+func nindent(n int, s string) string {
+	return "\n" + indent(n, s)
+}
+
+// pluralizeIf returns singular when count is exactly one and plural otherwise, e.g.
+// {{ pluralizeIf .replicas "replica" "replicas" }}. Number answers arrive as float64, and a
+// count given as a string is parsed, so the function works with any source of answers.
+func pluralizeIf(count any, singular, plural string) (string, error) {
+	var n float64
+	switch c := count.(type) {
+	case int:
+		n = float64(c)
+	case int64:
+		n = float64(c)
+	case float64:
+		n = c
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(c), 64)
+		if err != nil {
+			return "", fmt.Errorf("pluralizeIf: count %q is not a number", c)
+		}
+		n = parsed
+	default:
+		return "", fmt.Errorf("pluralizeIf: count must be a number, got %T", count)
+	}
+	if n == 1 {
+		return singular, nil
+	}
+	return plural, nil
+}
+
+// Path functions
+
+// pathJoin joins segments into a slash-separated path such as a Go import path,
+// skipping empty segments and dropping trailing slashes on every platform.
+func pathJoin(segments ...string) string {
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(segment, `\`, "/")
+	}
+	return path.Join(segments...)
+}
+
+// basePath returns the last element of a slash-separated path, e.g. "app" for "github.com/acme/app".
+func basePath(p string) string {
+	p = strings.TrimRight(strings.ReplaceAll(p, `\`, "/"), "/")
+	if p == "" {
+		return ""
+	}
+	return path.Base(p)
+}
+
+// toStringSlice converts any slice or array to its elements' string forms.
+func toStringSlice(list any) ([]string, error) {
+	if list == nil {
+		return []string{}, nil
+	}
+	if items, ok := list.([]string); ok {
+		return items, nil
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list, got %T", list)
+	}
+
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return items, nil
 }
 
 // Case conversion functions
@@ -362,46 +1071,141 @@ func toLowerRune(r rune) rune { return unicode.ToLower(r) }
 
 // shouldIgnoreWithSettings checks if a file should be ignored based on template settings.
 func (r *Renderer) shouldIgnoreWithSettings(relPath string, isDir bool, settings TemplateSettings) bool {
+	_, ok := r.ignorePattern(relPath, isDir, settings)
+	return ok
+}
+
+// ignorePattern returns the first ignore pattern of the template settings that matches a path.
+func (r *Renderer) ignorePattern(relPath string, isDir bool, settings TemplateSettings) (string, bool) {
 	basename := filepath.Base(relPath)
 
 	for _, pattern := range settings.IgnorePatterns {
-		// Check if pattern matches the basename
-		if matched, _ := filepath.Match(pattern, basename); matched {
-			return true
-		}
-		// Check if pattern matches the full relative path
-		if matched, _ := filepath.Match(pattern, relPath); matched {
-			return true
+		if matchesPattern(pattern, relPath) {
+			return pattern, true
 		}
 		// For directories, also check if the pattern matches the directory name exactly
 		if isDir && pattern == basename {
-			return true
+			return pattern, true
 		}
 	}
 
+	return "", false
+}
+
+// tagExclusion returns why --only or --skip drops an entry based on the tags its path matches,
+// or "" when the entry is kept
+func (r *Renderer) tagExclusion(relPath string, settings TemplateSettings) string {
+	if len(r.opts.Only) == 0 && len(r.opts.Skip) == 0 {
+		return ""
+	}
+
+	var tags []string
+	for tag, patterns := range settings.Tags {
+		for _, pattern := range patterns {
+			if matchesPattern(pattern, relPath) {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		if slices.Contains(r.opts.Skip, tag) {
+			return fmt.Sprintf("tag %q is skipped", tag)
+		}
+	}
+	if len(r.opts.Only) == 0 {
+		return ""
+	}
+	for _, tag := range tags {
+		if slices.Contains(r.opts.Only, tag) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("tagged %s, none of them selected", strings.Join(tags, ", "))
+}
+
+// conditionExclusion returns why a files condition drops an entry, or "" when every condition
+// whose pattern matches it holds
+func (r *Renderer) conditionExclusion(relPath string, data map[string]any, settings TemplateSettings) (string, error) {
+	for _, file := range settings.Files {
+		if !matchesPattern(file.Pattern, relPath) {
+			continue
+		}
+		// Conditions live in kick.yaml, so they keep the default delimiters
+		rendered, err := RenderString(file.When, data)
+		if err != nil {
+			return "", fmt.Errorf("evaluate condition for %q: %w", file.Pattern, err)
+		}
+		if !asBool(rendered) {
+			return fmt.Sprintf("condition %s is false", file.When), nil
+		}
+	}
+	return "", nil
+}
+
+// rawReason returns why a template file is copied without template parsing: its raw suffix or
+// the raw pattern matching it or one of its directories. It returns "" for files to render.
+func rawReason(relPath string, settings TemplateSettings) string {
+	if strings.HasSuffix(relPath, RawSuffix) {
+		return RawSuffix + " suffix"
+	}
+	for path := relPath; path != "."; path = filepath.Dir(path) {
+		for _, pattern := range settings.RawPatterns {
+			if matchesPattern(pattern, path) {
+				return fmt.Sprintf("raw pattern %q", pattern)
+			}
+		}
+	}
+	return ""
+}
+
+// executable reports whether an output path matches one of the template's executable patterns.
+func executable(targetRel string, settings TemplateSettings) bool {
+	for _, pattern := range settings.Executable {
+		if matchesPattern(pattern, targetRel) {
+			return true
+		}
+	}
 	return false
 }
 
+// ensureExecutable applies an executable mode to a written file. Writing keeps the mode of a
+// file that already existed, so the bits are set explicitly.
+func ensureExecutable(path string, mode os.FileMode) error {
+	if mode&0o111 == 0 {
+		return nil
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("make executable: %w", err)
+	}
+	return nil
+}
+
+// matchesPattern reports whether a glob pattern matches either the basename or the full relative path.
+func matchesPattern(pattern, relPath string) bool {
+	// Check if pattern matches the basename
+	if matched, _ := filepath.Match(pattern, filepath.Base(relPath)); matched {
+		return true
+	}
+	// Check if pattern matches the full relative path
+	matched, _ := filepath.Match(pattern, relPath)
+	return matched
+}
+
 // processFileWithSettings handles copying binary files or rendering text files with template settings.
-func (r *Renderer) processFileWithSettings(srcPath, targetPath string, data map[string]any, settings TemplateSettings) error {
+func (r *Renderer) processFileWithSettings(f fileTarget, data map[string]any, settings TemplateSettings) error {
 	// Get file info for permissions
-	srcInfo, err := os.Stat(srcPath)
+	srcInfo, err := os.Stat(f.srcPath)
 	if err != nil {
 		return fmt.Errorf("stat source file: %w", err)
 	}
 	mode := srcInfo.Mode()
 
-	// Read file content
-	content, err := os.ReadFile(srcPath)
-	if err != nil {
-		return fmt.Errorf("read source file: %w", err)
-	}
-
-	// Ensure target directory exists
-	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
-		return fmt.Errorf("create target directory: %w", err)
-	}
-
 	// Determine file permissions
 	var targetMode os.FileMode
 	if settings.KeepPermissions {
@@ -409,17 +1213,161 @@ func (r *Renderer) processFileWithSettings(srcPath, targetPath string, data map[
 	} else {
 		targetMode = 0644 // Default permissions
 	}
+	if executable(f.targetRel, settings) {
+		targetMode |= 0o111
+	}
+
+	// Raw files are copied as they are, without template parsing
+	if f.verbatim != "" {
+		return r.copyFile(f, targetMode)
+	}
+
+	// Files too large to hold in memory are copied as-is without rendering
+	if srcInfo.Size() > r.maxFileSize() {
+		r.oversized = append(r.oversized, f.rel)
+		f.verbatim = "exceeds the maximum file size"
+		return r.copyFile(f, targetMode)
+	}
+
+	// Read file content
+	content, err := os.ReadFile(f.srcPath)
+	if err != nil {
+		return fmt.Errorf("read source file: %w", err)
+	}
 
 	// Binary files are copied as-is, text files are rendered
 	if isBinary(content) {
-		return os.WriteFile(targetPath, content, targetMode)
+		f.verbatim = "binary content"
+		return r.writeFile(f, content, targetMode)
 	}
 
 	// Render text file
-	rendered, err := r.renderBytes(content, data)
+	tmpl, err := prepareTemplate(string(stripBOM(content)), settings)
+	if err != nil {
+		return fmt.Errorf("prepare template: %w", err)
+	}
+	rendered, err := r.renderBytes([]byte(tmpl), data)
 	if err != nil {
 		return fmt.Errorf("render template: %w", err)
 	}
 
-	return os.WriteFile(targetPath, rendered, targetMode)
+	if header := r.headerFor(f, settings); header != "" {
+		rendered = injectHeader(rendered, header)
+	}
+
+	if settings.EnsureFinalNewline {
+		rendered = ensureFinalNewline(rendered)
+	}
+
+	// Convert to the configured output encoding
+	encoded, err := encodeOutput(rendered, encodingFor(f.rel, settings))
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", f.rel, err)
+	}
+
+	return r.writeFile(f, encoded, targetMode)
+}
+
+// writeFile writes final content to the target, recording its hash in the manifest.
+// In incremental mode a file whose content matches the previous manifest is left untouched.
+func (r *Renderer) writeFile(f fileTarget, content []byte, mode os.FileMode) error {
+	hash := hashContent(content)
+	if r.unchanged(f, hash) {
+		return nil
+	}
+	if r.opts.Merge {
+		if identical, err := r.mergeTarget(f, hash); err != nil || identical {
+			return err
+		}
+	}
+	r.explainWrite(f)
+	if err := r.planWrite(f); err != nil {
+		return err
+	}
+
+	return r.output(func() error {
+		// Ensure target directory exists
+		if err := os.MkdirAll(filepath.Dir(f.targetPath), 0o755); err != nil {
+			return fmt.Errorf("create target directory: %w", err)
+		}
+
+		if err := os.WriteFile(f.targetPath, content, mode); err != nil {
+			return err
+		}
+		if err := ensureExecutable(f.targetPath, mode); err != nil {
+			return err
+		}
+		r.stats.Changed++
+		return nil
+	})
+}
+
+// copyFile streams a source file to the target without reading it into memory,
+// recording its hash in the manifest like writeFile.
+func (r *Renderer) copyFile(f fileTarget, mode os.FileMode) error {
+	hash, err := hashFile(f.srcPath)
+	if err != nil {
+		return fmt.Errorf("read source file: %w", err)
+	}
+	if r.unchanged(f, hash) {
+		return nil
+	}
+	if r.opts.Merge {
+		if identical, err := r.mergeTarget(f, hash); err != nil || identical {
+			return err
+		}
+	}
+	r.explainWrite(f)
+	if err := r.planWrite(f); err != nil {
+		return err
+	}
+
+	return r.output(func() error {
+		// Ensure target directory exists
+		if err := os.MkdirAll(filepath.Dir(f.targetPath), 0o755); err != nil {
+			return fmt.Errorf("create target directory: %w", err)
+		}
+
+		src, err := os.Open(f.srcPath)
+		if err != nil {
+			return fmt.Errorf("read source file: %w", err)
+		}
+		defer func() { _ = src.Close() }()
+
+		dst, err := os.OpenFile(f.targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			_ = dst.Close()
+			return err
+		}
+		if err := dst.Close(); err != nil {
+			return err
+		}
+		if err := ensureExecutable(f.targetPath, mode); err != nil {
+			return err
+		}
+		r.stats.Changed++
+		return nil
+	})
+}
+
+// unchanged records hash in the manifest and reports whether, in incremental mode,
+// the target already has this content and can be left untouched.
+func (r *Renderer) unchanged(f fileTarget, hash string) bool {
+	r.manifest.Files[f.targetRel] = hash
+
+	if r.opts.Incremental && r.opts.Previous.Files[f.targetRel] == hash {
+		if _, err := os.Stat(f.targetPath); err == nil {
+			r.stats.Unchanged++
+			r.explain(f.rel, "unchanged", "same content as the previous run")
+			r.plan(f.targetRel, PlanUnchanged, "", false)
+			if r.opts.Merge {
+				r.mergeKept(f, hash)
+			}
+			return true
+		}
+	}
+	return false
 }
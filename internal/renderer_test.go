@@ -1,9 +1,14 @@
 package internal
 
 import (
+	"bytes"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -392,6 +397,32 @@ func TestRenderer_RenderTree_EdgeCases(t *testing.T) {
 		expected := "#!/bin/bash\necho 'Hello World'"
 		assert.Equal(t, expected, string(renderedContent))
 	})
+
+	t.Run("path traversal in rendered name", func(t *testing.T) {
+		srcRoot := t.TempDir()
+		parent := t.TempDir()
+		outRoot := filepath.Join(parent, "out")
+		require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "{{.name}}.txt"), []byte("pwned"), 0644))
+		data := map[string]any{"name": "../escaped"}
+
+		err := renderer.RenderTree(srcRoot, outRoot, data)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "escapes the output directory")
+
+		err = renderer.RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "escapes the output directory")
+		assert.NoFileExists(t, filepath.Join(parent, "escaped.txt"))
+	})
+
+	t.Run("dot segments that stay inside the output", func(t *testing.T) {
+		srcRoot := t.TempDir()
+		outRoot := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "{{.name}}.txt"), []byte("ok"), 0644))
+
+		require.NoError(t, renderer.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "a/../b"}, TemplateSettings{}))
+		assert.FileExists(t, filepath.Join(outRoot, "b.txt"))
+	})
 }
 
 func TestRenderer_RenderTreeWithSettings(t *testing.T) {
@@ -518,6 +549,37 @@ func TestRenderer_RenderTreeWithSettings(t *testing.T) {
 				assert.Equal(t, os.FileMode(0444), info.Mode().Perm())
 			},
 		},
+		{
+			name: "executable patterns",
+			settings: TemplateSettings{
+				Executable: []string{"*.sh", "bin/*"},
+			},
+			setupFunc: func(t *testing.T) (string, string, map[string]any) {
+				srcRoot := t.TempDir()
+				outRoot := t.TempDir()
+
+				require.NoError(t, os.MkdirAll(filepath.Join(srcRoot, "bin"), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "setup.sh"), []byte("#!/bin/sh\necho {{.name}}"), 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "bin", "{{.name}}"), []byte("#!/bin/sh"), 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "README.md"), []byte("{{.name}}"), 0755))
+
+				// An existing output file keeps its mode on write, so the bit must still be added
+				require.NoError(t, os.WriteFile(filepath.Join(outRoot, "setup.sh"), []byte("old"), 0644))
+
+				return srcRoot, outRoot, map[string]any{"name": "tool"}
+			},
+			validateFunc: func(t *testing.T, outRoot string) {
+				for name, want := range map[string]os.FileMode{
+					"setup.sh":  0755,
+					"bin/tool":  0755,
+					"README.md": 0644,
+				} {
+					info, err := os.Stat(filepath.Join(outRoot, filepath.FromSlash(name)))
+					require.NoError(t, err)
+					assert.Equal(t, want, info.Mode().Perm(), name)
+				}
+			},
+		},
 		{
 			name: "keep permissions disabled - should use default",
 			settings: TemplateSettings{
@@ -545,6 +607,125 @@ func TestRenderer_RenderTreeWithSettings(t *testing.T) {
 				assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
 			},
 		},
+		{
+			name: "ensure final newline",
+			settings: TemplateSettings{
+				EnsureFinalNewline: true,
+			},
+			setupFunc: func(t *testing.T) (string, string, map[string]any) {
+				srcRoot, err := os.MkdirTemp("", "kick-src-*")
+				require.NoError(t, err)
+				outRoot, err := os.MkdirTemp("", "kick-out-*")
+				require.NoError(t, err)
+
+				files := map[string]string{
+					"none.txt": "{{.name}}",
+					"one.txt":  "{{.name}}\n",
+					"two.txt":  "{{.name}}\n{{ if false }}x{{ end }}\n",
+					"crlf.txt": "{{.name}}\r\n\r\n",
+				}
+				for name, content := range files {
+					err = os.WriteFile(filepath.Join(srcRoot, name), []byte(content), 0644)
+					require.NoError(t, err)
+				}
+				// Binary files are exempt
+				err = os.WriteFile(filepath.Join(srcRoot, "data.bin"), []byte{0x00, 0x01}, 0644)
+				require.NoError(t, err)
+
+				return srcRoot, outRoot, map[string]any{"name": "test"}
+			},
+			validateFunc: func(t *testing.T, outRoot string) {
+				expected := map[string]string{
+					"none.txt": "test\n",
+					"one.txt":  "test\n",
+					"two.txt":  "test\n",
+					"crlf.txt": "test\r\n",
+					"data.bin": "\x00\x01",
+				}
+				for name, want := range expected {
+					content, err := os.ReadFile(filepath.Join(outRoot, name))
+					require.NoError(t, err)
+					assert.Equal(t, want, string(content), name)
+				}
+			},
+		},
+		{
+			name: "encodings - utf-16le with BOM for matching files",
+			settings: TemplateSettings{
+				Encodings: []FileEncoding{{Pattern: "*.bat", Encoding: "utf-16le-bom"}},
+			},
+			setupFunc: func(t *testing.T) (string, string, map[string]any) {
+				srcRoot, err := os.MkdirTemp("", "kick-src-*")
+				require.NoError(t, err)
+				outRoot, err := os.MkdirTemp("", "kick-out-*")
+				require.NoError(t, err)
+
+				err = os.WriteFile(filepath.Join(srcRoot, "run.bat"), []byte("@echo {{.name}}"), 0644)
+				require.NoError(t, err)
+				err = os.WriteFile(filepath.Join(srcRoot, "run.sh"), []byte("echo {{.name}}"), 0644)
+				require.NoError(t, err)
+
+				return srcRoot, outRoot, map[string]any{"name": "hi"}
+			},
+			validateFunc: func(t *testing.T, outRoot string) {
+				content, err := os.ReadFile(filepath.Join(outRoot, "run.bat"))
+				require.NoError(t, err)
+				expected := []byte{0xFF, 0xFE, '@', 0, 'e', 0, 'c', 0, 'h', 0, 'o', 0, ' ', 0, 'h', 0, 'i', 0}
+				assert.Equal(t, expected, content)
+
+				// Non-matching files stay UTF-8 without BOM
+				content, err = os.ReadFile(filepath.Join(outRoot, "run.sh"))
+				require.NoError(t, err)
+				assert.Equal(t, "echo hi", string(content))
+			},
+		},
+		{
+			name: "encodings - source BOM is stripped unless the encoding adds one",
+			settings: TemplateSettings{
+				Encodings: []FileEncoding{{Pattern: "*.cs", Encoding: "utf-8-bom"}},
+			},
+			setupFunc: func(t *testing.T) (string, string, map[string]any) {
+				srcRoot, err := os.MkdirTemp("", "kick-src-*")
+				require.NoError(t, err)
+				outRoot, err := os.MkdirTemp("", "kick-out-*")
+				require.NoError(t, err)
+
+				err = os.WriteFile(filepath.Join(srcRoot, "notes.txt"), []byte("\xEF\xBB\xBF{{.name}}"), 0644)
+				require.NoError(t, err)
+				err = os.WriteFile(filepath.Join(srcRoot, "Program.cs"), []byte("\xEF\xBB\xBF// {{.name}}"), 0644)
+				require.NoError(t, err)
+
+				return srcRoot, outRoot, map[string]any{"name": "hi"}
+			},
+			validateFunc: func(t *testing.T, outRoot string) {
+				content, err := os.ReadFile(filepath.Join(outRoot, "notes.txt"))
+				require.NoError(t, err)
+				assert.Equal(t, "hi", string(content))
+
+				content, err = os.ReadFile(filepath.Join(outRoot, "Program.cs"))
+				require.NoError(t, err)
+				assert.Equal(t, "\xEF\xBB\xBF// hi", string(content), "exactly one BOM")
+			},
+		},
+		{
+			name: "encodings - unrepresentable characters fail",
+			settings: TemplateSettings{
+				Encodings: []FileEncoding{{Pattern: "*.txt", Encoding: "iso-8859-1"}},
+			},
+			setupFunc: func(t *testing.T) (string, string, map[string]any) {
+				srcRoot, err := os.MkdirTemp("", "kick-src-*")
+				require.NoError(t, err)
+				outRoot, err := os.MkdirTemp("", "kick-out-*")
+				require.NoError(t, err)
+
+				err = os.WriteFile(filepath.Join(srcRoot, "note.txt"), []byte("{{.name}}"), 0644)
+				require.NoError(t, err)
+
+				return srcRoot, outRoot, map[string]any{"name": "日本"}
+			},
+			wantErr:     true,
+			errContains: "encode note.txt",
+		},
 	}
 
 	for _, tt := range tests {
@@ -573,3 +754,966 @@ func TestRenderer_RenderTreeWithSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderer_RawRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "region is emitted verbatim",
+			content: "name: {{ .name }}\n{{/* kick:raw */}}image: {{ .Values.image }}\nquote: \"x\"{{/* kick:endraw */}}\n",
+			want:    "name: app\nimage: {{ .Values.image }}\nquote: \"x\"\n",
+		},
+		{
+			name:    "multiple regions",
+			content: "{{/* kick:raw */}}{{ a }}{{/* kick:endraw */}}-{{ .name }}-{{/*kick:raw*/}}{{ b }}{{/*kick:endraw*/}}",
+			want:    "{{ a }}-app-{{ b }}",
+		},
+		{
+			name:    "empty region",
+			content: "{{/* kick:raw */}}{{/* kick:endraw */}}{{ .name }}",
+			want:    "app",
+		},
+		{
+			name:    "unclosed region",
+			content: "{{/* kick:raw */}}{{ .Values }}",
+			wantErr: true,
+		},
+		{
+			name:    "stray end marker",
+			content: "{{ .name }}{{/* kick:endraw */}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRenderer().renderBytes([]byte(tt.content), map[string]any{"name": "app"})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRenderer_Delimiters(t *testing.T) {
+	srcRoot := t.TempDir()
+	files := map[string]string{
+		"<% .name %>.yaml":   "name: <% .name | upper %>\nimage: {{ .Values.image }}\n",
+		"raw.txt":            "<%/* kick:raw */%><% literal %><%/* kick:endraw */%>\n",
+		"{{ .name }}-docs":   "kept",
+		"optional/extra.txt": "<% .name %>",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcRoot, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	settings := TemplateSettings{
+		Delimiters: []string{"<%", "%>"},
+		// Conditions are written in kick.yaml and keep the default delimiters
+		Files: []FileCondition{{Pattern: "optional", When: "{{ .extra }}"}},
+	}
+
+	outRoot := t.TempDir()
+	rend := NewRendererWithOptions(RenderOptions{})
+	require.NoError(t, rend.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "api", "extra": false}, settings))
+
+	for name, want := range map[string]string{
+		"api.yaml":         "name: API\nimage: {{ .Values.image }}\n",
+		"raw.txt":          "<% literal %>\n",
+		"{{ .name }}-docs": "kept",
+	} {
+		content, err := os.ReadFile(filepath.Join(outRoot, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(content), name)
+	}
+	assert.NoDirExists(t, filepath.Join(outRoot, "optional"))
+}
+
+func TestTemplateFuncs_Lists(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]any
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "goSlice with values",
+			template: `{{ goSlice .items }}`,
+			data:     map[string]any{"items": []string{"a", "b"}},
+			want:     `[]string{"a", "b"}`,
+		},
+		{
+			name:     "goSlice empty",
+			template: `{{ goSlice .items }}`,
+			data:     map[string]any{"items": []string{}},
+			want:     `[]string{}`,
+		},
+		{
+			name:     "goSlice escapes quotes and backslashes",
+			template: `{{ goSlice .items }}`,
+			data:     map[string]any{"items": []any{`say "hi"`, `C:\dir`}},
+			want:     `[]string{"say \"hi\"", "C:\\dir"}`,
+		},
+		{
+			name:     "yamlList with values",
+			template: `{{ .items | yamlList }}`,
+			data:     map[string]any{"items": []string{"api", "worker"}},
+			want:     "- api\n- worker",
+		},
+		{
+			name:     "yamlList empty",
+			template: `{{ yamlList .items }}`,
+			data:     map[string]any{"items": []string{}},
+			want:     "[]",
+		},
+		{
+			name:     "yamlList quotes values needing it",
+			template: `{{ yamlList .items }}`,
+			data:     map[string]any{"items": []string{"yes", "a: b", "", "8080"}},
+			want:     "- \"yes\"\n- 'a: b'\n- \"\"\n- \"8080\"",
+		},
+		{
+			name:     "non-list value",
+			template: `{{ goSlice .items }}`,
+			data:     map[string]any{"items": "abc"},
+			wantErr:  true,
+		},
+		{
+			name:     "has list item",
+			template: `{{ has "api" .items }} {{ has 8080 .ports }} {{ has "db" .items }}`,
+			data:     map[string]any{"items": []any{"api", "worker"}, "ports": []any{8080}},
+			want:     "true true false",
+		},
+		{
+			name:     "has map key and substring",
+			template: `{{ has "name" .service }} {{ has "app" .module }} {{ has "x" .unset }}`,
+			data:     map[string]any{"service": map[string]any{"name": "api"}, "module": "example.com/app", "unset": nil},
+			want:     "true true false",
+		},
+		{
+			name:     "has in a number",
+			template: `{{ has 1 .port }}`,
+			data:     map[string]any{"port": 8080},
+			wantErr:  true,
+		},
+	}
+
+	renderer := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(tt.template, tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplateFuncs_Paths(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "join module path", template: `{{ pathJoin .module "internal" "greet" }}`, want: "github.com/acme/app/internal/greet"},
+		{name: "trailing slashes", template: `{{ pathJoin "github.com/acme/app/" "internal/" }}`, want: "github.com/acme/app/internal"},
+		{name: "empty segments", template: `{{ pathJoin "" .module "" "cmd" }}`, want: "github.com/acme/app/cmd"},
+		{name: "backslashes", template: `{{ pathJoin "github.com/acme/app" "internal\\greet" }}`, want: "github.com/acme/app/internal/greet"},
+		{name: "no segments", template: `{{ pathJoin "" "" }}`, want: ""},
+		{name: "base of module", template: `{{ basePath .module }}`, want: "app"},
+		{name: "base with trailing slash", template: `{{ basePath "github.com/acme/app/" }}`, want: "app"},
+		{name: "base of empty path", template: `{{ basePath "" }}`, want: ""},
+	}
+
+	renderer := NewRenderer()
+	data := map[string]any{"module": "github.com/acme/app"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(tt.template, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplateFuncs_Trim(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "trimAll leading slashes", template: `{{ trimAll "/" "//srv/app/" }}`, want: "srv/app"},
+		{name: "trimAll multiple characters", template: `{{ trimAll "\"' " "\" 'quoted' \"" }}`, want: "quoted"},
+		{name: "trimAll keeps inner characters", template: `{{ trimAll "-_" "-_my-app_-" }}`, want: "my-app"},
+		{name: "trimAll empty cutset", template: `{{ trimAll "" " app " }}`, want: " app "},
+		{name: "trimAll piped", template: `{{ .path | trimAll "/" }}`, want: "api/v1"},
+		{name: "trimPrefix once", template: `{{ trimPrefix "v" "vv1.2.0" }}`, want: "v1.2.0"},
+		{name: "trimPrefix absent", template: `{{ trimPrefix "v" "1.2.0" }}`, want: "1.2.0"},
+		{name: "trimSuffix", template: `{{ trimSuffix ".git" "app.git" }}`, want: "app"},
+		{name: "trimSuffix piped", template: `{{ .path | trimSuffix "/" }}`, want: "/api/v1"},
+	}
+
+	renderer := NewRenderer()
+	data := map[string]any{"path": "/api/v1/"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(tt.template, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplateFuncs_ArgumentTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{name: "number answer", template: `{{ snake .port }}`, want: "8080"},
+		{name: "number literal", template: `{{ kebab 42 }}`, want: "42"},
+		{name: "fraction", template: `{{ replace .ratio "." "_" }}`, want: "1_5"},
+		{name: "boolean", template: `{{ upper .enabled }}`, want: "TRUE"},
+		{name: "unset variable", template: `{{ pascal .unset }}`, want: ""},
+		{name: "piped number", template: `{{ .port | trimPrefix "80" }}`, want: "80"},
+		{name: "variadic", template: `{{ pathJoin "api" .port }}`, want: "api/8080"},
+		{name: "list", template: `{{ snake .services }}`, wantErr: "error calling snake: expected a string, got a list ([]interface {})"},
+		{name: "map", template: `{{ trimSuffix "/" .service }}`, wantErr: "error calling trimSuffix: expected a string, got a map (map[string]interface {})"},
+	}
+
+	renderer := NewRenderer()
+	data := map[string]any{
+		"port":     8080.0,
+		"ratio":    1.5,
+		"enabled":  true,
+		"unset":    nil,
+		"services": []any{"api"},
+		"service":  map[string]any{"name": "api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(tt.template, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplateFuncs_PluralizeIf(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   any
+		want    string
+		wantErr string
+	}{
+		{name: "zero", count: 0, want: "files"},
+		{name: "one", count: 1, want: "file"},
+		{name: "many", count: 3, want: "files"},
+		{name: "number answer", count: 1.0, want: "file"},
+		{name: "fraction", count: 1.5, want: "files"},
+		{name: "string count", count: " 1 ", want: "file"},
+		{name: "string zero", count: "0", want: "files"},
+		{name: "not a number", count: "many", wantErr: `count "many" is not a number`},
+		{name: "wrong type", count: true, wantErr: "count must be a number, got bool"},
+	}
+
+	renderer := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(`{{ pluralizeIf .n "file" "files" }}`, map[string]any{"n": tt.count})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplateFuncs_Structured(t *testing.T) {
+	service := map[string]any{
+		"name":  "api",
+		"ports": []any{8080, 9090},
+		"env":   map[string]any{"LOG_LEVEL": "debug"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]any
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "toYaml nested map",
+			template: `{{ toYaml .service }}`,
+			data:     map[string]any{"service": service},
+			want:     "env:\n  LOG_LEVEL: debug\nname: api\nports:\n  - 8080\n  - 9090",
+		},
+		{
+			name:     "toYaml with nindent",
+			template: "service:{{ .service | toYaml | nindent 2 }}",
+			data:     map[string]any{"service": map[string]any{"name": "api", "tags": []string{"a", "b"}}},
+			want:     "service:\n  name: api\n  tags:\n    - a\n    - b",
+		},
+		{
+			name:     "toJson nested map",
+			template: `{{ toJson .service }}`,
+			data:     map[string]any{"service": service},
+			want:     `{"env":{"LOG_LEVEL":"debug"},"name":"api","ports":[8080,9090]}`,
+		},
+		{
+			name:     "toPrettyJson slice",
+			template: `{{ toPrettyJson .items }}`,
+			data:     map[string]any{"items": []any{"a", map[string]any{"b": 1}}},
+			want:     "[\n  \"a\",\n  {\n    \"b\": 1\n  }\n]",
+		},
+		{
+			name:     "indent skips empty lines",
+			template: `{{ indent 2 .text }}`,
+			data:     map[string]any{"text": "a\n\nb"},
+			want:     "  a\n\n  b",
+		},
+		{
+			name:     "toJson unsupported value",
+			template: `{{ toJson .fn }}`,
+			data:     map[string]any{"fn": func() {}},
+			wantErr:  true,
+		},
+	}
+
+	renderer := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(tt.template, tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderer_Incremental(t *testing.T) {
+	srcRoot := t.TempDir()
+	outRoot := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "same.txt"), []byte("static"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "name.txt"), []byte("{{.name}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "skip.tmp"), []byte("tmp"), 0644))
+	settings := TemplateSettings{IgnorePatterns: []string{"*.tmp"}}
+
+	// First run writes everything and produces the manifest
+	first := NewRenderer()
+	require.NoError(t, first.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "one"}, settings))
+	assert.Equal(t, RenderStats{Changed: 2, Skipped: 1}, first.Stats())
+	require.NoError(t, first.Manifest().Save(outRoot))
+
+	// Backdate the unchanged file so a rewrite would be visible
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	samePath := filepath.Join(outRoot, "same.txt")
+	require.NoError(t, os.Chtimes(samePath, old, old))
+
+	previous, err := LoadManifest(outRoot)
+	require.NoError(t, err)
+	second := NewRendererWithOptions(RenderOptions{Incremental: true, Previous: previous})
+	require.NoError(t, second.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "two"}, settings))
+
+	assert.Equal(t, RenderStats{Changed: 1, Unchanged: 1, Skipped: 1}, second.Stats())
+	info, err := os.Stat(samePath)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().Equal(old), "unchanged file should keep its mtime")
+
+	content, err := os.ReadFile(filepath.Join(outRoot, "name.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(content))
+	assert.Equal(t, hashContent([]byte("two")), second.Manifest().Files["name.txt"])
+}
+
+func TestRenderer_KeepGoing(t *testing.T) {
+	srcRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "ok.txt"), []byte("{{.name}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "bad1.txt"), []byte("{{.missing}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "bad2.txt"), []byte("{{ if }}"), 0644))
+	data := map[string]any{"name": "app"}
+
+	t.Run("stops at first failure by default", func(t *testing.T) {
+		err := NewRenderer().RenderTreeWithSettings(srcRoot, t.TempDir(), data, TemplateSettings{})
+		require.Error(t, err)
+		var failures RenderFailures
+		assert.False(t, errors.As(err, &failures))
+	})
+
+	t.Run("reports every failure", func(t *testing.T) {
+		outRoot := t.TempDir()
+		err := NewRendererWithOptions(RenderOptions{KeepGoing: true}).RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{})
+
+		var failures RenderFailures
+		require.True(t, errors.As(err, &failures))
+		require.Len(t, failures, 2)
+		assert.Equal(t, "bad1.txt", failures[0].Path)
+		assert.Equal(t, "bad2.txt", failures[1].Path)
+		assert.Contains(t, err.Error(), "2 files failed to render")
+
+		content, err := os.ReadFile(filepath.Join(outRoot, "ok.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "app", string(content))
+	})
+}
+
+func TestRenderer_MaxFileSize(t *testing.T) {
+	srcRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "small.txt"), []byte("{{.name}}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "large.txt"), []byte("{{.name}} "+strings.Repeat("x", 64)), 0644))
+	outRoot := t.TempDir()
+
+	rend := NewRendererWithOptions(RenderOptions{MaxFileSize: 32})
+	require.NoError(t, rend.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "app"}, TemplateSettings{}))
+	assert.Equal(t, []string{"large.txt"}, rend.Oversized())
+
+	small, err := os.ReadFile(filepath.Join(outRoot, "small.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "app", string(small))
+
+	large, err := os.ReadFile(filepath.Join(outRoot, "large.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "{{.name}} "+strings.Repeat("x", 64), string(large))
+	assert.Equal(t, hashContent(large), rend.Manifest().Files["large.txt"])
+}
+
+func TestPortableName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{name: "main.go"},
+		{name: ".gitignore"},
+		{name: "console.log"},
+		{name: "COM10"},
+		{name: "."},
+		{name: "CON", wantErr: "CON is a reserved name on Windows"},
+		{name: "nul", wantErr: "nul is a reserved name on Windows"},
+		{name: "aux.txt", wantErr: "aux is a reserved name on Windows"},
+		{name: "LPT9.tar.gz", wantErr: "LPT9 is a reserved name on Windows"},
+		{name: "COM1 .txt", wantErr: "reserved name"},
+		{name: "notes.", wantErr: "ending in a dot or space"},
+		{name: "notes ", wantErr: "ending in a dot or space"},
+		{name: "a:b", wantErr: `':' is invalid`},
+		{name: "what?.md", wantErr: `'?' is invalid`},
+		{name: `back\slash`, wantErr: `'\\' is invalid`},
+		{name: "tab\there", wantErr: `'\t' is invalid`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := portableName(tt.name)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRenderer_NonPortableNames(t *testing.T) {
+	srcRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "{{.name}}.txt"), []byte("x"), 0644))
+	data := map[string]any{"name": "con"}
+
+	renderer := NewRenderer()
+	require.NoError(t, renderer.RenderTreeWithSettings(srcRoot, t.TempDir(), data, TemplateSettings{}))
+	assert.Equal(t, []string{`"{{.name}}.txt" renders to "con.txt": con is a reserved name on Windows`}, renderer.NonPortable())
+
+	err := NewRendererWithOptions(RenderOptions{StrictNames: true}).RenderTreeWithSettings(srcRoot, t.TempDir(), data, TemplateSettings{})
+	assert.ErrorContains(t, err, "con is a reserved name on Windows")
+}
+
+func TestRenderer_Explanations(t *testing.T) {
+	srcRoot := t.TempDir()
+	files := map[string]string{
+		KickYAML:                   "name: test",
+		"README.md":                "{{ .name }}",
+		"notes.tmp":                "scratch",
+		"{{ .optional }}":          "dropped",
+		"docs/guide.md":            "guide",
+		"logo.bin":                 "\x00\x01",
+		"ci/{{ .name }}.yml":       "ci",
+		"broken/{{ .missing }}.go": "x",
+	}
+	for name, content := range files {
+		target := filepath.Join(srcRoot, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(target), 0755))
+		require.NoError(t, os.WriteFile(target, []byte(content), 0644))
+	}
+	settings := TemplateSettings{
+		IgnorePatterns: []string{"*.tmp"},
+		Tags:           map[string][]string{"docs": {"docs"}},
+	}
+	data := map[string]any{"name": "demo", "optional": ""}
+
+	renderer := NewRendererWithOptions(RenderOptions{Skip: []string{"docs"}, KeepGoing: true})
+	err := renderer.RenderTreeWithSettings(srcRoot, t.TempDir(), data, settings)
+	require.Error(t, err)
+
+	assert.Equal(t, []Explanation{
+		{Path: "README.md", Outcome: "rendered"},
+		{Path: "broken", Outcome: "created"},
+		{Path: "broken/{{ .missing }}.go", Outcome: "failed", Reason: renderer.failures[0].Err.Error()},
+		{Path: "ci", Outcome: "created"},
+		{Path: "ci/{{ .name }}.yml", Outcome: "rendered"},
+		{Path: "docs", Outcome: "skipped", Reason: `tag "docs" is skipped`},
+		{Path: KickYAML, Outcome: "skipped", Reason: KickYAML + " is never rendered"},
+		{Path: "logo.bin", Outcome: "copied", Reason: "binary content"},
+		{Path: "notes.tmp", Outcome: "skipped", Reason: `ignore pattern "*.tmp"`},
+		{Path: "{{ .optional }}", Outcome: "skipped", Reason: "name renders empty"},
+	}, renderer.Explanations())
+	assert.Equal(t, `skipped   notes.tmp (ignore pattern "*.tmp")`, renderer.Explanations()[8].String())
+}
+
+func TestRenderer_MergeReport(t *testing.T) {
+	srcRoot := t.TempDir()
+	for name, content := range map[string]string{
+		"new.txt":       "new",
+		"same.txt":      "same",
+		"changed.txt":   "{{ .name }}",
+		"edited.txt":    "original",
+		"sub/inner.txt": "inner",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcRoot, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcRoot, name), []byte(content), 0644))
+	}
+
+	outRoot := t.TempDir()
+	for name, content := range map[string]string{
+		"same.txt":    "same",
+		"changed.txt": "old",
+		"edited.txt":  "local edit",
+		"keep.txt":    "not in the template",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(outRoot, name), []byte(content), 0644))
+	}
+	previous := NewManifest()
+	previous.Files["edited.txt"] = hashContent([]byte("original"))
+
+	renderer := NewRendererWithOptions(RenderOptions{Merge: true, Conflicts: ConflictOverwrite, Incremental: true, Previous: previous})
+	require.NoError(t, renderer.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "demo"}, TemplateSettings{}))
+
+	assert.Equal(t, MergeReport{
+		Created:     []string{"new.txt", "sub/inner.txt"},
+		Overwritten: []string{"changed.txt"},
+		Identical:   []string{"same.txt"},
+		Conflicts:   []string{"edited.txt"},
+	}, renderer.MergeReport())
+
+	content, err := os.ReadFile(filepath.Join(outRoot, "edited.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "local edit", string(content), "a conflict is not overwritten")
+	content, err = os.ReadFile(filepath.Join(outRoot, "changed.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "demo", string(content))
+	assert.Equal(t, 2, renderer.Stats().Unchanged)
+
+	assert.True(t, NewRenderer().MergeReport().Empty())
+}
+
+func TestRenderer_Atomic(t *testing.T) {
+	srcRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcRoot, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "a.txt"), []byte("{{ .name }}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "sub", "b.txt"), []byte("{{ .name "), 0644))
+	data := map[string]any{"name": "demo"}
+
+	t.Run("without atomic earlier files are written", func(t *testing.T) {
+		outRoot := filepath.Join(t.TempDir(), "out")
+		err := NewRenderer().RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{})
+		require.Error(t, err)
+		assert.FileExists(t, filepath.Join(outRoot, "a.txt"))
+	})
+
+	t.Run("atomic writes nothing on error", func(t *testing.T) {
+		outRoot := filepath.Join(t.TempDir(), "out")
+		err := NewRendererWithOptions(RenderOptions{Atomic: true}).RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{})
+		require.Error(t, err)
+		assert.NoDirExists(t, outRoot)
+	})
+
+	t.Run("atomic writes everything on success", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "sub", "b.txt"), []byte("{{ .name }}!"), 0644))
+		outRoot := filepath.Join(t.TempDir(), "out")
+		renderer := NewRendererWithOptions(RenderOptions{Atomic: true, MaxFileSize: 3})
+		require.NoError(t, renderer.RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{}))
+
+		content, err := os.ReadFile(filepath.Join(outRoot, "sub", "b.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "{{ .name }}!", string(content), "oversized files are copied verbatim")
+		assert.Equal(t, 2, renderer.Stats().Changed)
+	})
+}
+
+func TestRenderer_StrictNames(t *testing.T) {
+	srcRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcRoot, "{{.name}}.txt"), []byte("x"), 0644))
+	data := map[string]any{"name": "nested/app"}
+
+	outRoot := t.TempDir()
+	require.NoError(t, NewRenderer().RenderTreeWithSettings(srcRoot, outRoot, data, TemplateSettings{}))
+	assert.FileExists(t, filepath.Join(outRoot, "nested", "app.txt"))
+
+	err := NewRendererWithOptions(RenderOptions{StrictNames: true}).RenderTreeWithSettings(srcRoot, t.TempDir(), data, TemplateSettings{})
+	assert.ErrorContains(t, err, "contains a path separator")
+}
+
+func TestRenderer_Tags(t *testing.T) {
+	srcRoot := t.TempDir()
+	for _, name := range []string{"main.go", "README.md", "docs/guide.txt", ".github/ci.yml"} {
+		path := filepath.Join(srcRoot, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0644))
+	}
+	settings := TemplateSettings{Tags: map[string][]string{
+		"docs": {"docs", "*.md"},
+		"ci":   {".github"},
+	}}
+
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want []string
+	}{
+		{
+			name: "no selection renders everything",
+			want: []string{".github/ci.yml", "README.md", "docs/guide.txt", "main.go"},
+		},
+		{
+			name: "only keeps untagged and selected tags",
+			opts: RenderOptions{Only: []string{"docs"}},
+			want: []string{"README.md", "docs/guide.txt", "main.go"},
+		},
+		{
+			name: "skip drops tagged files",
+			opts: RenderOptions{Skip: []string{"docs"}},
+			want: []string{".github/ci.yml", "main.go"},
+		},
+		{
+			name: "skip wins over only",
+			opts: RenderOptions{Only: []string{"docs", "ci"}, Skip: []string{"ci"}},
+			want: []string{"README.md", "docs/guide.txt", "main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outRoot := t.TempDir()
+			require.NoError(t, NewRendererWithOptions(tt.opts).RenderTreeWithSettings(srcRoot, outRoot, nil, settings))
+
+			var got []string
+			require.NoError(t, filepath.WalkDir(outRoot, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					rel, _ := filepath.Rel(outRoot, path)
+					got = append(got, filepath.ToSlash(rel))
+				}
+				return err
+			}))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderer_FileConditions(t *testing.T) {
+	srcRoot := t.TempDir()
+	for _, name := range []string{"main.go", "Dockerfile", "deploy/k8s.yaml", "docs/guide.md"} {
+		path := filepath.Join(srcRoot, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0644))
+	}
+	settings := TemplateSettings{Files: []FileCondition{
+		{Pattern: "Dockerfile", When: "{{ .use_docker }}"},
+		{Pattern: "deploy", When: "{{ .use_docker }}"},
+		{Pattern: "deploy", When: `{{ eq .platform "k8s" }}`},
+	}}
+
+	tests := []struct {
+		name string
+		data map[string]any
+		want []string
+	}{
+		{
+			name: "every condition holds",
+			data: map[string]any{"use_docker": true, "platform": "k8s"},
+			want: []string{"Dockerfile", "deploy/k8s.yaml", "docs/guide.md", "main.go"},
+		},
+		{
+			name: "a false condition drops matching files and directories",
+			data: map[string]any{"use_docker": false, "platform": "k8s"},
+			want: []string{"docs/guide.md", "main.go"},
+		},
+		{
+			name: "every matching condition must hold",
+			data: map[string]any{"use_docker": true, "platform": "vm"},
+			want: []string{"Dockerfile", "docs/guide.md", "main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outRoot := t.TempDir()
+			require.NoError(t, NewRenderer().RenderTreeWithSettings(srcRoot, outRoot, tt.data, settings))
+
+			var got []string
+			require.NoError(t, filepath.WalkDir(outRoot, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					rel, _ := filepath.Rel(outRoot, path)
+					got = append(got, filepath.ToSlash(rel))
+				}
+				return err
+			}))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	renderer := NewRenderer()
+	err := renderer.RenderTreeWithSettings(srcRoot, t.TempDir(), map[string]any{}, settings)
+	assert.ErrorContains(t, err, `evaluate condition for "Dockerfile"`)
+}
+
+func TestTemplateFuncs_Wrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]any
+		want     string
+	}{
+		{
+			name:     "wraps at width",
+			template: `{{ .text | wrap 10 }}`,
+			data:     map[string]any{"text": "the quick brown fox jumps"},
+			want:     "the quick\nbrown fox\njumps",
+		},
+		{
+			name:     "short text unchanged",
+			template: `{{ wrap 80 .text }}`,
+			data:     map[string]any{"text": "short"},
+			want:     "short",
+		},
+		{
+			name:     "long word exceeding width stays whole",
+			template: `{{ .text | wrap 8 }}`,
+			data:     map[string]any{"text": "a supercalifragilistic word"},
+			want:     "a\nsupercalifragilistic\nword",
+		},
+		{
+			name:     "prefix counts toward width",
+			template: `{{ .text | wrapWith 12 "// " }}`,
+			data:     map[string]any{"text": "one two three four"},
+			want:     "// one two\n// three\n// four",
+		},
+		{
+			name:     "paragraph breaks keep a trimmed prefix",
+			template: `{{ .text | wrapWith 20 "# " }}`,
+			data:     map[string]any{"text": "first\n\nsecond"},
+			want:     "# first\n#\n# second",
+		},
+	}
+
+	renderer := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderer.renderString(tt.template, tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenderer_DryRun(t *testing.T) {
+	srcRoot := t.TempDir()
+	for name, content := range map[string]string{
+		"README.md":                   "# {{ .name }}",
+		"cmd/{{ .name }}/main.go":     "package main",
+		"same.txt":                    "same",
+		"notes.tmp":                   "scratch",
+		"docs/guide.md":               "guide",
+		"{{ if .ci }}ci.yml{{ end }}": "ci",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(srcRoot, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(srcRoot, name), []byte(content), 0644))
+	}
+
+	outRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outRoot, "README.md"), []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outRoot, "same.txt"), []byte("same"), 0644))
+
+	renderer := NewRendererWithOptions(RenderOptions{DryRun: true, Merge: true, Conflicts: ConflictOverwrite})
+	settings := TemplateSettings{IgnorePatterns: []string{"*.tmp", "docs"}}
+	require.NoError(t, renderer.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "app", "ci": false}, settings))
+
+	var out bytes.Buffer
+	writePlan(&out, "app", renderer.Plan())
+	assert.Equal(t, `app/
+├── ~ README.md
+├── cmd/
+│   └── app/
+│       └── + main.go
+├── - docs/ (ignore pattern "docs")
+├── - notes.tmp (ignore pattern "*.tmp")
+├── = same.txt
+└── - {{ if .ci }}ci.yml{{ end }} (name renders empty)
+`, out.String())
+
+	entries, err := os.ReadDir(outRoot)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "a dry run writes nothing")
+	content, err := os.ReadFile(filepath.Join(outRoot, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(content))
+}
+
+func TestRenderer_ConflictPolicy(t *testing.T) {
+	srcRoot := t.TempDir()
+	for name, content := range map[string]string{
+		"new.txt":       "new",
+		"edited.txt":    "{{ .name }}",
+		"generated.txt": "v2",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(srcRoot, name), []byte(content), 0644))
+	}
+	previous := NewManifest()
+	previous.Files["generated.txt"] = hashContent([]byte("v1"))
+
+	tests := []struct {
+		name       string
+		policy     ConflictPolicy
+		wantErr    string
+		wantEdited string
+		wantNew    bool
+		wantReport MergeReport
+	}{
+		{
+			name:       "fail by default",
+			wantErr:    "1 existing files differ from the template\n  edited.txt",
+			wantEdited: "local edit",
+		},
+		{
+			name:       "overwrite",
+			policy:     ConflictOverwrite,
+			wantEdited: "demo",
+			wantNew:    true,
+			wantReport: MergeReport{Created: []string{"new.txt"}, Overwritten: []string{"edited.txt", "generated.txt"}},
+		},
+		{
+			name:       "skip existing",
+			policy:     ConflictSkip,
+			wantEdited: "local edit",
+			wantNew:    true,
+			wantReport: MergeReport{Created: []string{"new.txt"}, Overwritten: []string{"generated.txt"}, Conflicts: []string{"edited.txt"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outRoot := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(outRoot, "edited.txt"), []byte("local edit"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(outRoot, "generated.txt"), []byte("v1"), 0644))
+
+			renderer := NewRendererWithOptions(RenderOptions{Merge: true, Atomic: true, Conflicts: tt.policy, Previous: previous})
+			err := renderer.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "demo"}, TemplateSettings{})
+			if tt.wantErr != "" {
+				var conflicts ConflictError
+				require.ErrorAs(t, err, &conflicts)
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantReport, renderer.MergeReport())
+			}
+
+			content, err := os.ReadFile(filepath.Join(outRoot, "edited.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEdited, string(content))
+			if tt.wantNew {
+				assert.FileExists(t, filepath.Join(outRoot, "new.txt"))
+			} else {
+				assert.NoFileExists(t, filepath.Join(outRoot, "new.txt"), "a conflict writes nothing")
+			}
+		})
+	}
+
+	t.Run("dry run marks conflicts", func(t *testing.T) {
+		outRoot := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(outRoot, "edited.txt"), []byte("local edit"), 0644))
+
+		renderer := NewRendererWithOptions(RenderOptions{Merge: true, DryRun: true})
+		require.NoError(t, renderer.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "demo"}, TemplateSettings{}))
+
+		var out bytes.Buffer
+		writePlan(&out, "app", renderer.Plan())
+		assert.Equal(t, `app/
+├── ! edited.txt (differs from the template)
+├── + generated.txt
+└── + new.txt
+`, out.String())
+	})
+}
+
+func TestRenderer_RawFiles(t *testing.T) {
+	srcRoot := t.TempDir()
+	helm := "image: {{ .Values.image }}\n"
+	files := map[string]string{
+		"chart/templates/deploy.yaml":             helm,
+		"chart/values.yaml":                       "name: {{ .name }}\n",
+		"{{ .name }}.yml.kickraw":                 "run: ${{ github.sha }}\n",
+		".github/workflows/ci.yml":                "sha: ${{ github.sha }}\n",
+		"README.md":                               "# {{ .name }}\n",
+		"chart/templates/{{ .name }}-secret.yaml": helm,
+	}
+	for name, content := range files {
+		path := filepath.Join(srcRoot, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	settings := TemplateSettings{RawPatterns: []string{"templates", ".github/workflows/*.yml"}}
+
+	outRoot := t.TempDir()
+	rend := NewRendererWithOptions(RenderOptions{})
+	require.NoError(t, rend.RenderTreeWithSettings(srcRoot, outRoot, map[string]any{"name": "api"}, settings))
+
+	for name, want := range map[string]string{
+		"chart/templates/deploy.yaml":     helm,
+		"chart/templates/api-secret.yaml": helm,
+		"chart/values.yaml":               "name: api\n",
+		"api.yml":                         "run: ${{ github.sha }}\n",
+		".github/workflows/ci.yml":        "sha: ${{ github.sha }}\n",
+		"README.md":                       "# api\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(outRoot, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(content), name)
+	}
+
+	assert.Contains(t, rend.Explanations(), Explanation{Path: "chart/templates/deploy.yaml", Outcome: "copied", Reason: `raw pattern "templates"`})
+	assert.Contains(t, rend.Explanations(), Explanation{Path: "{{ .name }}.yml.kickraw", Outcome: "copied", Reason: ".kickraw suffix"})
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// scaffoldConfig is the kick.yaml of a new template; NAME is replaced by the template name
+const scaffoldConfig = `name: "NAME"
+description: "Describe what projects generated from this template contain"
+version: "0.1.0"
+
+variables:
+  project_name:
+    type: string
+    prompt: "Project name"
+    default: "my-project"
+    pattern: "^[a-z][a-z0-9-]*$"
+    help: "Lowercase letters, digits and hyphens"
+
+  description:
+    type: string
+    prompt: "Short description"
+    default: "A new project"
+
+  author_name:
+    type: string
+    prompt: "Author name"
+    default: "Your Name"
+    default_from_git: user.name
+
+  license:
+    type: choice
+    prompt: "License"
+    choices: ["MIT", "Apache-2.0", "none"]
+    default: "MIT"
+
+hooks:
+  pre_generation:
+    - "echo 'Creating {{.project_name}}'"
+  post_generation:
+    - "echo 'Done: cd {{.project_name}}'"
+
+template:
+  # Only files under template/ are generated; this README stays with the template
+  root: template
+`
+
+// scaffoldReadme documents a new template for its authors; NAME is replaced by the template name
+const scaffoldReadme = "# NAME\n\n" +
+	"A [kick](https://github.com/kick-cli/kick) template.\n\n" +
+	"```bash\nkick path/to/NAME ./out\n```\n\n" +
+	"## Layout\n\n" +
+	"- `kick.yaml` declares the variables kick asks for and the hooks it runs.\n" +
+	"- `template/` holds the files to generate. File contents, file names and directory names\n" +
+	"  are Go templates, so `template/{{.project_name}}` becomes a directory named after the answer.\n\n" +
+	"Run `kick lint .` to check the template and `kick docs .` to document its variables.\n"
+
+// scaffoldProjectReadme is the README of generated projects
+const scaffoldProjectReadme = `# {{.project_name}}
+
+{{.description}}
+{{- if ne .license "none"}}
+
+Licensed under {{.license}} by {{.author_name}}.
+{{- end}}
+`
+
+// InitTemplate creates a skeleton template in dir: a kick.yaml with example variables and
+// hooks, a README for template authors and a {{.project_name}} directory to generate.
+// It refuses to overwrite existing files.
+func InitTemplate(dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("resolve template directory: %w", err)
+	}
+	name := filepath.Base(absDir)
+
+	files := []struct {
+		path    string
+		content string
+	}{
+		{KickYAML, strings.ReplaceAll(scaffoldConfig, "NAME", name)},
+		{"README.md", strings.ReplaceAll(scaffoldReadme, "NAME", name)},
+		{filepath.Join("template", "{{.project_name}}", "README.md"), scaffoldProjectReadme},
+	}
+
+	for _, f := range files {
+		if _, err := os.Lstat(filepath.Join(absDir, f.path)); err == nil {
+			return fmt.Errorf("%s already exists", filepath.Join(dir, f.path))
+		}
+	}
+	for _, f := range files {
+		path := filepath.Join(absDir, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", f.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitTemplate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	src := filepath.Join(t.TempDir(), "service-template")
+	require.NoError(t, InitTemplate(src))
+
+	cfg, err := loadConfig(src)
+	require.NoError(t, err)
+	assert.Equal(t, "service-template", cfg.Name)
+
+	findings, err := Lint(src)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+
+	out := t.TempDir()
+	require.NoError(t, Generate(Options{Source: src, OutputDir: out, SkipHooks: true,
+		Answers: map[string]string{"project_name": "api", "author_name": "Jane", "license": "MIT"}}))
+
+	content, err := os.ReadFile(filepath.Join(out, "api", "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# api\n\nA new project\n\nLicensed under MIT by Jane.\n", string(content))
+	assert.NoFileExists(t, filepath.Join(out, "README.md"), "the template README stays with the template")
+
+	err = InitTemplate(src)
+	assert.ErrorContains(t, err, "kick.yaml already exists")
+}
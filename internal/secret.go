@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yarlson/tap"
+	"golang.org/x/term"
+)
+
+// secretMask stands in for a secret answer wherever answers are shown or written
+const secretMask = "********"
+
+// redactSecrets returns a copy of data with every answered secret replaced by secretMask,
+// including the copies of the answers under cookiecutter
+func redactSecrets(variables map[string]Variable, data map[string]any) map[string]any {
+	redacted := make(map[string]any, len(data))
+	for name, value := range data {
+		if variables[name].Type == "secret" && value != nil {
+			value = secretMask
+		} else if alias, ok := value.(map[string]any); ok && name == cookiecutterAlias {
+			value = redactSecrets(variables, alias)
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// withoutSecrets returns a copy of values without the answers to secret variables, which are
+// never written to disk
+func withoutSecrets(variables map[string]Variable, values map[string]any) map[string]any {
+	kept := make(map[string]any, len(values))
+	for name, value := range values {
+		if variables[name].Type != "secret" {
+			kept[name] = value
+		}
+	}
+	return kept
+}
+
+// envSecrets answers the secrets whose env names a set environment variable
+func envSecrets(variables map[string]Variable) map[string]any {
+	values := make(map[string]any)
+	for name, variable := range variables {
+		if variable.Type != "secret" || variable.Env == "" {
+			continue
+		}
+		if value := os.Getenv(variable.Env); value != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// Prefixes of the environment variables hooks get the answers in, kept apart from each other
+// and from KICK_OUTPUT
+const (
+	secretEnvPrefix   = "KICK_SECRET_"
+	variableEnvPrefix = "KICK_VAR_"
+)
+
+// secretEnvName returns the environment variable a secret is passed to hooks in, e.g.
+// KICK_SECRET_DEPLOY_TOKEN for deploy_token
+func secretEnvName(name string) string {
+	return secretEnvPrefix + envName(name)
+}
+
+// variableEnvName returns the environment variable hooks find the answer to a variable that
+// is not a secret in, e.g. KICK_VAR_PROJECT_NAME for project_name
+func variableEnvName(name string) string {
+	return variableEnvPrefix + envName(name)
+}
+
+// envName turns a variable name into the upper case letters, digits and underscores of an
+// environment variable name
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// hookSecrets keeps secret answers out of hook command strings. In the returned data each
+// secret is replaced by a reference to its environment variable, so "{{ .token }}" renders
+// as "$KICK_SECRET_TOKEN" and the shell expands it; env holds the matching NAME=value entries.
+// The copies of the answers under cookiecutter are replaced too.
+func hookSecrets(variables map[string]Variable, data map[string]any) (map[string]any, []string) {
+	var env []string
+	hookData := data
+	for name, variable := range variables {
+		value, ok := data[name]
+		if variable.Type != "secret" || !ok || value == nil {
+			continue
+		}
+		if len(env) == 0 {
+			hookData = make(map[string]any, len(data))
+			for k, v := range data {
+				hookData[k] = v
+			}
+		}
+		envName := secretEnvName(name)
+		hookData[name] = "$" + envName
+		env = append(env, fmt.Sprintf("%s=%v", envName, value))
+	}
+	if alias, ok := data[cookiecutterAlias].(map[string]any); ok && len(env) > 0 {
+		hookData[cookiecutterAlias], _ = hookSecrets(variables, alias)
+	}
+	return hookData, env
+}
+
+// promptSecret asks for a secret without echoing it
+func (p prompter) promptSecret(variable Variable, required bool) (any, error) {
+	validate := func(input string) error {
+		if input == "" && required {
+			return errRequired
+		}
+		if input == "" {
+			return nil
+		}
+		return variable.Validate(input)
+	}
+
+	if p.compact {
+		return p.compactSecret(variable.Prompt, validate)
+	}
+
+	submit := submission{validate: validate}
+	input, err := awaitPrompt(p.timeout, func() string {
+		return tap.Password(tap.PasswordOptions{
+			Message:  variable.Prompt,
+			Validate: submit.check,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if promptCancelled(!submit.submitted) {
+		return nil, ErrCancelled
+	}
+	return input, nil
+}
+
+// compactSecret asks for a secret on a single line, without echo when it is typed at a terminal
+func (p prompter) compactSecret(message string, validate func(string) error) (string, error) {
+	fd := int(os.Stdin.Fd())
+	for {
+		_, _ = fmt.Fprintf(p.out, "%s: ", message)
+
+		var line string
+		if p.in == stdinReader && p.in.Buffered() == 0 && term.IsTerminal(fd) {
+			input, err := awaitPrompt(p.timeout, func() []byte {
+				b, _ := term.ReadPassword(fd)
+				return b
+			})
+			_, _ = fmt.Fprintln(p.out)
+			if err != nil {
+				return "", err
+			}
+			line = strings.TrimSpace(string(input))
+		} else {
+			answer, eof, err := p.readAnswer()
+			if err != nil {
+				return "", err
+			}
+			if eof {
+				// There is no more input to ask again, so a required secret fails here
+				if err := validate(""); err != nil {
+					return "", err
+				}
+				return "", nil
+			}
+			line = answer
+		}
+
+		if err := validate(line); err != nil {
+			_, _ = fmt.Fprintf(p.out, "  %v\n", err)
+			continue
+		}
+		return line, nil
+	}
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingsFile is the name of the user-level settings file inside the kick config directory
+const SettingsFile = "config.yaml"
+
+// Settings holds user-level defaults applied to every run. Command-line flags take precedence.
+type Settings struct {
+	// GitToken authenticates HTTPS clones of private template repositories
+	GitToken string `yaml:"git_token,omitempty"`
+	// Incremental enables incremental generation by default
+	Incremental bool `yaml:"incremental,omitempty"`
+	// PromptStyle selects "stepped" (the default) or "compact" single-line prompts
+	PromptStyle string `yaml:"prompt_style,omitempty"`
+	// VersionFile writes a provenance marker with this name into every generated project
+	VersionFile string `yaml:"version_file,omitempty"`
+	// Answers pre-fills variables shared across templates, e.g. author_name
+	Answers map[string]string `yaml:"answers,omitempty"`
+	// HookPolicy restricts the commands template hooks may run
+	HookPolicy HookPolicy `yaml:"hook_policy,omitempty"`
+	// CacheDir keeps cloned git templates in this directory instead of the default cache
+	CacheDir string `yaml:"cache_dir,omitempty"`
+	// Conflicts decides what happens to existing files that differ from the template when no
+	// --force, --skip-existing or --interactive-conflicts flag is given
+	Conflicts ConflictPolicy `yaml:"conflicts,omitempty"`
+}
+
+// ConfigDir returns the kick config directory ($XDG_CONFIG_HOME/kick or ~/.config/kick)
+func ConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "kick"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "kick"), nil
+}
+
+// LoadSettings reads the settings file at path. With an empty path the default
+// location is used, and a missing default file yields empty settings.
+func LoadSettings(path string) (Settings, error) {
+	explicit := path != ""
+	if !explicit {
+		dir, err := ConfigDir()
+		if err != nil {
+			return Settings{}, err
+		}
+		path = filepath.Join(dir, SettingsFile)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return Settings{}, nil
+	}
+	if err != nil {
+		return Settings{}, fmt.Errorf("read settings: %w", err)
+	}
+
+	var settings Settings
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("parse settings %s: %w", path, err)
+	}
+	if err := settings.HookPolicy.validate(); err != nil {
+		return Settings{}, fmt.Errorf("settings %s: %w", path, err)
+	}
+	if err := validConflictPolicy(settings.Conflicts); err != nil {
+		return Settings{}, fmt.Errorf("settings %s: %w", path, err)
+	}
+	if settings.CacheDir != "" {
+		if settings.CacheDir, err = normalizePath(settings.CacheDir); err != nil {
+			return Settings{}, fmt.Errorf("settings %s: cache_dir: %w", path, err)
+		}
+	}
+	return settings, nil
+}
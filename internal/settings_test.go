@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSettings(t *testing.T) {
+	t.Run("explicit path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.yaml")
+		content := `git_token: "secret"
+incremental: true
+answers:
+  author_name: "Jane Doe"`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		settings, err := LoadSettings(path)
+		require.NoError(t, err)
+		assert.Equal(t, Settings{
+			GitToken:    "secret",
+			Incremental: true,
+			Answers:     map[string]string{"author_name": "Jane Doe"},
+		}, settings)
+	})
+
+	t.Run("missing explicit path fails", func(t *testing.T) {
+		_, err := LoadSettings(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "read settings")
+	})
+
+	t.Run("missing default file yields empty settings", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+		settings, err := LoadSettings("")
+		require.NoError(t, err)
+		assert.Equal(t, Settings{}, settings)
+	})
+
+	t.Run("default location under XDG_CONFIG_HOME", func(t *testing.T) {
+		configHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", configHome)
+		require.NoError(t, os.MkdirAll(filepath.Join(configHome, "kick"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(configHome, "kick", SettingsFile), []byte("incremental: true"), 0644))
+
+		settings, err := LoadSettings("")
+		require.NoError(t, err)
+		assert.True(t, settings.Incremental)
+	})
+
+	t.Run("hook policy", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("hook_policy:\n  allow: [go, npm]\n  deny: [\"/rm -rf/\"]\n"), 0644))
+
+		settings, err := LoadSettings(path)
+		require.NoError(t, err)
+		assert.Equal(t, HookPolicy{Allow: []string{"go", "npm"}, Deny: []string{"/rm -rf/"}}, settings.HookPolicy)
+
+		require.NoError(t, os.WriteFile(path, []byte("hook_policy:\n  deny: [\"/(/\"]\n"), 0644))
+		_, err = LoadSettings(path)
+		assert.ErrorContains(t, err, "invalid hook policy pattern")
+	})
+
+	t.Run("cache directory and conflict policy", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		path := filepath.Join(t.TempDir(), "settings.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("cache_dir: ~/kick-cache\nconflicts: skip\n"), 0644))
+
+		settings, err := LoadSettings(path)
+		require.NoError(t, err)
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, "kick-cache"), settings.CacheDir)
+		assert.Equal(t, ConflictSkip, settings.Conflicts)
+
+		require.NoError(t, os.WriteFile(path, []byte("conflicts: merge\n"), 0644))
+		_, err = LoadSettings(path)
+		assert.ErrorContains(t, err, `unknown conflict policy "merge"`)
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "settings.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("answers: [unclosed"), 0644))
+
+		_, err := LoadSettings(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse settings")
+	})
+}
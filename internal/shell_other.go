@@ -0,0 +1,13 @@
+//go:build !windows
+
+package internal
+
+import (
+	"context"
+	"os/exec"
+)
+
+// cmdCommand runs command with a cmd program, which outside Windows is rarely installed
+func cmdCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/S", "/C", command)
+}
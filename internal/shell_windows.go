@@ -0,0 +1,15 @@
+package internal
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
+
+// cmdCommand runs command with cmd.exe. The command line is passed as written, because
+// cmd.exe does not parse arguments with the quoting rules Go applies to them.
+func cmdCommand(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + command + `"`}
+	return cmd
+}
@@ -4,44 +4,61 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 // Resolver handles template source resolution (local paths or git repositories)
-type Resolver struct{}
+type Resolver struct {
+	token string
+	// cacheDir keeps git clones for reuse across runs; empty clones into a temporary directory
+	cacheDir string
+	// fullHistory clones every commit instead of only the latest one
+	fullHistory bool
+	// refresh clones git templates again even when they are cached
+	refresh bool
+	// workCopy hands out a temporary copy of a cached clone, so nothing written to the
+	// template directory, e.g. by hooks, reaches the cache
+	workCopy bool
+}
 
 // NewResolver creates a new source resolver
 func NewResolver() *Resolver {
 	return &Resolver{}
 }
 
-// Resolve resolves a template source and returns the local path and optional cleanup function
+// NewResolverWithToken creates a source resolver that authenticates HTTPS clones with a token
+func NewResolverWithToken(token string) *Resolver {
+	return &Resolver{token: token}
+}
+
+// NewResolverWithCache creates a source resolver that clones git templates into cacheDir once and reuses them
+func NewResolverWithCache(token, cacheDir string) *Resolver {
+	return &Resolver{token: token, cacheDir: cacheDir}
+}
+
+// Resolve resolves a template source and returns the local path and optional cleanup function.
+// A git source may name a template directory inside the repository after a double slash,
+// e.g. gh://org/templates//service.
 func (r *Resolver) Resolve(src string) (string, func(), error) {
 	// Detect git-ish sources
 	if isGitLike(src) {
-		tmp, err := os.MkdirTemp("", "kick-*")
-		if err != nil {
-			return "", nil, err
+		repoSrc, subdir := splitSubdir(src)
+		dir, cleanup, err := r.resolveGit(repoSrc)
+		if err != nil || subdir == "" {
+			return dir, cleanup, err
 		}
-
-		// Best-effort shallow clone
-		_, err = git.PlainClone(tmp, false, &git.CloneOptions{
-			URL:      normalizeGitURL(src),
-			Progress: nil,
-			Depth:    1,
-		})
-		if err != nil {
-			if errors.Is(err, transport.ErrAuthenticationRequired) {
-				return "", func() { _ = os.RemoveAll(tmp) }, fmt.Errorf("git auth required for %s", src)
-			}
-
-			return "", func() { _ = os.RemoveAll(tmp) }, err
-		}
-
-		return tmp, func() { _ = os.RemoveAll(tmp) }, nil
+		path, err := templateSubdir(dir, subdir)
+		return path, cleanup, err
 	}
 
 	// Local path
@@ -57,7 +74,380 @@ func (r *Resolver) Resolve(src string) (string, func(), error) {
 	return src, nil, nil
 }
 
+// resolveGit clones a git source into the cache or, without a cache, into a temporary directory
+func (r *Resolver) resolveGit(src string) (string, func(), error) {
+	if r.cacheDir != "" {
+		dir, err := r.resolveCached(src)
+		if err != nil || !r.workCopy {
+			return dir, nil, err
+		}
+		return copyTemplate(dir)
+	}
+
+	tmp, err := os.MkdirTemp("", "kick-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+
+	if err := r.clone(src, tmp); err != nil {
+		return "", cleanup, err
+	}
+	return tmp, cleanup, nil
+}
+
+// templateSubdir returns the template directory subdir of a cloned repository, checking
+// that it stays inside the clone and holds a template configuration
+func templateSubdir(repoDir, subdir string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(subdir)) {
+		return "", fmt.Errorf("template subdirectory %q must be a relative path inside the repository", subdir)
+	}
+
+	path := filepath.Join(repoDir, filepath.FromSlash(subdir))
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("template subdirectory %s not found in repository", subdir)
+	}
+	for _, name := range []string{KickYAML, CookiecutterJSON} {
+		if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("template subdirectory %s has no %s", subdir, KickYAML)
+}
+
+// resolveCached returns the cached clone of a git source, cloning it on first use or
+// when the resolver refreshes. A failed refresh keeps the old clone.
+func (r *Resolver) resolveCached(src string) (string, error) {
+	dir := cacheEntry(r.cacheDir, src)
+	if _, err := os.Stat(dir); err == nil && !r.refresh {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(r.cacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("create cache directory: %w", err)
+	}
+	// Clone next to the entry and move it into place so an interrupted clone is never reused
+	tmp, err := os.MkdirTemp(r.cacheDir, ".clone-*")
+	if err != nil {
+		return "", err
+	}
+	if err := r.clone(src, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", fmt.Errorf("remove cached template: %w", err)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", fmt.Errorf("store cached template: %w", err)
+	}
+
+	return dir, nil
+}
+
+// copyTemplate copies a cached clone, with its git metadata, into a temporary directory
+func copyTemplate(dir string) (string, func(), error) {
+	tmp, err := os.MkdirTemp("", "kick-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+
+	if err := copyTree(dir, tmp); err != nil {
+		return "", cleanup, fmt.Errorf("copy cached template: %w", err)
+	}
+	return tmp, cleanup, nil
+}
+
+// clone makes a best-effort shallow clone of a git source into dir, at the branch or tag the
+// source names or else the default branch. A source pinned to a commit is cloned in full,
+// since only branch and tag heads can be cloned shallowly, as is every source when the
+// resolver needs the full history.
+func (r *Resolver) clone(src, dir string) error {
+	url := normalizeGitURL(src)
+	_, ref := splitRef(src)
+	_, commit := splitCommit(src)
+
+	opts := &git.CloneOptions{
+		URL:      url,
+		Auth:     r.auth(url),
+		Progress: nil,
+		Depth:    1,
+	}
+	if commit != "" || r.fullHistory {
+		opts.Depth = 0
+	}
+	if commit != "" {
+		opts.NoCheckout = true
+	} else if ref != "" {
+		name, err := r.remoteRef(url, ref)
+		if err != nil {
+			return err
+		}
+		opts.ReferenceName = name
+		opts.SingleBranch = true
+	}
+
+	repo, err := git.PlainClone(dir, false, opts)
+	if errors.Is(err, transport.ErrAuthenticationRequired) {
+		return fmt.Errorf("git auth required for %s", src)
+	}
+	if err != nil || commit == "" {
+		return err
+	}
+	return checkoutCommit(repo, commit, url)
+}
+
+// checkoutCommit checks out a commit of a freshly cloned repository
+func checkoutCommit(repo *git.Repository, commit, url string) error {
+	hash := plumbing.NewHash(commit)
+	if _, err := repo.CommitObject(hash); err != nil {
+		return fmt.Errorf("commit %s is not reachable from any branch or tag of %s", commit, url)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("open git worktree: %w", err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: hash}); err != nil {
+		return fmt.Errorf("checkout %s: %w", commit, err)
+	}
+	return nil
+}
+
+// remoteRef finds the branch or tag called ref among the refs the remote advertises,
+// preferring a branch when both exist
+func (r *Resolver) remoteRef(url, ref string) (plumbing.ReferenceName, error) {
+	refs, err := r.listRefs(url)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref), plumbing.NewTagReferenceName(ref)} {
+		for _, advertised := range refs {
+			if advertised.Name() == name {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("ref %s is not a branch, tag or full commit SHA of %s", ref, url)
+}
+
+// splitRef separates the ref of a git source from the repository, written either as a query
+// naming a branch, tag or commit (?ref=v1.2.0) or as a commit suffix (@<sha>)
+func splitRef(src string) (string, string) {
+	if base, ref, ok := strings.Cut(src, "?ref="); ok && ref != "" {
+		return base, ref
+	}
+	if i := strings.LastIndex(src, "@"); i >= 0 && plumbing.IsHash(src[i+1:]) {
+		return src[:i], src[i+1:]
+	}
+	return src, ""
+}
+
+// splitCommit separates a commit pin from a git source, written either as a query
+// (?ref=<sha>) or a suffix (@<sha>). Only full 40-character SHAs count as pins.
+func splitCommit(src string) (string, string) {
+	if base, ref := splitRef(src); plumbing.IsHash(ref) {
+		return base, ref
+	}
+	return src, ""
+}
+
+// WithRef pins a git source to a branch, tag or commit, replacing any ref it already names
+func WithRef(source, ref string) (string, error) {
+	if ref == "" {
+		return source, nil
+	}
+	if !isGitLike(source) {
+		return "", fmt.Errorf("a ref can only be selected for git templates")
+	}
+	base, _ := splitRef(source)
+	return base + "?ref=" + ref, nil
+}
+
+// splitSubdir separates the template directory of a git source, written after a double slash
+// as in gh://org/templates//service, from the repository. A ref stays on the source.
+func splitSubdir(src string) (string, string) {
+	base, _ := splitRef(src)
+	pin := src[len(base):]
+
+	start := 0
+	if i := strings.Index(base, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	i := strings.Index(base[start:], "//")
+	if i < 0 {
+		return src, ""
+	}
+	i += start
+	return base[:i] + pin, strings.Trim(base[i+2:], "/")
+}
+
+// WithSubdir points a template source at the template in subdir: git sources get it after a
+// double slash, ahead of any commit pin, and local paths are joined with it
+func WithSubdir(source, subdir string) string {
+	if subdir == "" {
+		return source
+	}
+	if !isGitLike(source) {
+		return filepath.Join(source, subdir)
+	}
+	base, _ := splitRef(source)
+	return base + "//" + strings.Trim(subdir, "/") + source[len(base):]
+}
+
+// SourceInfo describes what a template source resolves to
+type SourceInfo struct {
+	URL           string // clone URL of a git source; empty for local templates
+	DefaultBranch string // branch a clone without a ref checks out; empty for local templates
+	Path          string // local template directory; for git sources the cached clone, empty when there is no cache
+}
+
+// ResolveSource resolves a template source the way Generate does, with the cache in dir
+// (CacheDir when empty), and reports where it came from
+func ResolveSource(source, token, dir string) (SourceInfo, error) {
+	resolver := NewResolverWithToken(token)
+	if cacheDir, err := templateCacheDir(dir); err == nil {
+		resolver = NewResolverWithCache(token, cacheDir)
+	}
+
+	var info SourceInfo
+	if isGitLike(source) {
+		branch, err := resolver.DefaultBranch(source)
+		if err != nil {
+			return SourceInfo{}, err
+		}
+		info.URL = normalizeGitURL(source)
+		info.DefaultBranch = branch
+	}
+
+	path, cleanup, err := resolver.Resolve(source)
+	if cleanup != nil {
+		// A temporary clone is gone once we return, so there is no path to report
+		cleanup()
+		path = ""
+	}
+	if err != nil {
+		return SourceInfo{}, fmt.Errorf("resolve template: %v", err)
+	}
+	if path != "" {
+		if path, err = filepath.Abs(path); err != nil {
+			return SourceInfo{}, err
+		}
+	}
+	info.Path = path
+	return info, nil
+}
+
+// DefaultBranch asks the remote of a git source which branch its HEAD points to,
+// which is the branch a clone without a ref checks out
+func (r *Resolver) DefaultBranch(src string) (string, error) {
+	if !isGitLike(src) {
+		return "", fmt.Errorf("default branch is only available for git templates")
+	}
+
+	refs, err := r.listRefs(normalizeGitURL(src))
+	if err != nil {
+		return "", err
+	}
+	return defaultBranch(refs)
+}
+
+// upstreamCommit asks the remote of a git source which commit a fresh clone would check out:
+// the head of the branch or tag the source names, or of the default branch
+func (r *Resolver) upstreamCommit(src string) (string, error) {
+	if _, commit := splitCommit(src); commit != "" {
+		return commit, nil
+	}
+
+	url := normalizeGitURL(src)
+	refs, err := r.listRefs(url)
+	if err != nil {
+		return "", err
+	}
+	var name plumbing.ReferenceName
+	if _, ref := splitRef(src); ref != "" {
+		if name, err = r.remoteRef(url, ref); err != nil {
+			return "", err
+		}
+	} else {
+		branch, err := defaultBranch(refs)
+		if err != nil {
+			return "", err
+		}
+		name = plumbing.NewBranchReferenceName(branch)
+	}
+
+	// An annotated tag is advertised once more, peeled to the commit it tags
+	hash := ""
+	for _, advertised := range refs {
+		switch advertised.Name() {
+		case name + "^{}":
+			return advertised.Hash().String(), nil
+		case name:
+			hash = advertised.Hash().String()
+		}
+	}
+	if hash == "" {
+		return "", fmt.Errorf("ref %s is not advertised by %s", name.Short(), url)
+	}
+	return hash, nil
+}
+
+// listRefs lists the refs a git remote advertises, with annotated tags also peeled
+func (r *Resolver) listRefs(url string) ([]*plumbing.Reference, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{url}})
+	refs, err := remote.List(&git.ListOptions{Auth: r.auth(url), PeelingOption: git.AppendPeeled})
+	if errors.Is(err, transport.ErrAuthenticationRequired) {
+		return nil, fmt.Errorf("git auth required for %s", url)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list remote refs: %w", err)
+	}
+	return refs, nil
+}
+
+// defaultBranch finds the branch HEAD refers to among advertised refs. Servers that do not
+// advertise HEAD as a symbolic ref are matched by hash, preferring main and master.
+func defaultBranch(refs []*plumbing.Reference) (string, error) {
+	var head *plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+			break
+		}
+	}
+	if head == nil {
+		return "", fmt.Errorf("remote has no HEAD")
+	}
+	if head.Type() == plumbing.SymbolicReference {
+		return head.Target().Short(), nil
+	}
+
+	var matches []string
+	for _, ref := range refs {
+		if ref.Name().IsBranch() && ref.Hash() == head.Hash() {
+			matches = append(matches, ref.Name().Short())
+		}
+	}
+	for _, preferred := range []string{"main", "master"} {
+		if slices.Contains(matches, preferred) {
+			return preferred, nil
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no branch matches remote HEAD")
+	}
+	sort.Strings(matches)
+	return matches[0], nil
+}
+
 func isGitLike(s string) bool {
+	s, _ = splitSubdir(s)
+	s, _ = splitRef(s)
 	if strings.HasSuffix(s, ".git") {
 		return true
 	}
@@ -74,13 +464,19 @@ func isGitLike(s string) bool {
 }
 
 func normalizeGitURL(s string) string {
+	s, _ = splitSubdir(s)
+	s, _ = splitRef(s)
 	if after, ok := strings.CutPrefix(s, "gh://"); ok {
-		// gh://owner/repo[/subdir][?ref=branch]
-		rest := after
-		parts := strings.Split(rest, "?")
-		path := parts[0]
-		return "https://github.com/" + strings.TrimSuffix(path, "/")
+		return "https://github.com/" + strings.TrimSuffix(after, "/")
 	}
 
 	return s
 }
+
+// auth returns token credentials for HTTPS URLs, or nil to clone anonymously
+func (r *Resolver) auth(url string) transport.AuthMethod {
+	if r.token == "" || !strings.HasPrefix(url, "https://") {
+		return nil
+	}
+	return &http.BasicAuth{Username: "kick", Password: r.token}
+}
@@ -5,7 +5,11 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -244,3 +248,274 @@ func TestResolver_Resolve_ErrorScenarios(t *testing.T) {
 		assert.Nil(t, cleanup)
 	})
 }
+
+func TestDefaultBranch(t *testing.T) {
+	hash := plumbing.NewHash("1111111111111111111111111111111111111111")
+	other := plumbing.NewHash("2222222222222222222222222222222222222222")
+
+	tests := []struct {
+		name    string
+		refs    []*plumbing.Reference
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "symbolic HEAD",
+			refs: []*plumbing.Reference{
+				plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("trunk")),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("trunk"), hash),
+			},
+			want: "trunk",
+		},
+		{
+			name: "HEAD matched by hash prefers main",
+			refs: []*plumbing.Reference{
+				plumbing.NewHashReference(plumbing.HEAD, hash),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("develop"), hash),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), hash),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("release"), other),
+			},
+			want: "main",
+		},
+		{
+			name: "HEAD matched by hash",
+			refs: []*plumbing.Reference{
+				plumbing.NewHashReference(plumbing.HEAD, hash),
+				plumbing.NewHashReference(plumbing.NewBranchReferenceName("stable"), hash),
+			},
+			want: "stable",
+		},
+		{
+			name:    "no HEAD",
+			refs:    []*plumbing.Reference{plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), hash)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := defaultBranch(tt.refs)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolver_DefaultBranch(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	repo := initRepo(t, repoDir, map[string]string{KickYAML: "name: test\n"})
+	head, err := repo.Head()
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("trunk"), head.Hash())))
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("trunk"))))
+
+	branch, err := NewResolver().DefaultBranch(repoDir)
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", branch)
+
+	_, err = NewResolver().DefaultBranch(t.TempDir())
+	assert.ErrorContains(t, err, "only available for git templates")
+}
+
+func TestSplitCommit(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		src        string
+		wantSource string
+		wantCommit string
+	}{
+		{src: "https://github.com/acme/tpl.git@" + sha, wantSource: "https://github.com/acme/tpl.git", wantCommit: sha},
+		{src: "gh://acme/tpl?ref=" + sha, wantSource: "gh://acme/tpl", wantCommit: sha},
+		{src: "git@github.com:acme/tpl.git@" + sha, wantSource: "git@github.com:acme/tpl.git", wantCommit: sha},
+		{src: "git@github.com:acme/tpl.git", wantSource: "git@github.com:acme/tpl.git"},
+		{src: "gh://acme/tpl?ref=main", wantSource: "gh://acme/tpl?ref=main"},
+		{src: "https://github.com/acme/tpl.git@abc123", wantSource: "https://github.com/acme/tpl.git@abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			source, commit := splitCommit(tt.src)
+			assert.Equal(t, tt.wantSource, source)
+			assert.Equal(t, tt.wantCommit, commit)
+		})
+	}
+}
+
+func TestResolver_ResolveCommit(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	repo := initRepo(t, repoDir, map[string]string{KickYAML: "name: first\n"})
+	first, err := repo.Head()
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, KickYAML), []byte("name: second\n"), 0644))
+	_, err = worktree.Add(KickYAML)
+	require.NoError(t, err)
+	_, err = worktree.Commit("second", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	for _, src := range []string{repoDir + "@" + first.Hash().String(), repoDir + "?ref=" + first.Hash().String()} {
+		path, cleanup, err := NewResolver().Resolve(src)
+		require.NoError(t, err, src)
+		content, err := os.ReadFile(filepath.Join(path, KickYAML))
+		require.NoError(t, err)
+		assert.Equal(t, "name: first\n", string(content), src)
+		cleanup()
+	}
+
+	_, cleanup, err := NewResolver().Resolve(repoDir + "@" + strings.Repeat("f", 40))
+	if cleanup != nil {
+		cleanup()
+	}
+	assert.ErrorContains(t, err, "is not reachable")
+}
+
+func TestSplitSubdir(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		src        string
+		wantSource string
+		wantSubdir string
+	}{
+		{src: "gh://acme/templates//service", wantSource: "gh://acme/templates", wantSubdir: "service"},
+		{src: "https://github.com/acme/templates.git//go/cli/", wantSource: "https://github.com/acme/templates.git", wantSubdir: "go/cli"},
+		{src: "git@github.com:acme/templates.git//service@" + sha, wantSource: "git@github.com:acme/templates.git@" + sha, wantSubdir: "service"},
+		{src: "gh://acme/templates//service?ref=" + sha, wantSource: "gh://acme/templates?ref=" + sha, wantSubdir: "service"},
+		{src: "https://github.com/acme/tpl.git", wantSource: "https://github.com/acme/tpl.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			source, subdir := splitSubdir(tt.src)
+			assert.Equal(t, tt.wantSource, source)
+			assert.Equal(t, tt.wantSubdir, subdir)
+		})
+	}
+}
+
+func TestWithSubdir(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	assert.Equal(t, "gh://acme/templates//service", WithSubdir("gh://acme/templates", "service"))
+	assert.Equal(t, "gh://acme/templates//service@"+sha, WithSubdir("gh://acme/templates@"+sha, "service"))
+	assert.Equal(t, filepath.Join("templates", "service"), WithSubdir("templates", "service"))
+	assert.Equal(t, "gh://acme/tpl", WithSubdir("gh://acme/tpl", ""))
+}
+
+func TestResolver_ResolveSubdir(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "templates.git")
+	initRepo(t, repoDir, map[string]string{
+		"service/" + KickYAML: "name: service\n",
+		"docs/README.md":      "not a template\n",
+	})
+
+	for _, resolver := range []*Resolver{NewResolver(), NewResolverWithCache("", t.TempDir())} {
+		path, cleanup, err := resolver.Resolve(repoDir + "//service")
+		require.NoError(t, err)
+		assert.Equal(t, "service", filepath.Base(path))
+		content, err := os.ReadFile(filepath.Join(path, KickYAML))
+		require.NoError(t, err)
+		assert.Equal(t, "name: service\n", string(content))
+		if cleanup != nil {
+			cleanup()
+		}
+	}
+
+	for sub, want := range map[string]string{
+		"missing": "template subdirectory missing not found",
+		"docs":    "template subdirectory docs has no kick.yaml",
+		"../..":   "must be a relative path inside the repository",
+	} {
+		_, cleanup, err := NewResolver().Resolve(repoDir + "//" + sub)
+		if cleanup != nil {
+			cleanup()
+		}
+		assert.ErrorContains(t, err, want, sub)
+	}
+}
+
+func TestSplitRef(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		src        string
+		wantSource string
+		wantRef    string
+	}{
+		{src: "gh://acme/tpl?ref=v1.2.0", wantSource: "gh://acme/tpl", wantRef: "v1.2.0"},
+		{src: "gh://acme/tpl?ref=" + sha, wantSource: "gh://acme/tpl", wantRef: sha},
+		{src: "https://github.com/acme/tpl.git@" + sha, wantSource: "https://github.com/acme/tpl.git", wantRef: sha},
+		{src: "gh://acme/templates//service?ref=main", wantSource: "gh://acme/templates//service", wantRef: "main"},
+		{src: "git@github.com:acme/tpl.git", wantSource: "git@github.com:acme/tpl.git"},
+		{src: "gh://acme/tpl?ref=", wantSource: "gh://acme/tpl?ref="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			source, ref := splitRef(tt.src)
+			assert.Equal(t, tt.wantSource, source)
+			assert.Equal(t, tt.wantRef, ref)
+		})
+	}
+}
+
+func TestWithRef(t *testing.T) {
+	source, err := WithRef("gh://acme/templates//service", "v1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, "gh://acme/templates//service?ref=v1.2.0", source)
+
+	source, err = WithRef("gh://acme/tpl?ref=main", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "gh://acme/tpl?ref=dev", source)
+
+	_, err = WithRef(t.TempDir(), "main")
+	assert.ErrorContains(t, err, "only be selected for git templates")
+}
+
+func TestResolver_ResolveRef(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	repo := initRepo(t, repoDir, map[string]string{KickYAML: "name: first\n"})
+	first, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.0.0", first.Hash(), nil)
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("next"), Create: true}))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, KickYAML), []byte("name: next\n"), 0644))
+	_, err = worktree.Add(KickYAML)
+	require.NoError(t, err)
+	_, err = worktree.Commit("next", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	for ref, want := range map[string]string{"v1.0.0": "name: first\n", "next": "name: next\n"} {
+		for _, resolver := range []*Resolver{NewResolver(), NewResolverWithCache("", t.TempDir())} {
+			path, cleanup, err := resolver.Resolve(repoDir + "?ref=" + ref)
+			require.NoError(t, err, ref)
+			content, err := os.ReadFile(filepath.Join(path, KickYAML))
+			require.NoError(t, err)
+			assert.Equal(t, want, string(content), ref)
+			if cleanup != nil {
+				cleanup()
+			}
+		}
+	}
+
+	_, cleanup, err := NewResolver().Resolve(repoDir + "?ref=v9")
+	if cleanup != nil {
+		cleanup()
+	}
+	assert.ErrorContains(t, err, "ref v9 is not a branch, tag or full commit SHA")
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// stageOutput creates an empty directory beside outputDir for --atomic-dir. Generation and
+// hooks work in it, and commitOutput moves it into place once everything succeeded.
+// outputDir must not exist yet or be empty, so the move cannot clobber anything.
+func stageOutput(outputDir string) (string, error) {
+	// The parent of a relative "." would be the output directory itself
+	abs, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve output directory: %w", err)
+	}
+	parent := filepath.Dir(abs)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return "", fmt.Errorf("create output parent directory: %w", err)
+	}
+	staged, err := os.MkdirTemp(parent, ".kick-staging-*")
+	if err != nil {
+		return "", fmt.Errorf("create staging directory: %w", err)
+	}
+	return staged, nil
+}
+
+// commitOutput moves a staged output directory to outputDir. An existing, empty outputDir,
+// such as the working directory, is kept and the staged entries are moved into it. When the
+// two lie on different devices the staged tree is copied instead and then removed.
+func commitOutput(staged, outputDir string) error {
+	if info, err := os.Stat(outputDir); err == nil && info.IsDir() {
+		if err := moveEntries(staged, outputDir); err != nil {
+			return fmt.Errorf("move output into place: %w", err)
+		}
+		return os.RemoveAll(staged)
+	}
+
+	err := os.Rename(staged, outputDir)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("move output into place: %w", err)
+	}
+
+	if err := copyTree(staged, outputDir); err != nil {
+		_ = os.RemoveAll(outputDir)
+		return fmt.Errorf("copy output into place: %w", err)
+	}
+	return os.RemoveAll(staged)
+}
+
+// moveEntries moves the entries of the directory src into the existing directory dst
+func moveEntries(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		from, to := filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())
+		err := os.Rename(from, to)
+		if errors.Is(err, syscall.EXDEV) {
+			err = copyTree(from, to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTree copies the directory src to dst, keeping file modes and symbolic links
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyRegular(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+// copyRegular copies one regular file, creating it with mode
+func copyRegular(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
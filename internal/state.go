@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StateFile is the name of the file recording how a project was generated, read by kick update
+const StateFile = ".kick-state.yaml"
+
+// State records the template and answers a project was generated from, so kick update can
+// render the same answers with a newer template
+type State struct {
+	Template string         `yaml:"template"`
+	Version  string         `yaml:"version,omitempty"`
+	Source   string         `yaml:"source"`           // template source without a ref
+	Ref      string         `yaml:"ref,omitempty"`    // branch, tag or commit the template was requested at
+	Commit   string         `yaml:"commit,omitempty"` // commit generated from, when the template is a git repository
+	Answers  map[string]any `yaml:"answers,omitempty"`
+	Only     []string       `yaml:"only,omitempty"` // tags selected with --only
+	Skip     []string       `yaml:"skip,omitempty"` // tags left out with --skip
+	// Files holds the checksums of the generated files, keyed by slash-separated path, so
+	// files edited since can be told apart even without the original template
+	Files map[string]string `yaml:"files,omitempty"`
+}
+
+// newState describes a generation run from source, resolved to templatePath, with the
+// answers it collected. Implicit values and secrets are left out.
+func newState(cfg Config, source, templatePath string, values map[string]any) State {
+	base, ref := splitRef(source)
+	if isGitLike(base) {
+		_, subdir := splitSubdir(base)
+		base = WithSubdir(normalizeGitURL(base), subdir)
+	} else if abs, err := filepath.Abs(base); err == nil {
+		base = abs
+	}
+
+	return State{
+		Template: cfg.Name,
+		Version:  cfg.Version,
+		Source:   base,
+		Ref:      ref,
+		Commit:   headCommit(templatePath),
+		Answers:  storedAnswers(cfg.Variables, values),
+	}
+}
+
+// LoadState reads the state file of the project in dir
+func LoadState(dir string) (State, error) {
+	data, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, fmt.Errorf("no %s in %s; only projects generated by kick can be updated", StateFile, dir)
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("read state: %w", err)
+	}
+
+	var state State
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("parse state: %w", err)
+	}
+	if state.Source == "" {
+		return State{}, fmt.Errorf("%s does not record a template source", StateFile)
+	}
+	return state, nil
+}
+
+// Save writes the state file into dir
+func (s State) Save(dir string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, StateFile), data, 0o644); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Symlink handling selected by TemplateSettings.Symlinks
+const (
+	SymlinksRecreate = "recreate"
+	SymlinksFollow   = "follow"
+)
+
+// recreateLink makes the symbolic link f.srcPath in the output. Its target is rendered like a
+// file name, unless the link is raw, and must stay inside the template and the output.
+func (r *Renderer) recreateLink(srcRoot string, f fileTarget, data map[string]any, settings TemplateSettings) error {
+	dest, err := os.Readlink(f.srcPath)
+	if err != nil {
+		return fmt.Errorf("read symlink: %w", err)
+	}
+	if !localLink(srcRoot, f.srcPath, dest) {
+		return fmt.Errorf("symlink points to %q, outside the template; only relative links inside it are recreated", dest)
+	}
+
+	if f.verbatim == "" {
+		tmpl, err := prepareTemplate(dest, settings)
+		if err != nil {
+			return fmt.Errorf("prepare symlink target: %w", err)
+		}
+		if dest, err = r.renderString(tmpl, data); err != nil {
+			return fmt.Errorf("render symlink target %q: %w", tmpl, err)
+		}
+		if !filepath.IsLocal(filepath.Join(filepath.Dir(f.targetRel), dest)) {
+			return fmt.Errorf("symlink target renders to %q, outside the output directory", dest)
+		}
+	}
+
+	if existing, err := os.Readlink(f.targetPath); err == nil && existing == dest {
+		r.stats.Unchanged++
+		r.explain(f.rel, "unchanged", "the output already has this symlink")
+		r.plan(f.targetRel, PlanUnchanged, "", false)
+		return nil
+	}
+	if r.opts.Merge {
+		if _, err := os.Lstat(f.targetPath); err == nil {
+			if keep, err := r.resolveConflict(f); err != nil || keep {
+				return err
+			}
+		}
+	}
+	r.explain(f.rel, "linked", "-> "+dest)
+	if err := r.planWrite(f); err != nil {
+		return err
+	}
+
+	return r.output(func() error {
+		if err := os.MkdirAll(filepath.Dir(f.targetPath), 0o755); err != nil {
+			return fmt.Errorf("create target directory: %w", err)
+		}
+		if err := os.Remove(f.targetPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err := os.Symlink(dest, f.targetPath); err != nil {
+			return err
+		}
+		r.stats.Changed++
+		return nil
+	})
+}
+
+// localLink reports whether the relative link dest, found at path, points inside srcRoot
+func localLink(srcRoot, path, dest string) bool {
+	if filepath.IsAbs(dest) {
+		return false
+	}
+	rel, err := filepath.Rel(srcRoot, filepath.Join(filepath.Dir(path), dest))
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// resolveLink returns the real path a followed symlink leads to and whether it is a directory.
+// Links that lead outside the template, or to a directory being followed, are rejected.
+func (r *Renderer) resolveLink(srcRoot, path string) (string, bool, error) {
+	root, err := filepath.EvalSymlinks(srcRoot)
+	if err != nil {
+		return "", false, fmt.Errorf("resolve template directory: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false, fmt.Errorf("follow symlink: %w", err)
+	}
+	if !within(root, resolved) {
+		return "", false, fmt.Errorf("symlink leads to %s, outside the template", resolved)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", false, fmt.Errorf("follow symlink: %w", err)
+	}
+	if !info.IsDir() {
+		return resolved, false, nil
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return "", false, fmt.Errorf("follow symlink: %w", err)
+	}
+	if within(resolved, parent) || slices.Contains(r.following, resolved) {
+		return "", false, fmt.Errorf("symlink leads to %s, which contains it", resolved)
+	}
+	return resolved, true, nil
+}
+
+// followDir walks the directory a followed symlink leads to, visiting its entries under the
+// link's relative path rel
+func (r *Renderer) followDir(resolved, rel string, visit func(path, rel string, d fs.DirEntry) error) error {
+	r.following = append(r.following, resolved)
+	defer func() { r.following = r.following[:len(r.following)-1] }()
+
+	return filepath.WalkDir(resolved, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		sub, err := filepath.Rel(resolved, path)
+		if err != nil {
+			return fmt.Errorf("compute relative path: %w", err)
+		}
+		if sub == "." {
+			return nil
+		}
+		return visit(path, filepath.Join(rel, sub), d)
+	})
+}
+
+// within reports whether path is dir or inside it
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && filepath.IsLocal(rel)
+}
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLinkedTemplate creates a template with files and symbolic links, given as name -> target
+func writeLinkedTemplate(t *testing.T, files, links map[string]string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs extra privileges on Windows")
+	}
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	for name, target := range links {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.Symlink(target, path))
+	}
+	return root
+}
+
+func TestRenderer_RecreateSymlinks(t *testing.T) {
+	src := writeLinkedTemplate(t,
+		map[string]string{
+			"docs/{{ .name }}.md": "# {{ .name }}",
+			"shared/config.yaml":  "name: {{ .name }}",
+		},
+		map[string]string{
+			"README.md":         "docs/{{ .name }}.md",
+			"config.yaml":       "shared/config.yaml",
+			"raw.md.kickraw":    "docs/{{ .name }}.md",
+			"shared-dir":        "shared",
+			"{{ .name }}-link":  "docs",
+			"docs/parent-index": "../config.yaml",
+		})
+
+	out := t.TempDir()
+	rend := NewRendererWithOptions(RenderOptions{})
+	require.NoError(t, rend.RenderTreeWithSettings(src, out, map[string]any{"name": "api"}, TemplateSettings{}))
+
+	for name, want := range map[string]string{
+		"README.md":         "docs/api.md",
+		"config.yaml":       "shared/config.yaml",
+		"raw.md":            "docs/{{ .name }}.md",
+		"shared-dir":        "shared",
+		"api-link":          "docs",
+		"docs/parent-index": "../config.yaml",
+	} {
+		dest, err := os.Readlink(filepath.Join(out, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, dest, name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(out, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# api", string(content), "the link resolves in the output")
+	assert.Contains(t, rend.Explanations(), Explanation{Path: "README.md", Outcome: "linked", Reason: "-> docs/api.md"})
+
+	// Rendering again over the output leaves the links alone
+	require.NoError(t, rend.RenderTreeWithSettings(src, out, map[string]any{"name": "api"}, TemplateSettings{}))
+	assert.Contains(t, rend.Explanations(), Explanation{Path: "README.md", Outcome: "unchanged", Reason: "the output already has this symlink"})
+}
+
+func TestRenderer_SymlinksOutsideTemplate(t *testing.T) {
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("secret"), 0o644))
+
+	tests := []struct {
+		name     string
+		links    map[string]string
+		symlinks string
+		wantErr  string
+	}{
+		{
+			name:    "absolute link",
+			links:   map[string]string{"secret.txt": outside},
+			wantErr: "outside the template",
+		},
+		{
+			name:    "relative link escaping the template",
+			links:   map[string]string{"secret.txt": "../../etc/passwd"},
+			wantErr: "outside the template",
+		},
+		{
+			name:    "target rendering outside the output",
+			links:   map[string]string{"link": "{{ .up }}/etc/passwd"},
+			wantErr: "outside the output directory",
+		},
+		{
+			name:     "followed link leading outside the template",
+			links:    map[string]string{"secret.txt": outside},
+			symlinks: SymlinksFollow,
+			wantErr:  "outside the template",
+		},
+		{
+			name:     "followed link to a parent directory",
+			links:    map[string]string{"docs/loop": ".."},
+			symlinks: SymlinksFollow,
+			wantErr:  "which contains it",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := writeLinkedTemplate(t, map[string]string{"docs/index.md": "docs"}, tt.links)
+			out := t.TempDir()
+			err := NewRendererWithOptions(RenderOptions{}).RenderTreeWithSettings(src, out, map[string]any{"up": "../.."}, TemplateSettings{Symlinks: tt.symlinks})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.NoFileExists(t, filepath.Join(out, "secret.txt"))
+		})
+	}
+}
+
+func TestRenderer_FollowSymlinks(t *testing.T) {
+	src := writeLinkedTemplate(t,
+		map[string]string{
+			"shared/config.yaml":    "name: {{ .name }}",
+			"shared/nested/note.md": "{{ .name }} notes",
+		},
+		map[string]string{
+			"config.yaml":         "shared/config.yaml",
+			"{{ .name }}":         "shared",
+			"shared/nested/again": "../config.yaml",
+		})
+
+	out := t.TempDir()
+	rend := NewRendererWithOptions(RenderOptions{})
+	require.NoError(t, rend.RenderTreeWithSettings(src, out, map[string]any{"name": "api"}, TemplateSettings{Symlinks: SymlinksFollow}))
+
+	for name, want := range map[string]string{
+		"config.yaml":           "name: api",
+		"api/config.yaml":       "name: api",
+		"api/nested/note.md":    "api notes",
+		"api/nested/again":      "name: api",
+		"shared/nested/again":   "name: api",
+		"shared/nested/note.md": "api notes",
+		"shared/config.yaml":    "name: api",
+	} {
+		info, err := os.Lstat(filepath.Join(out, name))
+		require.NoError(t, err, name)
+		assert.True(t, info.Mode().IsRegular(), "%s is a regular file", name)
+		content, err := os.ReadFile(filepath.Join(out, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(content), name)
+	}
+	assert.Contains(t, rend.Manifest().Files, "api/nested/note.md")
+}
@@ -0,0 +1,378 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// UpdateOptions configures Update
+type UpdateOptions struct {
+	Dir string // project generated by kick, with a state file
+	// Ref is the template branch, tag or commit to update to; empty keeps the ref the
+	// project was generated from, or the default branch when it had none
+	Ref string
+	// Answers holds raw command-line answers that replace the recorded ones
+	Answers map[string]string
+	// SettingsAnswers holds raw answers from the user settings file, for variables the
+	// recorded answers do not cover
+	SettingsAnswers map[string]string
+	// DryRun reports what would change without writing anything
+	DryRun        bool
+	GitToken      string
+	CacheDir      string // directory of cloned git templates; empty uses CacheDir()
+	PromptStyle   string
+	PromptTimeout time.Duration
+}
+
+// UpdateReport sorts the files of an update by what happened to them. Paths are
+// slash-separated and relative to the project.
+type UpdateReport struct {
+	Updated   []string // files the project left as generated, replaced with the new output
+	Merged    []string // files the project and the template both changed, merged cleanly
+	Conflicts []string // files both changed in the same place: text files get conflict markers, others keep the project's version
+	Created   []string // files the template added
+	Removed   []string // files the template dropped that the project left as generated
+	Kept      []string // files the template dropped that the project changed, left alone
+}
+
+// Empty reports whether the update changed nothing
+func (r UpdateReport) Empty() bool {
+	return len(r.Updated)+len(r.Merged)+len(r.Conflicts)+len(r.Created)+len(r.Removed)+len(r.Kept) == 0
+}
+
+// Update renders the template recorded in a project's state file again, at opts.Ref, with the
+// recorded answers, and merges the result into the project. Changes made in the project are kept:
+// each file is merged three ways with the output of the template the project was generated from.
+// Only variables without a recorded answer are prompted, and no hooks run.
+func Update(opts UpdateOptions) (UpdateReport, error) {
+	state, err := LoadState(opts.Dir)
+	if err != nil {
+		return UpdateReport{}, err
+	}
+	if err := validPromptStyle(opts.PromptStyle); err != nil {
+		return UpdateReport{}, err
+	}
+
+	ref := opts.Ref
+	if ref == "" {
+		ref = state.Ref
+	}
+	source, err := WithRef(state.Source, ref)
+	if err != nil {
+		return UpdateReport{}, err
+	}
+
+	work, err := os.MkdirTemp("", "kick-update-")
+	if err != nil {
+		return UpdateReport{}, fmt.Errorf("create work directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(work) }()
+
+	// Always fetch the template again; the cached copy may be older than the project
+	resolver := NewResolverWithToken(opts.GitToken)
+	if cacheDir, err := templateCacheDir(opts.CacheDir); err == nil {
+		resolver = NewResolverWithCache(opts.GitToken, cacheDir)
+		resolver.refresh = true
+	}
+	templatePath, cleanup, err := resolver.Resolve(source)
+	if err != nil {
+		return UpdateReport{}, fmt.Errorf("resolve template: %v", err)
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+	cfg, err := loadConfig(templatePath)
+	if err != nil {
+		return UpdateReport{}, err
+	}
+
+	values, err := updateValues(cfg, state, opts)
+	if err != nil {
+		return UpdateReport{}, err
+	}
+	newDir := filepath.Join(work, "new")
+	rendered, err := renderScratch(templatePath, cfg, source, values, newDir, state.Only, state.Skip)
+	if err != nil {
+		return UpdateReport{}, fmt.Errorf("render template: %w", err)
+	}
+
+	u := updater{dir: opts.Dir, newDir: newDir, dryRun: opts.DryRun}
+	if state.Commit != "" {
+		u.baseDir = filepath.Join(work, "base")
+		if u.previous, err = renderBase(state, values, u.baseDir, opts.GitToken, opts.CacheDir); err != nil {
+			return UpdateReport{}, err
+		}
+	} else {
+		// Without the original template only recorded checksums tell generated files from edited ones
+		warn("%s records no template commit, so files changed since generation cannot be merged and are marked as conflicts", StateFile)
+		if u.previous, err = LoadManifest(opts.Dir); err != nil {
+			return UpdateReport{}, err
+		}
+	}
+	// The checksums recorded when generating describe the project exactly, even when the
+	// template had uncommitted changes then
+	if state.Files != nil {
+		u.previous = Manifest{Files: state.Files}
+	}
+
+	if err := u.apply(rendered); err != nil {
+		return u.report, err
+	}
+	if opts.DryRun {
+		return u.report, nil
+	}
+
+	// Record the new template version and answers for the next update
+	next := newState(cfg, source, templatePath, values)
+	next.Only, next.Skip, next.Files = state.Only, state.Skip, rendered.Files
+	if err := next.Save(opts.Dir); err != nil {
+		return u.report, err
+	}
+	if _, err := os.Stat(filepath.Join(opts.Dir, ManifestFile)); err == nil {
+		if err := rendered.Save(opts.Dir); err != nil {
+			return u.report, err
+		}
+	}
+	if _, err := os.Stat(filepath.Join(opts.Dir, AnswersFile)); err == nil {
+		if err := saveAnswers(opts.Dir, cfg.Variables, values); err != nil {
+			return u.report, err
+		}
+	}
+	return u.report, nil
+}
+
+// updateValues collects the values to render the new template with: the recorded answers,
+// overridden by command-line answers, and prompts for variables neither covers
+func updateValues(cfg Config, state State, opts UpdateOptions) (map[string]any, error) {
+	settingsAnswers, err := coerceAnswers(cfg.Variables, opts.SettingsAnswers)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := coerceAnswers(cfg.Variables, opts.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := make(map[string]any)
+	for _, layer := range []map[string]any{
+		implicitValues(opts.Dir),
+		settingsAnswers,
+		envSecrets(cfg.Variables),
+		coerceFileAnswers(cfg.Variables, state.Answers),
+		answers,
+	} {
+		maps.Copy(seed, layer)
+	}
+
+	variables, warnings := environmentDefaults(cfg.Variables)
+	for _, msg := range warnings {
+		warn("%s", msg)
+	}
+
+	prompt := newPrompter(opts.PromptStyle, opts.PromptTimeout)
+	values, _, err := collectValues(variables, cfg.GetVariableOrder(), seed, collectOptions{overlays: cfg.Overlays, prompt: prompt})
+	if err != nil {
+		return nil, fmt.Errorf("collect values: %w", err)
+	}
+	return values, nil
+}
+
+// renderBase renders the template at the commit the project was generated from into dir, with the
+// answers recorded then, and returns the hashes of what it produced. A local template is read
+// from the git repository it lives in.
+func renderBase(state State, values map[string]any, dir, token, cacheDir string) (Manifest, error) {
+	source := state.Source
+	var templatePath string
+	var cleanup func()
+	var err error
+	if isGitLike(state.Source) {
+		if source, err = WithRef(state.Source, state.Commit); err != nil {
+			return Manifest{}, err
+		}
+		resolver := NewResolverWithToken(token)
+		if cacheDir, err := templateCacheDir(cacheDir); err == nil {
+			resolver = NewResolverWithCache(token, cacheDir)
+		}
+		templatePath, cleanup, err = resolver.Resolve(source)
+	} else {
+		templatePath, cleanup, err = checkoutLocal(state.Source, state.Commit)
+	}
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return Manifest{}, fmt.Errorf("resolve generated template version: %v", err)
+	}
+	cfg, err := loadConfig(templatePath)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("generated template version: %w", err)
+	}
+
+	// Secrets and variables the recorded answers lack take the values collected for the update
+	baseValues := maps.Clone(values)
+	maps.Copy(baseValues, coerceFileAnswers(cfg.Variables, state.Answers))
+	manifest, err := renderScratch(templatePath, cfg, source, baseValues, dir, state.Only, state.Skip)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("render generated template version: %w", err)
+	}
+	return manifest, nil
+}
+
+// renderScratch renders a template with the given values and tag selection into an empty
+// directory, without hooks, and returns the hashes of the files it produced
+func renderScratch(templatePath string, cfg Config, source string, values map[string]any, dir string, only, skip []string) (Manifest, error) {
+	renderPath, err := renderRoot(templatePath, cfg.Template)
+	if err != nil {
+		return Manifest{}, err
+	}
+	data := renderData(cfg, values)
+	header, err := renderHeader(cfg, source, data)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	rend := NewRendererWithOptions(RenderOptions{Header: header, Only: only, Skip: skip})
+	if err := rend.RenderTreeWithSettings(renderPath, dir, data, cfg.Template); err != nil {
+		return Manifest{}, err
+	}
+	return rend.Manifest(), nil
+}
+
+// updater merges the output of the new template into a project
+type updater struct {
+	dir     string // the project
+	newDir  string // output of the new template
+	baseDir string // output of the template the project was generated from; empty when unavailable
+	// previous holds the hashes of the files generated last time: the base output, or the
+	// project's manifest when there is no base
+	previous Manifest
+	dryRun   bool
+	report   UpdateReport
+}
+
+// apply merges every file of the new output into the project, then removes the files the
+// template no longer generates
+func (u *updater) apply(rendered Manifest) error {
+	for _, rel := range sortedKeys(rendered.Files) {
+		if err := u.applyFile(rel); err != nil {
+			return fmt.Errorf("update %s: %w", rel, err)
+		}
+	}
+
+	for _, rel := range sortedKeys(u.previous.Files) {
+		if _, ok := rendered.Files[rel]; ok {
+			continue
+		}
+		path := filepath.Join(u.dir, filepath.FromSlash(rel))
+		current, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("update %s: %w", rel, err)
+		}
+		if !u.generated(rel, current) {
+			u.report.Kept = append(u.report.Kept, rel)
+			continue
+		}
+		u.report.Removed = append(u.report.Removed, rel)
+		if !u.dryRun {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("update %s: %w", rel, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyFile brings one file of the new output into the project
+func (u *updater) applyFile(rel string) error {
+	newPath := filepath.Join(u.newDir, filepath.FromSlash(rel))
+	latest, err := os.ReadFile(newPath)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(newPath)
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(filepath.Join(u.dir, filepath.FromSlash(rel)))
+	if errors.Is(err, os.ErrNotExist) {
+		if base, ok := u.base(rel); ok && bytes.Equal(base, latest) {
+			// Deleted in the project and unchanged in the template
+			return nil
+		}
+		u.report.Created = append(u.report.Created, rel)
+		return u.write(rel, latest, info.Mode())
+	}
+	if err != nil {
+		return err
+	}
+
+	base, _ := u.base(rel)
+	switch {
+	case bytes.Equal(current, latest):
+		return nil
+	case u.generated(rel, current):
+		u.report.Updated = append(u.report.Updated, rel)
+		return u.write(rel, latest, info.Mode())
+	case u.baseDir != "" && bytes.Equal(base, latest):
+		// Only the project changed the file
+		return nil
+	case isBinary(current) || isBinary(latest):
+		u.report.Conflicts = append(u.report.Conflicts, rel)
+		return nil
+	}
+
+	merged, conflicts := merge3(string(base), string(current), string(latest))
+	if conflicts > 0 {
+		u.report.Conflicts = append(u.report.Conflicts, rel)
+	} else {
+		u.report.Merged = append(u.report.Merged, rel)
+	}
+	return u.write(rel, []byte(merged), info.Mode())
+}
+
+// base returns what the template the project was generated from produced for rel
+func (u *updater) base(rel string) ([]byte, bool) {
+	if u.baseDir == "" {
+		return nil, false
+	}
+	content, err := os.ReadFile(filepath.Join(u.baseDir, filepath.FromSlash(rel)))
+	return content, err == nil
+}
+
+// generated reports whether content is still what kick generated for rel last time
+func (u *updater) generated(rel string, content []byte) bool {
+	previous, ok := u.previous.Files[rel]
+	return ok && previous == hashContent(content)
+}
+
+// write stores content in the project, unless this is a dry run
+func (u *updater) write(rel string, content []byte, mode os.FileMode) error {
+	if u.dryRun {
+		return nil
+	}
+	path := filepath.Join(u.dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, mode.Perm())
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
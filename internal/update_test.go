@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repoDir := filepath.Join(t.TempDir(), "template.git")
+	repo := initRepo(t, repoDir, map[string]string{
+		KickYAML:     "name: svc\nvariables:\n  name:\n    type: string\n    default: app\n",
+		"README.md":  "# {{.name}}\n\nintro\n\nbody\n\nfooter\n",
+		"config.txt": "port: 80\n",
+		"other.txt":  "v1\n",
+		"old.txt":    "legacy\n",
+		"keep.txt":   "keep\n",
+	})
+
+	out := t.TempDir()
+	require.NoError(t, Generate(Options{Source: repoDir, OutputDir: out, Answers: map[string]string{"name": "api"}}))
+	state, err := LoadState(out)
+	require.NoError(t, err)
+	assert.Equal(t, repoDir, state.Source)
+	assert.Equal(t, map[string]any{"name": "api"}, state.Answers)
+	assert.Len(t, state.Commit, 40)
+
+	// Edit the project
+	for name, content := range map[string]string{
+		"README.md":  "# api\n\nmy intro\n\nbody\n\nfooter\n",
+		"config.txt": "port: 8080\n",
+		"keep.txt":   "kept locally\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(out, name), []byte(content), 0644))
+	}
+
+	// Release a new template version
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range map[string]string{
+		KickYAML:     "name: svc\nvariables:\n  name:\n    type: string\n    default: app\n  license:\n    type: string\n    default: MIT\n",
+		"README.md":  "# {{.name}}\n\nintro\n\nbody\n\nnew footer\n",
+		"config.txt": "port: 81\n",
+		"other.txt":  "v2\n",
+		"new.txt":    "{{.license}}\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	for _, name := range []string{"old.txt", "keep.txt"} {
+		_, err = worktree.Remove(name)
+		require.NoError(t, err)
+	}
+	_, err = worktree.Commit("v2", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	planned, err := Update(UpdateOptions{Dir: out, DryRun: true})
+	require.NoError(t, err)
+	dryRunState, err := LoadState(out)
+	require.NoError(t, err)
+	assert.Equal(t, state, dryRunState, "a dry run writes nothing")
+
+	report, err := Update(UpdateOptions{Dir: out})
+	require.NoError(t, err)
+	assert.Equal(t, planned, report)
+	assert.Equal(t, UpdateReport{
+		Updated:   []string{"other.txt"},
+		Merged:    []string{"README.md"},
+		Conflicts: []string{"config.txt"},
+		Created:   []string{"new.txt"},
+		Removed:   []string{"old.txt"},
+		Kept:      []string{"keep.txt"},
+	}, report)
+
+	for name, want := range map[string]string{
+		"README.md":  "# api\n\nmy intro\n\nbody\n\nnew footer\n",
+		"config.txt": "<<<<<<< current\nport: 8080\n=======\nport: 81\n>>>>>>> template\n",
+		"other.txt":  "v2\n",
+		"new.txt":    "MIT\n",
+		"keep.txt":   "kept locally\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(out, name))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content), name)
+	}
+	assert.NoFileExists(t, filepath.Join(out, "old.txt"))
+
+	updated, err := LoadState(out)
+	require.NoError(t, err)
+	assert.NotEqual(t, state.Commit, updated.Commit)
+	assert.Equal(t, map[string]any{"name": "api", "license": "MIT"}, updated.Answers)
+
+	report, err = Update(UpdateOptions{Dir: out})
+	require.NoError(t, err)
+	assert.True(t, report.Empty(), "the project is up to date with the template")
+}
+
+func TestUpdate_LocalTemplate(t *testing.T) {
+	repoDir := filepath.Join(t.TempDir(), "template")
+	repo := initRepo(t, repoDir, map[string]string{
+		KickYAML:        "name: svc\ntemplate:\n  tags:\n    docs: [docs]\n",
+		"README.md":     "intro\n\nbody\n\nfooter\n",
+		"docs/guide.md": "guide\n",
+	})
+
+	out := t.TempDir()
+	require.NoError(t, Generate(Options{Source: repoDir, OutputDir: out, Skip: []string{"docs"}}))
+	state, err := LoadState(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, state.Skip)
+	assert.Contains(t, state.Files, "README.md")
+	require.NoError(t, os.WriteFile(filepath.Join(out, "README.md"), []byte("my intro\n\nbody\n\nfooter\n"), 0644))
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range map[string]string{"README.md": "intro\n\nbody\n\nnew footer\n", "docs/guide.md": "new guide\n"} {
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = worktree.Commit("v2", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	report, err := Update(UpdateOptions{Dir: out})
+	require.NoError(t, err)
+	assert.Equal(t, UpdateReport{Merged: []string{"README.md"}}, report, "the base is rendered from the local repository")
+	content, err := os.ReadFile(filepath.Join(out, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "my intro\n\nbody\n\nnew footer\n", string(content))
+	assert.NoDirExists(t, filepath.Join(out, "docs"), "skipped tags stay skipped")
+
+	updated, err := LoadState(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, updated.Skip)
+}
+
+func TestUpdate_NoState(t *testing.T) {
+	_, err := Update(UpdateOptions{Dir: t.TempDir()})
+	assert.ErrorContains(t, err, "no .kick-state.yaml")
+}
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/kick-cli/kick/internal"
+	"github.com/yarlson/tap"
 )
 
 func main() {
@@ -13,37 +20,915 @@ func main() {
 		return
 	}
 
+	if shell, ok := strings.CutPrefix(os.Args[1], "--shell-completion="); ok {
+		runShellCompletion([]string{shell})
+		return
+	}
+
+	switch os.Args[1] {
+	case "--shell-completion":
+		runShellCompletion(os.Args[2:])
+		return
+	case "init":
+		runInit(os.Args[2:])
+		return
+	case "lint":
+		runLint(os.Args[2:])
+		return
+	case "verify":
+		runVerify(os.Args[2:])
+		return
+	case "changelog":
+		runChangelog(os.Args[2:])
+		return
+	case "render":
+		runRender(os.Args[2:])
+		return
+	case "functions":
+		runFunctions(os.Args[2:])
+		return
+	case "docs":
+		runDocs(os.Args[2:])
+		return
+	case "cache":
+		runCache(os.Args[2:])
+		return
+	case "update":
+		runUpdate(os.Args[2:])
+		return
+	case "list":
+		runList(os.Args[2:])
+		return
+	case "add":
+		runAdd(os.Args[2:])
+		return
+	case "remove":
+		runRemove(os.Args[2:])
+		return
+	}
+
 	// Parse command line arguments
-	opts := internal.Options{
-		Source:    os.Args[1],
-		OutputDir: ".",
+	opts, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if opts.TemplateVersion {
+		runTemplateVersion(opts.Options)
+		return
 	}
-	if len(os.Args) >= 3 {
-		opts.OutputDir = os.Args[2]
+	if opts.ResolveOnly {
+		runResolveOnly(opts.Options)
+		return
 	}
 
-	if err := internal.Generate(opts); err != nil {
+	if err := internal.Generate(opts.Options); err != nil {
+		// Ctrl+C at a prompt exits quietly with the conventional status
+		if errors.Is(err, internal.ErrCancelled) {
+			os.Exit(130)
+		}
 		fatal("generate template: %v", err)
 	}
 }
 
+// runShellCompletion prints the completion script for the requested shell.
+func runShellCompletion(args []string) {
+	if len(args) != 1 {
+		fatal("shell-completion: expected one of bash, zsh or fish")
+	}
+	if err := writeCompletion(os.Stdout, args[0]); err != nil {
+		fatal("shell-completion: %v", err)
+	}
+}
+
+// runInit creates a skeleton template for template authors to start from.
+func runInit(args []string) {
+	dir := "."
+	switch len(args) {
+	case 0:
+	case 1:
+		dir = args[0]
+	default:
+		fatal("init: expected at most one template directory")
+	}
+
+	if err := internal.InitTemplate(dir); err != nil {
+		fatal("init: %v", err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "✓ created template in %s\n  try it: kick %s ./out\n", dir, dir)
+}
+
+// runLint checks a template for authoring mistakes and exits non-zero when any are found.
+func runLint(args []string) {
+	if len(args) != 1 {
+		fatal("lint: expected exactly one template source")
+	}
+
+	findings, err := internal.Lint(args[0])
+	if err != nil {
+		fatal("lint: %v", err)
+	}
+
+	if len(findings) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "✓ no problems found")
+		return
+	}
+	for _, finding := range findings {
+		_, _ = fmt.Fprintf(os.Stdout, "⚠ %s\n", finding)
+	}
+	os.Exit(1)
+}
+
+// runVerify reports generated files that were modified or removed since generation.
+func runVerify(args []string) {
+	dir := "."
+	switch len(args) {
+	case 0:
+	case 1:
+		dir = args[0]
+	default:
+		fatal("verify: expected at most one output directory")
+	}
+
+	changes, err := internal.Verify(dir)
+	if err != nil {
+		fatal("verify: %v", err)
+	}
+
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "✓ all generated files match the manifest")
+		return
+	}
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(os.Stdout, "%-9s %s\n", change.Status+":", change.Path)
+	}
+	os.Exit(1)
+}
+
+// runChangelog reports the variables added, removed or changed between two versions of a template.
+func runChangelog(args []string) {
+	var configPath string
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&configPath, "config", "", "")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			fatal("changelog: %v", err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 2 {
+		fatal("changelog: expected an old and a new template source")
+	}
+
+	settings, err := internal.LoadSettings(configPath)
+	if err != nil {
+		fatal("changelog: %v", err)
+	}
+	changes, err := internal.Changelog(positional[0], positional[1], settings.GitToken)
+	if err != nil {
+		fatal("changelog: %v", err)
+	}
+
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "✓ variables are unchanged")
+		return
+	}
+	symbols := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+	for _, change := range changes {
+		line := fmt.Sprintf("%s %-8s %s", symbols[change.Kind], change.Kind, change.Name)
+		if len(change.Details) > 0 {
+			line += ": " + strings.Join(change.Details, "; ")
+		}
+		_, _ = fmt.Fprintln(os.Stdout, line)
+	}
+}
+
+// runDocs prints a table of a template's variables, e.g. for the template's README.
+func runDocs(args []string) {
+	format := internal.DocsFormatMarkdown
+	var configPath string
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&configPath, "config", "", "")
+	fs.StringVar(&format, "format", format, "")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			fatal("docs: %v", err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fatal("docs: expected exactly one template source")
+	}
+
+	settings, err := internal.LoadSettings(configPath)
+	if err != nil {
+		fatal("docs: %v", err)
+	}
+	out, err := internal.VariableDocs(positional[0], settings.GitToken, format)
+	if err != nil {
+		fatal("docs: %v", err)
+	}
+	_, _ = fmt.Fprint(os.Stdout, out)
+}
+
+// runCache lists or removes cached git templates.
+func runCache(args []string) {
+	var configPath string
+	fs := flag.NewFlagSet("cache", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&configPath, "config", "", "")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			fatal("cache: %v", err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	args = positional
+	if len(args) == 0 {
+		fatal("cache: expected list or clean")
+	}
+
+	settings, err := internal.LoadSettings(configPath)
+	if err != nil {
+		fatal("cache: %v", err)
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			fatal("cache list: unexpected argument %q", args[1])
+		}
+		cached, err := internal.ListCache(settings.CacheDir)
+		if err != nil {
+			fatal("cache list: %v", err)
+		}
+		if len(cached) == 0 {
+			_, _ = fmt.Fprintln(os.Stdout, "no cached templates")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "URL\tREF\tCOMMIT\tSIZE\tFETCHED")
+		for _, c := range cached {
+			ref, commit := c.Ref, c.Commit
+			if ref == "" {
+				ref = "-"
+			}
+			if len(commit) > 7 {
+				commit = commit[:7]
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.URL, ref, commit, formatSize(c.Size), c.Fetched.Format("2006-01-02 15:04"))
+		}
+		_ = w.Flush()
+	case "clean":
+		if len(args) > 2 {
+			fatal("cache clean: expected at most one template source")
+		}
+		var source string
+		if len(args) == 2 {
+			source = args[1]
+		}
+		removed, err := internal.CleanCache(settings.CacheDir, source)
+		if err != nil {
+			fatal("cache clean: %v", err)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "✓ removed %d cached %s\n", removed, pluralize(removed, "template", "templates"))
+	default:
+		fatal("cache: unknown command %q, expected list or clean", args[0])
+	}
+}
+
+// runList prints the templates in the registry.
+func runList(args []string) {
+	if len(args) > 0 {
+		fatal("list: unexpected argument %q", args[0])
+	}
+
+	templates, err := internal.ListTemplates()
+	if err != nil {
+		fatal("list: %v", err)
+	}
+	if len(templates) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "no registered templates; add one with kick add <name> <template>")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tTEMPLATE")
+	for _, t := range templates {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", t.Name, t.Source)
+	}
+	_ = w.Flush()
+}
+
+// runAdd registers a template source under a short name.
+func runAdd(args []string) {
+	if len(args) != 2 {
+		fatal("add: expected a name and a template source")
+	}
+
+	if err := internal.AddTemplate(args[0], args[1]); err != nil {
+		fatal("add: %v", err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "✓ added %s\n", args[0])
+}
+
+// runRemove removes a template from the registry.
+func runRemove(args []string) {
+	if len(args) != 1 {
+		fatal("remove: expected exactly one template name")
+	}
+
+	if err := internal.RemoveTemplate(args[0]); err != nil {
+		fatal("remove: %v", err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "✓ removed %s\n", args[0])
+}
+
+// runUpdate merges a newer version of a project's template into the project.
+func runUpdate(args []string) {
+	opts := internal.UpdateOptions{Dir: ".", Answers: map[string]string{}}
+	flagAnswers := answerFlags{}
+	var configPath string
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(flagAnswers, "answer", "")
+	fs.StringVar(&configPath, "config", "", "")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "")
+	fs.StringVar(&opts.PromptStyle, "prompt-style", "", "")
+	fs.DurationVar(&opts.PromptTimeout, "prompt-timeout", 0, "")
+	fs.StringVar(&opts.Ref, "ref", "", "")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			fatal("update: %v", err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	dirSet := false
+	for _, arg := range positional {
+		if strings.Contains(arg, "=") {
+			key, value, err := internal.ParseAnswer(arg)
+			if err != nil {
+				fatal("update: %v", err)
+			}
+			opts.Answers[key] = value
+			continue
+		}
+		if dirSet {
+			fatal("update: unexpected argument %q", arg)
+		}
+		opts.Dir = arg
+		dirSet = true
+	}
+	for key, value := range flagAnswers {
+		opts.Answers[key] = value
+	}
+
+	settings, err := internal.LoadSettings(configPath)
+	if err != nil {
+		fatal("update: %v", err)
+	}
+	opts.GitToken = settings.GitToken
+	opts.CacheDir = settings.CacheDir
+	opts.SettingsAnswers = settings.Answers
+	if opts.PromptStyle == "" {
+		opts.PromptStyle = settings.PromptStyle
+	}
+
+	report, err := internal.Update(opts)
+	if errors.Is(err, internal.ErrCancelled) {
+		os.Exit(130)
+	}
+	if err != nil {
+		fatal("update: %v", err)
+	}
+
+	if report.Empty() {
+		_, _ = fmt.Fprintln(os.Stdout, "✓ already up to date")
+		return
+	}
+	for _, group := range []struct {
+		label string
+		files []string
+	}{
+		{"created", report.Created},
+		{"updated", report.Updated},
+		{"merged", report.Merged},
+		{"removed", report.Removed},
+		{"kept", report.Kept},
+		{"conflict", report.Conflicts},
+	} {
+		for _, file := range group.files {
+			_, _ = fmt.Fprintf(os.Stdout, "%-9s %s\n", group.label+":", file)
+		}
+	}
+	if len(report.Conflicts) > 0 {
+		fatal("update: %d %s changed in both the project and the template; resolve the conflicts listed above",
+			len(report.Conflicts), pluralize(len(report.Conflicts), "file", "files"))
+	}
+}
+
+// runResolveOnly prints where the template source resolves to without generating anything.
+func runResolveOnly(opts internal.Options) {
+	info, err := internal.ResolveSource(opts.Source, opts.GitToken, opts.CacheDir)
+	if err != nil {
+		fatal("resolve: %v", err)
+	}
+
+	if info.URL != "" {
+		_, _ = fmt.Fprintf(os.Stdout, "url:            %s\ndefault branch: %s\n", info.URL, info.DefaultBranch)
+	}
+	if info.Path != "" {
+		_, _ = fmt.Fprintf(os.Stdout, "path:           %s\n", info.Path)
+	}
+}
+
+// runRender renders a template string with the given answers and prints the result.
+func runRender(args []string) {
+	answers := answerFlags{}
+	var answersPath string
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(answers, "answer", "")
+	fs.StringVar(&answersPath, "answers", "", "")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			fatal("render: %v", err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) == 0 {
+		fatal("render: expected a template string")
+	}
+
+	data := map[string]any{}
+	if answersPath != "" {
+		values, err := internal.LoadAnswersFile(answersPath)
+		if err != nil {
+			fatal("render: %v", err)
+		}
+		data = values
+	}
+	// Positional key=value answers, then --answer, override the answers file
+	for _, arg := range positional[1:] {
+		key, value, err := internal.ParseAnswer(arg)
+		if err != nil {
+			fatal("render: %v", err)
+		}
+		data[key] = value
+	}
+	for key, value := range answers {
+		data[key] = value
+	}
+
+	out, err := internal.RenderString(positional[0], data)
+	if err != nil {
+		fatal("render: %v", err)
+	}
+	_, _ = fmt.Fprintln(os.Stdout, out)
+}
+
+// runFunctions lists the functions available in templates.
+func runFunctions(args []string) {
+	if len(args) > 0 {
+		fatal("functions: unexpected argument %q", args[0])
+	}
+	for _, fn := range internal.Functions() {
+		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n    %s\n    %s\n", fn.Name, fn.Signature, fn.Description, fn.Example)
+	}
+}
+
+// runTemplateVersion compares the cached copy of a git template with upstream and offers to refresh it.
+func runTemplateVersion(opts internal.Options) {
+	status, err := internal.CheckTemplateVersion(opts.Source, opts.GitToken, opts.CacheDir)
+	if err != nil {
+		fatal("template version: %v", err)
+	}
+
+	cached := status.Cached
+	if cached == "" {
+		cached = "not cached"
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "cached:   %s\nupstream: %s\n", cached, status.Upstream)
+
+	if !status.Outdated() {
+		return
+	}
+	refresh := tap.Confirm(tap.ConfirmOptions{
+		Message:      "The cached template is outdated. Refresh it?",
+		Active:       "Yes",
+		Inactive:     "No",
+		InitialValue: true,
+	})
+	if !refresh {
+		return
+	}
+	if err := internal.RefreshTemplate(opts.Source, opts.GitToken, opts.CacheDir); err != nil {
+		fatal("refresh template: %v", err)
+	}
+	_, _ = fmt.Fprintf(os.Stdout, "✓ cached template updated to %s\n", status.Upstream)
+}
+
+// answerFlags collects repeated --answer key=value flags.
+type answerFlags map[string]string
+
+func (a answerFlags) String() string { return fmt.Sprint(map[string]string(a)) }
+
+func (a answerFlags) Set(s string) error {
+	key, value, err := internal.ParseAnswer(s)
+	if err != nil {
+		return err
+	}
+	a[key] = value
+	return nil
+}
+
+// listFlag collects comma-separated values from a repeatable flag.
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// formatSize spells a byte count in the largest unit it fills, e.g. 1.5 MB.
+func formatSize(n int64) string {
+	for _, unit := range []struct {
+		name string
+		size int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}} {
+		if n >= unit.size {
+			return fmt.Sprintf("%.1f %s", float64(n)/float64(unit.size), unit.name)
+		}
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// pluralize picks the singular or plural form of a noun for count.
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// sizeFlag parses a byte size such as 1048576, 512KB or 64MB.
+type sizeFlag int64
+
+func (s *sizeFlag) String() string { return strconv.FormatInt(int64(*s), 10) }
+
+func (s *sizeFlag) Set(v string) error {
+	num, unit := v, int64(1)
+	for _, suffix := range []struct {
+		name string
+		size int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if trimmed, ok := strings.CutSuffix(strings.ToUpper(v), suffix.name); ok {
+			num, unit = trimmed, suffix.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(num), 10, 64)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("invalid size %q, expected e.g. 512KB or 64MB", v)
+	}
+	*s = sizeFlag(n * unit)
+	return nil
+}
+
+// cliOptions holds the generation options plus flags that main handles itself.
+type cliOptions struct {
+	internal.Options
+
+	// TemplateVersion compares the cached template version with upstream instead of generating
+	TemplateVersion bool
+	// ResolveOnly prints where the template source resolves to instead of generating
+	ResolveOnly bool
+}
+
+// parseArgs parses the template source, optional output directory, key=value answers and flags.
+// Flags may appear before, between or after the positional arguments.
+func parseArgs(args []string) (cliOptions, error) {
+	cli := cliOptions{Options: internal.Options{OutputDir: ".", Answers: map[string]string{}}}
+	opts := &cli.Options
+	flagAnswers := answerFlags{}
+	var configPath, answersPath string
+	var safe, force, skipExisting, interactiveConflicts bool
+	var ref, subdir string
+
+	fs := flag.NewFlagSet("kick", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&configPath, "config", "", "")
+	fs.StringVar(&opts.ChangedSince, "changed-since", "", "")
+	fs.BoolVar(&opts.Atomic, "atomic", false, "")
+	fs.BoolVar(&opts.AtomicDir, "atomic-dir", false, "")
+	fs.BoolVar(&opts.Explain, "explain", false, "")
+	fs.BoolVar(&force, "force", false, "")
+	fs.BoolVar(&skipExisting, "skip-existing", false, "")
+	fs.BoolVar(&interactiveConflicts, "interactive-conflicts", false, "")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "")
+	fs.BoolVar(&opts.Incremental, "incremental", false, "")
+	fs.BoolVar(&opts.InitGit, "init-git", false, "")
+	fs.StringVar(&opts.InitialCommit, "initial-commit", "", "")
+	fs.BoolVar(&opts.KeepGoing, "keep-going", false, "")
+	fs.BoolVar(&opts.Manifest, "manifest", false, "")
+	fs.BoolVar(&opts.NoCache, "no-cache", false, "")
+	fs.BoolVar(&opts.SkipHooks, "no-hooks", false, "")
+	fs.Var((*sizeFlag)(&opts.MaxFileSize), "max-file-size", "")
+	fs.BoolVar(&opts.Quick, "quick", false, "")
+	fs.StringVar(&ref, "ref", "", "")
+	fs.BoolVar(&opts.Refresh, "refresh", false, "")
+	fs.BoolVar(&opts.RequireClean, "require-clean", false, "")
+	fs.BoolVar(&cli.ResolveOnly, "resolve-only", false, "")
+	fs.BoolVar(&safe, "safe", false, "")
+	fs.BoolVar(&opts.StrictConfig, "strict", false, "")
+	fs.StringVar(&subdir, "subdir", "", "")
+	fs.BoolVar(&opts.Verbose, "verbose", false, "")
+	fs.StringVar(&opts.VersionFile, "version-file", "", "")
+	fs.StringVar(&opts.PromptStyle, "prompt-style", "", "")
+	fs.DurationVar(&opts.PromptTimeout, "prompt-timeout", 0, "")
+	fs.Var(flagAnswers, "answer", "")
+	fs.Var((*listFlag)(&opts.Only), "only", "")
+	fs.Var((*listFlag)(&opts.Skip), "skip", "")
+	fs.StringVar(&answersPath, "answers", "", "")
+	fs.StringVar(&opts.ExportAnswers, "export-answers", "", "")
+	fs.StringVar(&opts.Record, "record", "", "")
+	fs.StringVar(&opts.DumpContext, "dump-context", "", "")
+	fs.BoolVar(&cli.TemplateVersion, "template-version", false, "")
+	fs.BoolVar(&cli.TemplateVersion, "V", false, "")
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return cli, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) == 0 {
+		return cli, fmt.Errorf("missing template source")
+	}
+	source, err := internal.LookupTemplate(positional[0])
+	if err != nil {
+		return cli, err
+	}
+	source, err = internal.WithRef(internal.WithSubdir(source, subdir), ref)
+	if err != nil {
+		return cli, err
+	}
+	opts.Source = source
+	if opts.InitialCommit != "" {
+		opts.InitGit = true
+	}
+	for _, policy := range []struct {
+		set    bool
+		policy internal.ConflictPolicy
+	}{{force, internal.ConflictOverwrite}, {skipExisting, internal.ConflictSkip}, {interactiveConflicts, internal.ConflictPrompt}} {
+		if !policy.set {
+			continue
+		}
+		if opts.Conflicts != "" {
+			return cli, fmt.Errorf("--force, --skip-existing and --interactive-conflicts cannot be combined")
+		}
+		opts.Conflicts = policy.policy
+	}
+	if safe {
+		opts.SkipHooks = true
+		opts.LocalOnly = true
+		opts.StrictNames = true
+	}
+
+	opts.AnswersFile = answersPath
+
+	settings, err := internal.LoadSettings(configPath)
+	if err != nil {
+		return cli, err
+	}
+	applySettings(opts, settings, fs)
+
+	// After the source, arguments containing "=" are answers; the first other one is the output dir
+	outputSet := false
+	for _, arg := range positional[1:] {
+		if strings.Contains(arg, "=") {
+			key, value, err := internal.ParseAnswer(arg)
+			if err != nil {
+				return cli, err
+			}
+			opts.Answers[key] = value
+			continue
+		}
+		if outputSet {
+			return cli, fmt.Errorf("unexpected argument %q", arg)
+		}
+		opts.OutputDir = arg
+		outputSet = true
+	}
+
+	// Explicit --answer flags override positional answers
+	for key, value := range flagAnswers {
+		opts.Answers[key] = value
+	}
+
+	return cli, nil
+}
+
+// applySettings fills options from user-level settings wherever no flag was given.
+func applySettings(opts *internal.Options, settings internal.Settings, fs *flag.FlagSet) {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	opts.GitToken = settings.GitToken
+	opts.CacheDir = settings.CacheDir
+	if opts.Conflicts == "" {
+		// --force, --skip-existing and --interactive-conflicts set a policy already
+		opts.Conflicts = settings.Conflicts
+	}
+	if !set["incremental"] {
+		opts.Incremental = settings.Incremental
+	}
+	if !set["prompt-style"] {
+		opts.PromptStyle = settings.PromptStyle
+	}
+	if !set["version-file"] {
+		opts.VersionFile = settings.VersionFile
+	}
+	opts.SettingsAnswers = settings.Answers
+	opts.HookPolicy = settings.HookPolicy
+}
+
 func usage() {
 	_, _ = fmt.Fprintf(os.Stdout, `kick – kickstart projects from templates
 
 Usage:
-  kick <template> [output_dir]
+  kick <template> [output_dir] [key=value ...] [flags]
+  kick init [dir]
+  kick lint <template>
+  kick verify [output_dir]
+  kick update [output_dir] [key=value ...] [--ref ref] [--dry-run]
+  kick changelog <old_template> <new_template>
+  kick render '<template string>' [key=value ...] [--answer k=v] [--answers file]
+  kick docs <template> [--format md|table]
+  kick cache list|clean [template]
+  kick list
+  kick add <name> <template>
+  kick remove <name>
+  kick --shell-completion bash|zsh|fish
 
 <template> can be:
   - local directory path
   - git URL (https/ssh) or something ending in .git (cloned in-process)
+  - name of a template registered with kick add
 
 Template expects %s at the root with variables.
 
+Commands:
+  init            create a skeleton template with example variables, hooks
+                  and a README
+  lint            check a template for common authoring mistakes
+  verify          list generated files modified since generation
+  update          merge the latest version of the project's template into a
+                  generated project, keeping changes made since generation
+  changelog       list variables added, removed or changed between two
+                  versions of a template
+  render          render a template string with the given answers, for
+                  trying out template functions
+  functions       list the functions available in templates
+  docs            print a table of the template's variables, as Markdown
+                  (default) or with --format table as aligned text
+  cache           list cached git templates, or clean all of them or the
+                  given template's clone
+  list            list the templates registered by name
+  add             register a template under a short name, e.g.
+                  kick add go-service gh://my-org/go-service
+  remove          remove a registered template
+
+Flags:
+  --answer k=v    answer a variable without prompting (repeatable);
+                  overrides positional key=value answers
+  --answers file  pre-fill variables from a YAML or JSON file
+  --atomic        render every file before writing any, so a template error
+                  leaves the output directory untouched
+  --atomic-dir    generate and run post-generation hooks in a staging
+                  directory, moved into place only when everything succeeded
+  --changed-since ref
+                  render only template files added or modified since the
+                  template ref (branch, tag or commit)
+  --config path   user settings file (default ~/.config/kick/%s)
+  --dry-run       render the template but write nothing and run no hooks;
+                  print a tree of the files that would be created (+),
+                  overwritten (~), left unchanged (=) or skipped (-)
+  --dump-context file
+                  when rendering or a hook fails, write the data the template
+                  received to file as YAML (- for stderr), secrets redacted
+  --explain       print what happened to every template file and the rule
+                  that decided it, e.g. the ignore pattern that dropped it
+  --export-answers file
+                  write the effective answers to file for a later --answers run
+  --force         overwrite existing files that differ from the template;
+                  by default kick stops and writes nothing
+  --incremental   only write files whose rendered content changed since the
+                  last run (tracked in %s)
+  --init-git      run git init in the output directory after generation
+  --initial-commit message
+                  like --init-git, then commit the generated files
+  --interactive-conflicts
+                  ask whether to overwrite each existing file that differs
+                  from the template
+  --keep-going    render the remaining files after a render error and
+                  report every failure at the end
+  --manifest      write checksums of generated files to %s
+  --max-file-size size
+                  copy files larger than size (e.g. 512KB, 64MB) without
+                  rendering them (default 64MB)
+  --only tags     render only tagged files with one of these comma-separated
+                  tags (untagged files are always rendered)
+  --skip tags     leave out files with any of these comma-separated tags
+  --skip-existing keep existing files that differ from the template
+  --prompt-style style
+                  stepped (default) or compact single-line prompts
+  --prompt-timeout duration
+                  abort when a prompt gets no answer within duration (e.g. 30s)
+  --quick         only prompt for basic variables; advanced ones take
+                  their defaults
+  --record file   write the answers, without secrets, to file before
+                  rendering, to replay the run later with --answers
+  --ref ref       clone a git template at this branch, tag or commit SHA
+                  instead of its default branch
+  --refresh       clone a git template again instead of using the cached copy
+  --no-cache      clone a git template into a temporary directory, neither
+                  reading nor writing the cache
+  --no-hooks      run no pre- or post-generation hooks, nor --init-git
+  --require-clean refuse to generate into a git working tree with
+                  uncommitted changes
+  --resolve-only  print the template's URL, default branch and local path
+                  without generating
+  --safe          evaluate an untrusted template: run no hooks, accept only
+                  local template paths and reject file names that render
+                  to a path separator
+  --shell-completion shell
+                  print a completion script for bash, zsh or fish
+  --strict        reject a kick.yaml with unknown keys, e.g. a misspelled
+                  "varaibles:"
+  --subdir dir    use the template in dir of the source, e.g. one of several
+                  templates in a git repository
+  --verbose       show where each answer came from (default, prompt,
+                  answers file, settings or command line)
+  --version-file name
+                  record the template name, version, source and commit in
+                  name (e.g. %s) in the output directory
+  -V, --template-version
+                  compare the cached copy of a git template with upstream
+                  and offer to refresh it
+
 Example:
   kick gh://my-org/service-template ./my-service
   kick /path/to/template ./out
+  kick ./template ./out project_name=demo port=8080
 
-`, internal.KickYAML)
+`, internal.KickYAML, internal.SettingsFile, internal.ManifestFile, internal.ManifestFile, internal.VersionFile)
 }
 
 func hasHelpFlag(args []string) bool {
@@ -0,0 +1,66 @@
+package prompts
+
+import "github.com/yarlson/tap/internal/core"
+
+// Type aliases for convenience
+
+type Reader = core.Reader
+type Writer = core.Writer
+
+// TextOptions defines options for styled text prompt
+type TextOptions struct {
+	Message      string
+	Placeholder  string
+	DefaultValue string
+	InitialValue string
+	Validate     func(string) error
+	Input        core.Reader
+	Output       core.Writer
+}
+
+// PasswordOptions defines options for styled password prompt
+type PasswordOptions struct {
+	Message      string
+	DefaultValue string
+	InitialValue string
+	Validate     func(string) error
+	Input        core.Reader
+	Output       core.Writer
+}
+
+// ConfirmOptions defines options for styled confirm prompt
+type ConfirmOptions struct {
+	Message      string
+	Active       string
+	Inactive     string
+	InitialValue bool
+	Input        core.Reader
+	Output       core.Writer
+}
+
+// SelectOption represents an option in a styled select prompt
+type SelectOption[T any] struct {
+	Value T
+	Label string
+	Hint  string
+}
+
+// SelectOptions defines options for styled select prompt
+type SelectOptions[T any] struct {
+	Message      string
+	Options      []SelectOption[T]
+	InitialValue *T
+	MaxItems     *int
+	Input        core.Reader
+	Output       core.Writer
+}
+
+// MultiSelectOptions defines options for styled multi-select prompt
+type MultiSelectOptions[T any] struct {
+	Message       string
+	Options       []SelectOption[T]
+	InitialValues []T
+	MaxItems      *int
+	Input         core.Reader
+	Output        core.Writer
+}
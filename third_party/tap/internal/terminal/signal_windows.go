@@ -0,0 +1,14 @@
+//go:build windows
+
+package terminal
+
+import "os"
+
+// reraise ends the process, as the default handling of sig would: Windows cannot send a
+// signal to a process.
+func reraise(os.Signal) {
+	os.Exit(1)
+}
+
+// notifyResize does nothing: Windows has no resize signal.
+func notifyResize(chan<- os.Signal) {}